@@ -27,6 +27,22 @@ func InitCron() {
 		log.Fatal("Error adding monthly cron job: ", err)
 	}
 
+	// Low-attendance alerts - Every day at 6am
+	_, err = c.AddFunc("0 6 * * *", func() {
+		service.EvaluateAttendanceAlerts()
+	})
+	if err != nil {
+		log.Fatal("Error adding attendance alert cron job: ", err)
+	}
+
+	// Close ended sessions and mark their absentees - Every 5 minutes
+	_, err = c.AddFunc("*/5 * * * *", func() {
+		service.CloseEndedSessions()
+	})
+	if err != nil {
+		log.Fatal("Error adding session closing cron job: ", err)
+	}
+
 	c.Start()
 	log.Println("Cron scheduler started")
 }
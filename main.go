@@ -7,6 +7,7 @@ import (
 	cronjob "github.com/shravanasati/scopex-go-assignment/cronjob"
 	docs "github.com/shravanasati/scopex-go-assignment/docs"
 	router "github.com/shravanasati/scopex-go-assignment/router"
+	service "github.com/shravanasati/scopex-go-assignment/service"
 	util "github.com/shravanasati/scopex-go-assignment/util"
 
 	"github.com/spf13/viper"
@@ -37,6 +38,11 @@ func main() {
 	}
 	defer configuration.DB.Close()
 
+	// Load the attendance status catalogue used for validation
+	if err := service.LoadAttendanceStatuses(); err != nil {
+		log.Println("Using default attendance statuses: ", err)
+	}
+
 	// Start Cron Jobs
 	cronjob.InitCron()
 
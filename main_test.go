@@ -156,6 +156,37 @@ func TestAPILogoutUser(t *testing.T) {
 
 }
 
+func TestAPIRefreshToken(t *testing.T) {
+	requireDB(t)
+	payloadLogin := []byte(`{"username":"admin", "password":"admin1234"}`)
+
+	reqLogin, _ := http.NewRequest("POST", "/api/login", bytes.NewBuffer(payloadLogin))
+	respLogin := executeRequest(reqLogin)
+
+	checkResponseCode(t, http.StatusOK, respLogin.Code)
+
+	var mLogin map[string]interface{}
+	json.Unmarshal(respLogin.Body.Bytes(), &mLogin)
+
+	payload := []byte(fmt.Sprintf(`{"refreshToken":"%v"}`, mLogin["refreshToken"]))
+
+	req, _ := http.NewRequest("POST", "/api/token/refresh", bytes.NewBuffer(payload))
+	resp := executeRequest(req)
+
+	checkResponseCode(t, http.StatusOK, resp.Code)
+
+	var m map[string]interface{}
+	json.Unmarshal(resp.Body.Bytes(), &m)
+
+	assert.NotEqual(t, mLogin["refreshToken"], m["refreshToken"])
+
+	// the exchanged token cannot be used again
+	req, _ = http.NewRequest("POST", "/api/token/refresh", bytes.NewBuffer(payload))
+	resp = executeRequest(req)
+
+	checkResponseCode(t, http.StatusUnauthorized, resp.Code)
+}
+
 func TestAPICreateUser(t *testing.T) {
 	requireDB(t)
 	// doing login first to get token
@@ -173,7 +204,7 @@ func TestAPICreateUser(t *testing.T) {
 	tokenString := fmt.Sprintf("Bearer %v", token)
 	// ----------------------------------------------
 
-	payload := []byte(`{"userName":"wiro", "password":"pass345", "accountExpired":false, "accountLocked":false, "credentialsExpired":false, "enabled":true}`)
+	payload := []byte(`{"userName":"wiro", "password":"pass3456", "accountExpired":false, "accountLocked":false, "credentialsExpired":false, "enabled":true}`)
 
 	req, _ := http.NewRequest("POST", "/api/user/", bytes.NewBuffer(payload))
 	req.Header.Set("Authorization", tokenString)
@@ -268,6 +299,39 @@ func TestAPIGetAllUser(t *testing.T) {
 	checkResponseCode(t, http.StatusOK, response.Code)
 }
 
+func TestAPIRefreshAfterUserLocked(t *testing.T) {
+	requireDB(t)
+
+	// wiro, created by TestAPICreateUser, logs in
+	payloadLogin := []byte(`{"username":"wiro", "password":"pass3456"}`)
+	reqLogin, _ := http.NewRequest("POST", "/api/login", bytes.NewBuffer(payloadLogin))
+	respLogin := executeRequest(reqLogin)
+	checkResponseCode(t, http.StatusOK, respLogin.Code)
+
+	var mLogin map[string]interface{}
+	json.Unmarshal(respLogin.Body.Bytes(), &mLogin)
+
+	// the admin locks the account
+	payloadAdmin := []byte(`{"username":"admin", "password":"admin1234"}`)
+	reqAdmin, _ := http.NewRequest("POST", "/api/login", bytes.NewBuffer(payloadAdmin))
+	respAdmin := executeRequest(reqAdmin)
+	checkResponseCode(t, http.StatusOK, respAdmin.Code)
+
+	var mAdmin map[string]interface{}
+	json.Unmarshal(respAdmin.Body.Bytes(), &mAdmin)
+
+	req, _ := http.NewRequest("PUT", "/api/user/"+strconv.FormatInt(userID, 10), bytes.NewBuffer([]byte(`{"accountLocked":true}`)))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", mAdmin["accessToken"]))
+	resp := executeRequest(req)
+	checkResponseCode(t, http.StatusOK, resp.Code)
+
+	// wiro's session ended with the lock
+	payload := []byte(fmt.Sprintf(`{"refreshToken":"%v"}`, mLogin["refreshToken"]))
+	req, _ = http.NewRequest("POST", "/api/token/refresh", bytes.NewBuffer(payload))
+	resp = executeRequest(req)
+	checkResponseCode(t, http.StatusUnauthorized, resp.Code)
+}
+
 func TestAPIDeleteByID(t *testing.T) {
 	requireDB(t)
 
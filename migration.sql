@@ -1,6 +1,29 @@
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
+DROP TABLE IF EXISTS teacher_departments;
+DROP TABLE IF EXISTS teachers;
 DROP TABLE IF EXISTS `m_user`;
+DROP TABLE IF EXISTS attendance_flags;
+DROP TABLE IF EXISTS attendance_lock_overrides;
+DROP TABLE IF EXISTS attendance_audit;
 DROP TABLE IF EXISTS attendance;
+DROP TABLE IF EXISTS leave_requests;
+DROP TABLE IF EXISTS attendance_alerts;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS timetable_entries;
+DROP TABLE IF EXISTS enrollments;
+DROP TABLE IF EXISTS sections;
+DROP TABLE IF EXISTS courses;
 DROP TABLE IF EXISTS students;
+DROP TABLE IF EXISTS department_aliases;
+DROP TABLE IF EXISTS departments;
+DROP TABLE IF EXISTS attendance_statuses;
+DROP TABLE IF EXISTS terms;
+DROP TABLE IF EXISTS holidays;
+DROP TABLE IF EXISTS weekend_days;
+DROP TABLE IF EXISTS locations;
 /*!40101 SET @saved_cs_client     = @@character_set_client */;
 /*!40101 SET character_set_client = utf8 */;
 CREATE TABLE `m_user` (
@@ -11,8 +34,10 @@ CREATE TABLE `m_user` (
   `ACCOUNT_LOCKED` tinyint(1) DEFAULT NULL,
   `CREDENTIALS_EXPIRED` tinyint(1) DEFAULT NULL,
   `ENABLED` tinyint(1) DEFAULT NULL,
+  `EMAIL` varchar(255) DEFAULT NULL,
   PRIMARY KEY (`ID`),
-  UNIQUE KEY `USER_USER_NAME` (`USER_NAME`)
+  UNIQUE KEY `USER_USER_NAME` (`USER_NAME`),
+  UNIQUE KEY `USER_EMAIL` (`EMAIL`)
 ) ENGINE=InnoDB AUTO_INCREMENT=7 DEFAULT CHARSET=utf8;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
@@ -22,28 +47,360 @@ CREATE TABLE `m_user` (
 
 LOCK TABLES `m_user` WRITE;
 /*!40000 ALTER TABLE `m_user` DISABLE KEYS */;
-INSERT INTO `m_user` VALUES (1,'$2a$08$qvrzQZ7jJ7oy2p/msL4M0.l83Cd0jNsX6AJUitbgRXGzge4j035ha','admin',0,0,0,1),(2,'$2a$08$dwYz8O.qtUXboGosJFsS4u19LHKW7aCQ0LXXuNlRfjjGKwj5NfKSe','budi',0,0,0,1),(3,'$2a$08$kPjzxewXRGNRiIuL4FtQH.mhMn7ZAFBYKB3ROz.J24IX8vDAcThsG','anduk',0,0,0,1),(4,'$2a$08$vVXqh6S8TqfHMs1SlNTu/.J25iUCrpGBpyGExA.9yI.IlDRadR6Ea','haya',0,0,0,1);
+INSERT INTO `m_user` VALUES (1,'$2a$08$qvrzQZ7jJ7oy2p/msL4M0.l83Cd0jNsX6AJUitbgRXGzge4j035ha','admin',0,0,0,1,'admin@scopex.local'),(2,'$2a$08$dwYz8O.qtUXboGosJFsS4u19LHKW7aCQ0LXXuNlRfjjGKwj5NfKSe','budi',0,0,0,1,'budi@scopex.local'),(3,'$2a$08$kPjzxewXRGNRiIuL4FtQH.mhMn7ZAFBYKB3ROz.J24IX8vDAcThsG','anduk',0,0,0,1,'anduk@scopex.local'),(4,'$2a$08$vVXqh6S8TqfHMs1SlNTu/.J25iUCrpGBpyGExA.9yI.IlDRadR6Ea','haya',0,0,0,1,'haya@scopex.local');
 /*!40000 ALTER TABLE `m_user` ENABLE KEYS */;
 UNLOCK TABLES;
 
+CREATE TABLE roles (
+    code VARCHAR(32) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE permissions (
+    code VARCHAR(64) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE role_permissions (
+    role_code VARCHAR(32) NOT NULL,
+    permission_code VARCHAR(64) NOT NULL,
+    PRIMARY KEY (role_code, permission_code),
+    FOREIGN KEY (role_code) REFERENCES roles(code) ON DELETE CASCADE,
+    FOREIGN KEY (permission_code) REFERENCES permissions(code) ON DELETE CASCADE
+);
+
+CREATE TABLE user_roles (
+    user_id BIGINT NOT NULL,
+    role_code VARCHAR(32) NOT NULL,
+    PRIMARY KEY (user_id, role_code),
+    FOREIGN KEY (user_id) REFERENCES m_user(ID) ON DELETE CASCADE,
+    FOREIGN KEY (role_code) REFERENCES roles(code)
+);
+
+INSERT INTO roles (code, description) VALUES
+    ('admin', 'Full access, including overriding the attendance lock'),
+    ('teacher', 'Manages and marks the students of their departments'),
+    ('student', 'Requests leave and reads the calendar and timetable'),
+    ('auditor', 'Read-only access to attendance, reports and audit logs');
+
+INSERT INTO permissions (code, description) VALUES
+    ('users:read', 'List and view login accounts'),
+    ('users:write', 'Create and update login accounts'),
+    ('users:delete', 'Delete login accounts'),
+    ('roles:read', 'List roles and the roles of a user'),
+    ('roles:write', 'Assign roles to users'),
+    ('teachers:read', 'List and view teacher profiles'),
+    ('teachers:write', 'Create, update and delete teacher profiles'),
+    ('students:read', 'List and view students'),
+    ('students:write', 'Create and update students'),
+    ('students:delete', 'Delete students'),
+    ('attendance:read', 'View attendance, its history and the attendance statuses'),
+    ('attendance:mark', 'Mark attendance and issue check-in codes'),
+    ('attendance:checkin', 'Check in to a session with its QR code'),
+    ('attendance:correct', 'Correct marked attendance'),
+    ('attendance:delete', 'Delete attendance records'),
+    ('attendance:import', 'Import attendance from CSV'),
+    ('attendance:export', 'Export attendance'),
+    ('attendance:review', 'Review geofence flags'),
+    ('attendance:audit', 'View the lock override audit log'),
+    ('attendance:override_lock', 'Change attendance inside the lock window'),
+    ('attendance_statuses:write', 'Manage the attendance statuses'),
+    ('reports:read', 'View attendance reports and summaries'),
+    ('sessions:read', 'List and view sessions'),
+    ('sessions:write', 'Create and delete sessions'),
+    ('calendar:read', 'View terms, holidays and working days'),
+    ('calendar:write', 'Manage terms, holidays and the weekend'),
+    ('leave:read', 'List and view leave requests'),
+    ('leave:request', 'Request and cancel leave'),
+    ('leave:decide', 'Approve and reject leave requests'),
+    ('alerts:read', 'List low-attendance alerts'),
+    ('locations:read', 'List and view geofence locations'),
+    ('locations:write', 'Manage geofence locations'),
+    ('departments:read', 'List and view departments'),
+    ('departments:write', 'Manage departments'),
+    ('courses:read', 'List and view courses, sections and enrollments'),
+    ('courses:write', 'Manage courses, sections and enrollments'),
+    ('timetable:read', 'View the timetable'),
+    ('timetable:write', 'Manage the timetable and generate sessions');
+
+INSERT INTO role_permissions (role_code, permission_code)
+    SELECT 'admin', code FROM permissions;
+
+INSERT INTO role_permissions (role_code, permission_code) VALUES
+    ('teacher', 'teachers:read'),
+    ('teacher', 'students:read'),
+    ('teacher', 'students:write'),
+    ('teacher', 'students:delete'),
+    ('teacher', 'attendance:read'),
+    ('teacher', 'attendance:mark'),
+    ('teacher', 'attendance:review'),
+    ('teacher', 'reports:read'),
+    ('teacher', 'sessions:read'),
+    ('teacher', 'sessions:write'),
+    ('teacher', 'calendar:read'),
+    ('teacher', 'leave:read'),
+    ('teacher', 'leave:decide'),
+    ('teacher', 'alerts:read'),
+    ('teacher', 'locations:read'),
+    ('teacher', 'departments:read'),
+    ('teacher', 'courses:read'),
+    ('teacher', 'timetable:read'),
+    ('student', 'attendance:checkin'),
+    ('student', 'leave:request'),
+    ('student', 'sessions:read'),
+    ('student', 'calendar:read'),
+    ('student', 'courses:read'),
+    ('student', 'timetable:read');
+
+INSERT INTO role_permissions (role_code, permission_code)
+    SELECT 'auditor', code FROM permissions
+    WHERE code LIKE '%:read' AND code NOT IN ('users:read', 'roles:read')
+       OR code IN ('attendance:export', 'attendance:audit');
+
+INSERT INTO user_roles (user_id, role_code) VALUES
+    (1, 'admin'),
+    (2, 'teacher'),
+    (3, 'teacher'),
+    (4, 'auditor');
+
+
+CREATE TABLE departments (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- alternative spellings that resolve to a department
+CREATE TABLE department_aliases (
+    alias VARCHAR(255) PRIMARY KEY,
+    department_id BIGINT NOT NULL,
+    FOREIGN KEY (department_id) REFERENCES departments(id) ON DELETE CASCADE
+);
+
+-- teacher profile of an m_user account
+CREATE TABLE teachers (
+    user_id BIGINT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES m_user(ID) ON DELETE CASCADE
+);
+
+CREATE TABLE teacher_departments (
+    user_id BIGINT NOT NULL,
+    department_id BIGINT NOT NULL,
+    PRIMARY KEY (user_id, department_id),
+    FOREIGN KEY (user_id) REFERENCES teachers(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (department_id) REFERENCES departments(id) ON DELETE CASCADE
+);
 
 CREATE TABLE students (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
     name VARCHAR(255) NOT NULL,
     email VARCHAR(255) NOT NULL UNIQUE,
-    department VARCHAR(255),
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    department_id BIGINT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (department_id) REFERENCES departments(id)
 );
 
+CREATE TABLE attendance_statuses (
+    code VARCHAR(32) PRIMARY KEY,
+    label VARCHAR(255) NOT NULL,
+    counts_as_present TINYINT(1) NOT NULL DEFAULT 0,
+    -- excused statuses count neither as present nor as absent
+    excused TINYINT(1) NOT NULL DEFAULT 0,
+    sort_order INT NOT NULL DEFAULT 0
+);
+
+INSERT INTO attendance_statuses (code, label, counts_as_present, excused, sort_order) VALUES
+    ('Present', 'Present', 1, 0, 1),
+    ('Absent', 'Absent', 0, 0, 2),
+    ('Late', 'Late', 1, 0, 3),
+    ('Excused', 'Excused', 0, 1, 4),
+    ('HalfDay', 'Half-day', 1, 0, 5),
+    ('OnLeave', 'On leave', 0, 1, 6),
+    ('Flagged', 'Flagged for review', 0, 0, 7);
+
+CREATE TABLE courses (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    department_id BIGINT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (department_id) REFERENCES departments(id)
+);
+
+CREATE TABLE sections (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    course_id BIGINT NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (course_id) REFERENCES courses(id),
+    UNIQUE KEY unique_section (course_id, name)
+);
+
+CREATE TABLE enrollments (
+    section_id BIGINT NOT NULL,
+    student_id BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (section_id, student_id),
+    FOREIGN KEY (section_id) REFERENCES sections(id) ON DELETE CASCADE,
+    FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE
+);
+
+-- weekday follows Go's time.Weekday: 0 is Sunday, 6 is Saturday
+CREATE TABLE timetable_entries (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    section_id BIGINT NOT NULL,
+    weekday TINYINT NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    room VARCHAR(64) NOT NULL DEFAULT '',
+    teacher VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (section_id) REFERENCES sections(id) ON DELETE CASCADE
+);
+
+CREATE TABLE sessions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    date DATE NOT NULL,
+    period INT NOT NULL DEFAULT 0,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    section_id BIGINT NULL,
+    -- department of a session without a section; a section's sessions belong to its course's department
+    department_id BIGINT NULL,
+    timetable_entry_id BIGINT NULL,
+    closed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (section_id) REFERENCES sections(id),
+    FOREIGN KEY (department_id) REFERENCES departments(id),
+    FOREIGN KEY (timetable_entry_id) REFERENCES timetable_entries(id) ON DELETE SET NULL,
+    UNIQUE KEY unique_generated_session (timetable_entry_id, date)
+);
+
+-- session_id is 0 for whole-day attendance
 CREATE TABLE attendance (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
     student_id BIGINT NOT NULL,
     date DATE NOT NULL,
-    status ENUM('Present', 'Absent') NOT NULL,
+    session_id BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(32) NOT NULL,
+    -- review state of a geofence flag: pending, approved or rejected
+    flag VARCHAR(16) NULL,
     FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE,
-    UNIQUE KEY unique_attendance (student_id, date)
+    FOREIGN KEY (status) REFERENCES attendance_statuses(code),
+    UNIQUE KEY unique_attendance (student_id, date, session_id)
 );
 
+CREATE TABLE attendance_audit (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    attendance_id BIGINT NOT NULL,
+    student_id BIGINT NOT NULL,
+    date DATE NOT NULL,
+    action ENUM('update', 'delete') NOT NULL,
+    old_status VARCHAR(32) NOT NULL,
+    new_status VARCHAR(32),
+    changed_by BIGINT NOT NULL,
+    changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE leave_requests (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    student_id BIGINT NOT NULL,
+    from_date DATE NOT NULL,
+    to_date DATE NOT NULL,
+    reason VARCHAR(1024) NOT NULL,
+    state VARCHAR(16) NOT NULL DEFAULT 'pending',
+    requested_by BIGINT NOT NULL,
+    decided_by BIGINT NULL,
+    decision_note VARCHAR(1024) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    decided_at TIMESTAMP NULL,
+    FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE
+);
+
+CREATE TABLE attendance_alerts (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    student_id BIGINT NOT NULL,
+    window_start DATE NOT NULL,
+    window_end DATE NOT NULL,
+    present_count INT NOT NULL,
+    absent_count INT NOT NULL,
+    percentage DECIMAL(5,2) NOT NULL,
+    threshold DECIMAL(5,2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP NULL,
+    FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE
+);
+
+CREATE TABLE locations (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    latitude DECIMAL(9,6) NOT NULL,
+    longitude DECIMAL(9,6) NOT NULL,
+    radius_meters INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE attendance_flags (
+    attendance_id BIGINT PRIMARY KEY,
+    requested_status VARCHAR(32) NOT NULL,
+    location_id BIGINT NULL,
+    latitude DECIMAL(9,6) NOT NULL,
+    longitude DECIMAL(9,6) NOT NULL,
+    distance_meters INT NOT NULL,
+    reviewed_by BIGINT NULL,
+    review_note VARCHAR(1024) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP NULL,
+    FOREIGN KEY (attendance_id) REFERENCES attendance(id) ON DELETE CASCADE,
+    FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE SET NULL
+);
+
+-- attendance_id is kept after the record is deleted, like attendance_audit
+CREATE TABLE attendance_lock_overrides (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    action VARCHAR(16) NOT NULL,
+    attendance_id BIGINT NOT NULL,
+    student_id BIGINT NOT NULL,
+    date DATE NOT NULL,
+    session_id BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(32) NOT NULL,
+    user_id BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE terms (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL
+);
+
+CREATE TABLE holidays (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    date DATE NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL
+);
+
+-- weekday follows Go's time.Weekday: 0 is Sunday, 6 is Saturday
+CREATE TABLE weekend_days (
+    weekday TINYINT PRIMARY KEY
+);
+
+INSERT INTO weekend_days (weekday) VALUES (0), (6);
+
 CREATE INDEX idx_students_email ON students(email);
 CREATE INDEX idx_students_id ON students(id);
 CREATE INDEX idx_attendance_student_date ON attendance(student_id, date);
+CREATE INDEX idx_attendance_audit_attendance ON attendance_audit(attendance_id);
+CREATE INDEX idx_sessions_date ON sessions(date);
+CREATE INDEX idx_attendance_alerts_student ON attendance_alerts(student_id, resolved_at);
+CREATE INDEX idx_leave_requests_student_dates ON leave_requests(student_id, from_date, to_date);
+CREATE INDEX idx_attendance_flag ON attendance(flag);
+CREATE INDEX idx_enrollments_student ON enrollments(student_id);
+CREATE INDEX idx_sessions_closed ON sessions(closed_at, date);
@@ -0,0 +1,89 @@
+-- One-off upgrade for databases created before departments were a table.
+-- migration.sql already creates the new schema; run this file instead on a
+-- database whose students (and courses) still hold free-text departments:
+--
+--   docker compose exec -T db \
+--     sh -c "mysql -uhomestead -p!Secret1234 scopex-assignment" < migration_departments.sql
+
+CREATE TABLE departments (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE department_aliases (
+    alias VARCHAR(255) PRIMARY KEY,
+    department_id BIGINT NOT NULL,
+    FOREIGN KEY (department_id) REFERENCES departments(id) ON DELETE CASCADE
+);
+
+-- Step 1: the mapping. List every free-text spelling in use together with
+-- the department it stands for; find them with
+--   SELECT department, COUNT(*) FROM students GROUP BY department;
+-- Spellings left out become departments of their own in step 2.
+CREATE TEMPORARY TABLE department_mapping (
+    free_text VARCHAR(255) PRIMARY KEY,
+    code VARCHAR(32) NOT NULL,
+    name VARCHAR(255) NOT NULL
+);
+
+INSERT INTO department_mapping (free_text, code, name) VALUES
+    ('CS', 'CS', 'Computer Science'),
+    ('Comp Sci', 'CS', 'Computer Science'),
+    ('Computer Science', 'CS', 'Computer Science');
+
+-- Step 2: create the mapped departments, then one per unmapped spelling
+INSERT IGNORE INTO departments (code, name)
+    SELECT DISTINCT code, name FROM department_mapping;
+
+INSERT IGNORE INTO departments (code, name)
+    SELECT DISTINCT LEFT(UPPER(REPLACE(TRIM(s.department), ' ', '_')), 32), TRIM(s.department)
+    FROM students s
+    WHERE TRIM(COALESCE(s.department, '')) <> ''
+      AND TRIM(s.department) NOT IN (SELECT free_text FROM department_mapping);
+
+-- Step 3: keep the mapped spellings as aliases so the API still accepts them
+INSERT IGNORE INTO department_aliases (alias, department_id)
+    SELECT m.free_text, d.id
+    FROM department_mapping m
+    JOIN departments d ON d.code = m.code
+    WHERE m.free_text <> d.name;
+
+-- Step 4: point students and courses at their departments
+ALTER TABLE students
+    ADD COLUMN department_id BIGINT NULL AFTER email,
+    ADD FOREIGN KEY (department_id) REFERENCES departments(id);
+
+UPDATE students s
+    JOIN department_mapping m ON m.free_text = TRIM(s.department)
+    JOIN departments d ON d.code = m.code
+    SET s.department_id = d.id;
+
+UPDATE students s
+    JOIN departments d ON d.name = TRIM(s.department)
+    SET s.department_id = d.id
+    WHERE s.department_id IS NULL;
+
+ALTER TABLE courses
+    ADD COLUMN department_id BIGINT NULL AFTER name,
+    ADD FOREIGN KEY (department_id) REFERENCES departments(id);
+
+UPDATE courses c
+    JOIN department_aliases a ON a.alias = TRIM(c.department)
+    SET c.department_id = a.department_id;
+
+UPDATE courses c
+    JOIN departments d ON d.name = TRIM(c.department) OR d.code = TRIM(c.department)
+    SET c.department_id = d.id
+    WHERE c.department_id IS NULL;
+
+DROP TEMPORARY TABLE department_mapping;
+
+-- Step 5: list what could not be mapped (e.g. two spellings that derived the
+-- same code) and fix it by hand. The application no longer reads the
+-- free-text columns; drop them once both queries come back empty:
+--   ALTER TABLE students DROP COLUMN department;
+--   ALTER TABLE courses DROP COLUMN department;
+SELECT id, email, department FROM students WHERE department_id IS NULL AND TRIM(COALESCE(department, '')) <> '';
+SELECT id, code, department FROM courses WHERE department_id IS NULL AND TRIM(department) <> '';
@@ -0,0 +1,436 @@
+-- One-off upgrade for databases created from the original schema, where
+-- attendance is still unique per student and date, statuses are an ENUM and
+-- students hold a free-text department. migration.sql already creates the
+-- new schema; run this file instead to bring such a database to the same
+-- schema without losing its users, students and attendance:
+--
+--   docker compose exec -T db \
+--     sh -c "mysql -uhomestead -p!Secret1234 scopex-assignment" < migration_sessions.sql
+--
+-- Databases that already have sessions and courses but still hold free-text
+-- departments are upgraded with migration_departments.sql instead.
+
+-- Attendance corrections and deletions (user-002)
+CREATE TABLE attendance_audit (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    attendance_id BIGINT NOT NULL,
+    student_id BIGINT NOT NULL,
+    date DATE NOT NULL,
+    action ENUM('update', 'delete') NOT NULL,
+    old_status VARCHAR(32) NOT NULL,
+    new_status VARCHAR(32),
+    changed_by BIGINT NOT NULL,
+    changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_attendance_audit_attendance ON attendance_audit(attendance_id);
+
+-- Status catalogue (user-004), including the workflow statuses of leave
+-- (user-009) and geofence flags (user-014). Existing Present and Absent
+-- marks keep their codes.
+CREATE TABLE attendance_statuses (
+    code VARCHAR(32) PRIMARY KEY,
+    label VARCHAR(255) NOT NULL,
+    counts_as_present TINYINT(1) NOT NULL DEFAULT 0,
+    -- excused statuses count neither as present nor as absent
+    excused TINYINT(1) NOT NULL DEFAULT 0,
+    sort_order INT NOT NULL DEFAULT 0
+);
+
+INSERT INTO attendance_statuses (code, label, counts_as_present, excused, sort_order) VALUES
+    ('Present', 'Present', 1, 0, 1),
+    ('Absent', 'Absent', 0, 0, 2),
+    ('Late', 'Late', 1, 0, 3),
+    ('Excused', 'Excused', 0, 1, 4),
+    ('HalfDay', 'Half-day', 1, 0, 5),
+    ('OnLeave', 'On leave', 0, 1, 6),
+    ('Flagged', 'Flagged for review', 0, 0, 7);
+
+ALTER TABLE attendance MODIFY status VARCHAR(32) NOT NULL;
+
+ALTER TABLE attendance ADD FOREIGN KEY (status) REFERENCES attendance_statuses(code);
+
+-- Sessions (user-005). Existing marks become whole-day attendance
+-- (session_id 0). The foreign key on student_id keeps using
+-- idx_attendance_student_date once the old key goes.
+CREATE TABLE sessions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    date DATE NOT NULL,
+    period INT NOT NULL DEFAULT 0,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sessions_date ON sessions(date);
+
+ALTER TABLE attendance
+    ADD COLUMN session_id BIGINT NOT NULL DEFAULT 0 AFTER date,
+    DROP INDEX unique_attendance,
+    ADD UNIQUE KEY unique_attendance (student_id, date, session_id);
+
+-- Academic calendar (user-008)
+CREATE TABLE terms (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL
+);
+
+CREATE TABLE holidays (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    date DATE NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL
+);
+
+-- weekday follows Go's time.Weekday: 0 is Sunday, 6 is Saturday
+CREATE TABLE weekend_days (
+    weekday TINYINT PRIMARY KEY
+);
+
+INSERT INTO weekend_days (weekday) VALUES (0), (6);
+
+-- Leave requests (user-009)
+CREATE TABLE leave_requests (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    student_id BIGINT NOT NULL,
+    from_date DATE NOT NULL,
+    to_date DATE NOT NULL,
+    reason VARCHAR(1024) NOT NULL,
+    state VARCHAR(16) NOT NULL DEFAULT 'pending',
+    requested_by BIGINT NOT NULL,
+    decided_by BIGINT NULL,
+    decision_note VARCHAR(1024) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    decided_at TIMESTAMP NULL,
+    FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_leave_requests_student_dates ON leave_requests(student_id, from_date, to_date);
+
+-- Low-attendance alerts (user-010)
+CREATE TABLE attendance_alerts (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    student_id BIGINT NOT NULL,
+    window_start DATE NOT NULL,
+    window_end DATE NOT NULL,
+    present_count INT NOT NULL,
+    absent_count INT NOT NULL,
+    percentage DECIMAL(5,2) NOT NULL,
+    threshold DECIMAL(5,2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP NULL,
+    FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_attendance_alerts_student ON attendance_alerts(student_id, resolved_at);
+
+-- Geofenced marks (user-014)
+CREATE TABLE locations (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    latitude DECIMAL(9,6) NOT NULL,
+    longitude DECIMAL(9,6) NOT NULL,
+    radius_meters INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- review state of a geofence flag: pending, approved or rejected
+ALTER TABLE attendance
+    ADD COLUMN flag VARCHAR(16) NULL AFTER status;
+
+CREATE INDEX idx_attendance_flag ON attendance(flag);
+
+CREATE TABLE attendance_flags (
+    attendance_id BIGINT PRIMARY KEY,
+    requested_status VARCHAR(32) NOT NULL,
+    location_id BIGINT NULL,
+    latitude DECIMAL(9,6) NOT NULL,
+    longitude DECIMAL(9,6) NOT NULL,
+    distance_meters INT NOT NULL,
+    reviewed_by BIGINT NULL,
+    review_note VARCHAR(1024) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP NULL,
+    FOREIGN KEY (attendance_id) REFERENCES attendance(id) ON DELETE CASCADE,
+    FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE SET NULL
+);
+
+-- Attendance locking (user-015). attendance_id is kept after the record is
+-- deleted, like attendance_audit.
+CREATE TABLE attendance_lock_overrides (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    action VARCHAR(16) NOT NULL,
+    attendance_id BIGINT NOT NULL,
+    student_id BIGINT NOT NULL,
+    date DATE NOT NULL,
+    session_id BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(32) NOT NULL,
+    user_id BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Departments (user-017), created before the courses that reference them.
+-- Every free-text spelling becomes a department of its own unless listed in
+-- the mapping below, as in migration_departments.sql; find the spellings with
+--   SELECT department, COUNT(*) FROM students GROUP BY department;
+CREATE TABLE departments (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- alternative spellings that resolve to a department
+CREATE TABLE department_aliases (
+    alias VARCHAR(255) PRIMARY KEY,
+    department_id BIGINT NOT NULL,
+    FOREIGN KEY (department_id) REFERENCES departments(id) ON DELETE CASCADE
+);
+
+CREATE TEMPORARY TABLE department_mapping (
+    free_text VARCHAR(255) PRIMARY KEY,
+    code VARCHAR(32) NOT NULL,
+    name VARCHAR(255) NOT NULL
+);
+
+INSERT INTO department_mapping (free_text, code, name) VALUES
+    ('CS', 'CS', 'Computer Science'),
+    ('Comp Sci', 'CS', 'Computer Science'),
+    ('Computer Science', 'CS', 'Computer Science');
+
+-- Unmapped spellings get a derived code; a spelling whose code is taken
+-- joins the department holding it and is kept as its alias, so every
+-- student with a department keeps one once the free-text column is dropped.
+INSERT IGNORE INTO department_mapping (free_text, code, name)
+    SELECT DISTINCT TRIM(department), LEFT(UPPER(REPLACE(TRIM(department), ' ', '_')), 32), TRIM(department)
+    FROM students
+    WHERE TRIM(COALESCE(department, '')) <> '';
+
+INSERT IGNORE INTO departments (code, name)
+    SELECT code, MIN(name) FROM department_mapping GROUP BY code;
+
+INSERT IGNORE INTO department_aliases (alias, department_id)
+    SELECT m.free_text, d.id
+    FROM department_mapping m
+    JOIN departments d ON d.code = m.code
+    WHERE m.free_text <> d.name;
+
+ALTER TABLE students
+    ADD COLUMN department_id BIGINT NULL AFTER email,
+    ADD FOREIGN KEY (department_id) REFERENCES departments(id);
+
+UPDATE students s
+    JOIN department_mapping m ON m.free_text = TRIM(s.department)
+    JOIN departments d ON d.code = m.code
+    SET s.department_id = d.id;
+
+UPDATE students s
+    JOIN departments d ON d.name = TRIM(s.department)
+    SET s.department_id = d.id
+    WHERE s.department_id IS NULL;
+
+DROP TEMPORARY TABLE department_mapping;
+
+ALTER TABLE students DROP COLUMN department;
+
+-- Courses, sections and enrollments (user-016)
+CREATE TABLE courses (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    department_id BIGINT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (department_id) REFERENCES departments(id)
+);
+
+CREATE TABLE sections (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    course_id BIGINT NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (course_id) REFERENCES courses(id),
+    UNIQUE KEY unique_section (course_id, name)
+);
+
+CREATE TABLE enrollments (
+    section_id BIGINT NOT NULL,
+    student_id BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (section_id, student_id),
+    FOREIGN KEY (section_id) REFERENCES sections(id) ON DELETE CASCADE,
+    FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_enrollments_student ON enrollments(student_id);
+
+ALTER TABLE sessions
+    ADD COLUMN section_id BIGINT NULL AFTER title,
+    ADD FOREIGN KEY (section_id) REFERENCES sections(id);
+
+-- Timetable, generated sessions and auto-close (user-018)
+-- weekday follows Go's time.Weekday: 0 is Sunday, 6 is Saturday
+CREATE TABLE timetable_entries (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    section_id BIGINT NOT NULL,
+    weekday TINYINT NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    room VARCHAR(64) NOT NULL DEFAULT '',
+    teacher VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (section_id) REFERENCES sections(id) ON DELETE CASCADE
+);
+
+-- department of a session without a section; a section's sessions belong to its course's department
+ALTER TABLE sessions
+    ADD COLUMN department_id BIGINT NULL AFTER section_id,
+    ADD COLUMN timetable_entry_id BIGINT NULL AFTER department_id,
+    ADD COLUMN closed_at TIMESTAMP NULL AFTER timetable_entry_id,
+    ADD UNIQUE KEY unique_generated_session (timetable_entry_id, date);
+
+-- added after unique_generated_session, which serves as the index of the
+-- timetable_entry_id key
+ALTER TABLE sessions
+    ADD FOREIGN KEY (department_id) REFERENCES departments(id),
+    ADD FOREIGN KEY (timetable_entry_id) REFERENCES timetable_entries(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_sessions_closed ON sessions(closed_at, date);
+
+-- Teacher profiles (user-019)
+-- teacher profile of an m_user account
+CREATE TABLE teachers (
+    user_id BIGINT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES m_user(ID) ON DELETE CASCADE
+);
+
+CREATE TABLE teacher_departments (
+    user_id BIGINT NOT NULL,
+    department_id BIGINT NOT NULL,
+    PRIMARY KEY (user_id, department_id),
+    FOREIGN KEY (user_id) REFERENCES teachers(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (department_id) REFERENCES departments(id) ON DELETE CASCADE
+);
+
+-- Roles and permissions (user-020)
+CREATE TABLE roles (
+    code VARCHAR(32) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE permissions (
+    code VARCHAR(64) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE role_permissions (
+    role_code VARCHAR(32) NOT NULL,
+    permission_code VARCHAR(64) NOT NULL,
+    PRIMARY KEY (role_code, permission_code),
+    FOREIGN KEY (role_code) REFERENCES roles(code) ON DELETE CASCADE,
+    FOREIGN KEY (permission_code) REFERENCES permissions(code) ON DELETE CASCADE
+);
+
+CREATE TABLE user_roles (
+    user_id BIGINT NOT NULL,
+    role_code VARCHAR(32) NOT NULL,
+    PRIMARY KEY (user_id, role_code),
+    FOREIGN KEY (user_id) REFERENCES m_user(ID) ON DELETE CASCADE,
+    FOREIGN KEY (role_code) REFERENCES roles(code)
+);
+
+INSERT INTO roles (code, description) VALUES
+    ('admin', 'Full access, including overriding the attendance lock'),
+    ('teacher', 'Manages and marks the students of their departments'),
+    ('student', 'Requests leave and reads the calendar and timetable'),
+    ('auditor', 'Read-only access to attendance, reports and audit logs');
+
+INSERT INTO permissions (code, description) VALUES
+    ('users:read', 'List and view login accounts'),
+    ('users:write', 'Create and update login accounts'),
+    ('users:delete', 'Delete login accounts'),
+    ('roles:read', 'List roles and the roles of a user'),
+    ('roles:write', 'Assign roles to users'),
+    ('teachers:read', 'List and view teacher profiles'),
+    ('teachers:write', 'Create, update and delete teacher profiles'),
+    ('students:read', 'List and view students'),
+    ('students:write', 'Create and update students'),
+    ('students:delete', 'Delete students'),
+    ('attendance:read', 'View attendance, its history and the attendance statuses'),
+    ('attendance:mark', 'Mark attendance and issue check-in codes'),
+    ('attendance:checkin', 'Check in to a session with its QR code'),
+    ('attendance:correct', 'Correct marked attendance'),
+    ('attendance:delete', 'Delete attendance records'),
+    ('attendance:import', 'Import attendance from CSV'),
+    ('attendance:export', 'Export attendance'),
+    ('attendance:review', 'Review geofence flags'),
+    ('attendance:audit', 'View the lock override audit log'),
+    ('attendance:override_lock', 'Change attendance inside the lock window'),
+    ('attendance_statuses:write', 'Manage the attendance statuses'),
+    ('reports:read', 'View attendance reports and summaries'),
+    ('sessions:read', 'List and view sessions'),
+    ('sessions:write', 'Create and delete sessions'),
+    ('calendar:read', 'View terms, holidays and working days'),
+    ('calendar:write', 'Manage terms, holidays and the weekend'),
+    ('leave:read', 'List and view leave requests'),
+    ('leave:request', 'Request and cancel leave'),
+    ('leave:decide', 'Approve and reject leave requests'),
+    ('alerts:read', 'List low-attendance alerts'),
+    ('locations:read', 'List and view geofence locations'),
+    ('locations:write', 'Manage geofence locations'),
+    ('departments:read', 'List and view departments'),
+    ('departments:write', 'Manage departments'),
+    ('courses:read', 'List and view courses, sections and enrollments'),
+    ('courses:write', 'Manage courses, sections and enrollments'),
+    ('timetable:read', 'View the timetable'),
+    ('timetable:write', 'Manage the timetable and generate sessions');
+
+INSERT INTO role_permissions (role_code, permission_code)
+    SELECT 'admin', code FROM permissions;
+
+INSERT INTO role_permissions (role_code, permission_code) VALUES
+    ('teacher', 'teachers:read'),
+    ('teacher', 'students:read'),
+    ('teacher', 'students:write'),
+    ('teacher', 'students:delete'),
+    ('teacher', 'attendance:read'),
+    ('teacher', 'attendance:mark'),
+    ('teacher', 'attendance:review'),
+    ('teacher', 'reports:read'),
+    ('teacher', 'sessions:read'),
+    ('teacher', 'sessions:write'),
+    ('teacher', 'calendar:read'),
+    ('teacher', 'leave:read'),
+    ('teacher', 'leave:decide'),
+    ('teacher', 'alerts:read'),
+    ('teacher', 'locations:read'),
+    ('teacher', 'departments:read'),
+    ('teacher', 'courses:read'),
+    ('teacher', 'timetable:read'),
+    ('student', 'attendance:checkin'),
+    ('student', 'leave:request'),
+    ('student', 'sessions:read'),
+    ('student', 'calendar:read'),
+    ('student', 'courses:read'),
+    ('student', 'timetable:read');
+
+INSERT INTO role_permissions (role_code, permission_code)
+    SELECT 'auditor', code FROM permissions
+    WHERE code LIKE '%:read' AND code NOT IN ('users:read', 'roles:read')
+       OR code IN ('attendance:export', 'attendance:audit');
+
+-- Every existing account could reach every route before roles existed, so
+-- it starts out as an admin; assign the narrower roles with
+-- PUT /user/{id}/roles afterwards.
+INSERT INTO user_roles (user_id, role_code)
+    SELECT ID, 'admin' FROM m_user;
+
+-- User emails for password resets (user-024)
+ALTER TABLE m_user
+    ADD COLUMN `EMAIL` varchar(255) DEFAULT NULL AFTER `ENABLED`,
+    ADD UNIQUE KEY `USER_EMAIL` (`EMAIL`);
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// AttendanceAlert flags a student whose attendance percentage over the
+// trailing window fell below the configured threshold. A student has at most
+// one open alert; it is resolved once attendance recovers.
+type AttendanceAlert struct {
+	ID           int64      `json:"id" example:"1"`
+	StudentID    int64      `json:"student_id" example:"1"`
+	WindowStart  string     `json:"window_start" example:"2023-09-28"`
+	WindowEnd    string     `json:"window_end" example:"2023-10-27"`
+	PresentCount int        `json:"present_count" example:"14"`
+	AbsentCount  int        `json:"absent_count" example:"6"`
+	Percentage   float64    `json:"percentage" example:"70"`
+	Threshold    float64    `json:"threshold" example:"75"`
+	CreatedAt    time.Time  `json:"created_at" example:"2023-10-27T09:00:00Z"`
+	ResolvedAt   *time.Time `json:"resolved_at,omitempty" example:"2023-11-10T09:00:00Z"`
+}
+
+// AttendanceAlerts array of AttendanceAlert type
+type AttendanceAlerts []AttendanceAlert
+
+// AlertFilter narrows an alert listing. Empty fields are ignored.
+type AlertFilter struct {
+	StudentID     int64
+	Open          *bool
+	DepartmentIDs []int64 // only students of these departments when set
+}
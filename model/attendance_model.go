@@ -1,12 +1,99 @@
 package model
 
+import "time"
+
 // Attendance struct
 type Attendance struct {
 	ID        int64  `json:"id" example:"1"`
 	StudentID int64  `json:"student_id" example:"1" binding:"required"`
 	Date      string `json:"date" example:"2023-10-27" binding:"required"` // Using string for date input, could be time.Time
-	Status    string `json:"status" example:"Present" binding:"required,oneof=Present Absent"`
+	SessionID int64  `json:"session_id,omitempty" example:"0"`             // 0 marks the whole day
+	Status    string `json:"status" example:"Present" binding:"required,attendance_status"`
 }
 
 // Attendances array of Attendance type
 type Attendances []Attendance
+
+// AttendanceFilter narrows an attendance listing. Empty fields are ignored.
+type AttendanceFilter struct {
+	StudentID int64
+	From      string
+	To        string
+	Status    string
+}
+
+// AttendancePage is one page of an attendance listing
+type AttendancePage struct {
+	Data  Attendances `json:"data"`
+	Total int         `json:"total" example:"42"`
+	Page  int         `json:"page" example:"1"`
+	Limit int         `json:"limit" example:"10"`
+}
+
+// AttendanceUpdate is the payload for correcting an existing attendance record
+type AttendanceUpdate struct {
+	Status string `json:"status" example:"Absent" binding:"required,attendance_status"`
+}
+
+// Actions recorded in the attendance audit trail
+const (
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// AttendanceAudit is one entry of an attendance record's change history
+type AttendanceAudit struct {
+	ID           int64     `json:"id" example:"1"`
+	AttendanceID int64     `json:"attendance_id" example:"1"`
+	StudentID    int64     `json:"student_id" example:"1"`
+	Date         string    `json:"date" example:"2023-10-27"`
+	Action       string    `json:"action" example:"update" enums:"update,delete"`
+	OldStatus    string    `json:"old_status" example:"Present"`
+	NewStatus    string    `json:"new_status,omitempty" example:"Absent"`
+	ChangedBy    int64     `json:"changed_by" example:"1"`
+	ChangedAt    time.Time `json:"changed_at" example:"2023-10-28T09:00:00Z"`
+}
+
+// AttendanceAudits array of AttendanceAudit type
+type AttendanceAudits []AttendanceAudit
+
+// Outcomes reported for each entry of a bulk attendance request
+const (
+	BulkResultCreated        = "created"
+	BulkResultDuplicate      = "duplicate"
+	BulkResultUnknownStudent = "unknown_student"
+	BulkResultOnLeave        = "on_leave"
+	BulkResultNotEnrolled    = "not_enrolled"
+)
+
+// BulkAttendanceEntry is a single student mark inside a bulk request
+type BulkAttendanceEntry struct {
+	StudentID int64  `json:"student_id" example:"1" binding:"required"`
+	Status    string `json:"status" example:"Present" binding:"required,attendance_status"`
+}
+
+// BulkAttendanceRequest marks a whole class for one date and session
+type BulkAttendanceRequest struct {
+	Date      string                `json:"date" example:"2023-10-27" binding:"required"`
+	SessionID int64                 `json:"session_id,omitempty" example:"0"`
+	Entries   []BulkAttendanceEntry `json:"entries" binding:"required,min=1,dive"`
+}
+
+// BulkAttendanceResult reports what happened to one entry of a bulk request
+type BulkAttendanceResult struct {
+	StudentID int64  `json:"student_id" example:"1"`
+	Status    string `json:"status" example:"Present"`
+	Result    string `json:"result" example:"created" enums:"created,duplicate,unknown_student,on_leave,not_enrolled"`
+	ID        int64  `json:"id,omitempty" example:"1"`
+}
+
+// BulkAttendanceResponse is the per-row report returned for a bulk request
+type BulkAttendanceResponse struct {
+	Date        string                 `json:"date" example:"2023-10-27"`
+	Created     int                    `json:"created" example:"38"`
+	Duplicates  int                    `json:"duplicates" example:"1"`
+	Unknown     int                    `json:"unknown" example:"1"`
+	OnLeave     int                    `json:"on_leave" example:"2"`
+	NotEnrolled int                    `json:"not_enrolled" example:"0"`
+	Results     []BulkAttendanceResult `json:"results"`
+}
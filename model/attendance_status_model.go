@@ -0,0 +1,50 @@
+package model
+
+// Statuses the application itself writes. They are seeded with the catalogue
+// and cannot be deleted.
+const (
+	// StatusPresent is what QR self check-in marks
+	StatusPresent = "Present"
+	// StatusAbsent is what a rejected geofence flag leaves a mark with
+	StatusAbsent = "Absent"
+	// StatusOnLeave is what approved leave requests mark; clients cannot
+	// submit it
+	StatusOnLeave = "OnLeave"
+	// StatusFlagged replaces the requested status of a mark submitted from
+	// outside its geofence until an admin reviews it; clients cannot submit it
+	StatusFlagged = "Flagged"
+)
+
+// AttendanceStatus is an entry of the configurable status catalogue.
+// Excused statuses count neither as present nor as absent, so they leave
+// attendance percentages unchanged.
+type AttendanceStatus struct {
+	Code            string `json:"code" example:"Late" binding:"required,max=32,alphanum"`
+	Label           string `json:"label" example:"Late arrival" binding:"required"`
+	CountsAsPresent bool   `json:"counts_as_present" example:"true"`
+	Excused         bool   `json:"excused" example:"false"`
+	SortOrder       int    `json:"sort_order" example:"3"`
+}
+
+// AttendanceStatuses array of AttendanceStatus type
+type AttendanceStatuses []AttendanceStatus
+
+// DefaultAttendanceStatuses mirrors the catalogue seeded by migration.sql and
+// is used until the catalogue has been loaded from the database
+var DefaultAttendanceStatuses = AttendanceStatuses{
+	{Code: StatusPresent, Label: "Present", CountsAsPresent: true, SortOrder: 1},
+	{Code: StatusAbsent, Label: "Absent", CountsAsPresent: false, SortOrder: 2},
+	{Code: "Late", Label: "Late", CountsAsPresent: true, SortOrder: 3},
+	{Code: "Excused", Label: "Excused", CountsAsPresent: false, Excused: true, SortOrder: 4},
+	{Code: "HalfDay", Label: "Half-day", CountsAsPresent: true, SortOrder: 5},
+	{Code: StatusOnLeave, Label: "On leave", CountsAsPresent: false, Excused: true, SortOrder: 6},
+	{Code: StatusFlagged, Label: "Flagged for review", CountsAsPresent: false, SortOrder: 7},
+}
+
+// AttendanceStatusUpdate is the payload for changing an existing status
+type AttendanceStatusUpdate struct {
+	Label           string `json:"label" example:"Late arrival" binding:"required"`
+	CountsAsPresent bool   `json:"counts_as_present" example:"true"`
+	Excused         bool   `json:"excused" example:"false"`
+	SortOrder       int    `json:"sort_order" example:"3"`
+}
@@ -0,0 +1,36 @@
+package model
+
+// Term is a teaching period of the academic calendar. When terms are defined,
+// days outside every term are not working days.
+type Term struct {
+	ID        int64  `json:"id" example:"1"`
+	Name      string `json:"name" example:"Autumn 2023" binding:"required"`
+	StartDate string `json:"start_date" example:"2023-08-01" binding:"required"`
+	EndDate   string `json:"end_date" example:"2023-12-15" binding:"required"`
+}
+
+// Terms array of Term type
+type Terms []Term
+
+// Holiday is a single non-working day of the academic calendar
+type Holiday struct {
+	ID   int64  `json:"id" example:"1"`
+	Date string `json:"date" example:"2023-11-12" binding:"required"`
+	Name string `json:"name" example:"Diwali" binding:"required"`
+}
+
+// Holidays array of Holiday type
+type Holidays []Holiday
+
+// WeekendRule lists the weekdays that are never working days
+type WeekendRule struct {
+	Weekdays []string `json:"weekdays" example:"Saturday,Sunday" binding:"required"`
+}
+
+// WorkingDays lists the working days of a date range
+type WorkingDays struct {
+	From  string   `json:"from" example:"2023-10-23"`
+	To    string   `json:"to" example:"2023-10-29"`
+	Count int      `json:"count" example:"5"`
+	Days  []string `json:"days"`
+}
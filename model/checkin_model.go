@@ -0,0 +1,7 @@
+package model
+
+// CheckinRequest is sent by a student scanning a session's check-in QR code.
+// The student is the logged in caller.
+type CheckinRequest struct {
+	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..." binding:"required"`
+}
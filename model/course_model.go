@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// Course is a subject offered by a department, taught in one or more sections
+type Course struct {
+	ID           int64     `json:"id" example:"1"`
+	Code         string    `json:"code" example:"CS101" binding:"required,max=32"`
+	Name         string    `json:"name" example:"Data Structures" binding:"required"`
+	DepartmentID int64     `json:"department_id,omitempty" example:"1"`
+	CreatedAt    time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// Courses array of Course type
+type Courses []Course
+
+// Section is a class group of a course. Sessions can belong to a section,
+// and only students enrolled in it can be marked for them.
+type Section struct {
+	ID        int64     `json:"id" example:"1"`
+	CourseID  int64     `json:"course_id" example:"1" binding:"required"`
+	Name      string    `json:"name" example:"A" binding:"required,max=64"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// Sections array of Section type
+type Sections []Section
+
+// Enrollment is a student's membership of a section
+type Enrollment struct {
+	SectionID    int64     `json:"section_id" example:"1"`
+	StudentID    int64     `json:"student_id" example:"1"`
+	StudentName  string    `json:"student_name" example:"John Doe"`
+	StudentEmail string    `json:"student_email" example:"john.doe@example.com"`
+	CreatedAt    time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// Enrollments array of Enrollment type
+type Enrollments []Enrollment
+
+// EnrollmentRequest enrolls students in a section
+type EnrollmentRequest struct {
+	StudentIDs []int64 `json:"student_ids" binding:"required,min=1"`
+}
+
+// EnrollmentResult reports the outcome of an enrollment request
+type EnrollmentResult struct {
+	Enrolled        int     `json:"enrolled" example:"28"`
+	AlreadyEnrolled int     `json:"already_enrolled" example:"2"`
+	UnknownStudents []int64 `json:"unknown_students"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// Department is an academic department students and courses belong to.
+// Aliases are alternative spellings (e.g. "CS", "Comp Sci") that resolve to
+// it wherever a department is given by name.
+type Department struct {
+	ID        int64     `json:"id" example:"1"`
+	Code      string    `json:"code" example:"CS" binding:"required,max=32"`
+	Name      string    `json:"name" example:"Computer Science" binding:"required"`
+	Aliases   []string  `json:"aliases" example:"Comp Sci"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// Departments array of Department type
+type Departments []Department
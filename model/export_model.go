@@ -0,0 +1,21 @@
+package model
+
+// AttendanceExportFilter selects the attendance rows of an export. A zero
+// DepartmentID exports every department.
+type AttendanceExportFilter struct {
+	From         string
+	To           string
+	DepartmentID int64
+}
+
+// AttendanceExportRow is one attendance record joined with its student
+type AttendanceExportRow struct {
+	AttendanceID int64  `json:"attendance_id" example:"1"`
+	StudentID    int64  `json:"student_id" example:"1"`
+	StudentName  string `json:"student_name" example:"John Doe"`
+	StudentEmail string `json:"student_email" example:"john.doe@example.com"`
+	Department   string `json:"department" example:"Computer Science"`
+	Date         string `json:"date" example:"2023-10-27"`
+	SessionID    int64  `json:"session_id" example:"0"`
+	Status       string `json:"status" example:"Present"`
+}
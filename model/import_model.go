@@ -0,0 +1,21 @@
+package model
+
+// ImportError describes one rejected line of an attendance import
+type ImportError struct {
+	Line   int      `json:"line" example:"14"`
+	Record []string `json:"record"`
+	Error  string   `json:"error" example:"unknown student"`
+}
+
+// ImportResult summarises an attendance import. In a dry run nothing is
+// written, so Imported and Duplicates stay zero and Valid counts the lines
+// that would be inserted.
+type ImportResult struct {
+	DryRun     bool          `json:"dry_run" example:"false"`
+	Total      int           `json:"total" example:"1200"`
+	Valid      int           `json:"valid" example:"1195"`
+	Imported   int           `json:"imported" example:"1190"`
+	Duplicates int           `json:"duplicates" example:"5"`
+	Rejected   int           `json:"rejected" example:"5"`
+	Errors     []ImportError `json:"errors"`
+}
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// States of a leave request. Pending requests can be approved, rejected or
+// cancelled; approved requests can still be cancelled.
+const (
+	LeaveStatePending   = "pending"
+	LeaveStateApproved  = "approved"
+	LeaveStateRejected  = "rejected"
+	LeaveStateCancelled = "cancelled"
+)
+
+// LeaveRequest asks for a student to be excused for a date range. Approval
+// marks every working day of the range OnLeave.
+type LeaveRequest struct {
+	ID           int64      `json:"id" example:"1"`
+	StudentID    int64      `json:"student_id" example:"1" binding:"required"`
+	FromDate     string     `json:"from_date" example:"2023-10-30" binding:"required"`
+	ToDate       string     `json:"to_date" example:"2023-11-01" binding:"required"`
+	Reason       string     `json:"reason" example:"Family wedding" binding:"required"`
+	State        string     `json:"state" example:"pending" enums:"pending,approved,rejected,cancelled"`
+	RequestedBy  int64      `json:"requested_by" example:"1"`
+	DecidedBy    int64      `json:"decided_by,omitempty" example:"2"`
+	DecisionNote string     `json:"decision_note,omitempty" example:"Approved by class teacher"`
+	CreatedAt    time.Time  `json:"created_at" example:"2023-10-27T09:00:00Z"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty" example:"2023-10-27T12:00:00Z"`
+}
+
+// LeaveRequests array of LeaveRequest type
+type LeaveRequests []LeaveRequest
+
+// LeaveRequestFilter narrows a leave request listing. Empty fields are ignored.
+type LeaveRequestFilter struct {
+	StudentID     int64
+	State         string
+	DepartmentIDs []int64 // only students of these departments when set
+}
+
+// LeaveDecision is the optional payload for approving, rejecting or
+// cancelling a leave request
+type LeaveDecision struct {
+	Note string `json:"note" example:"Approved by class teacher"`
+}
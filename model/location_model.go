@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// Location is a campus or classroom geofence: a circle of RadiusMeters
+// around a point
+type Location struct {
+	ID           int64     `json:"id" example:"1"`
+	Name         string    `json:"name" example:"Main Campus" binding:"required"`
+	Latitude     float64   `json:"latitude" example:"12.971599"`
+	Longitude    float64   `json:"longitude" example:"77.594566"`
+	RadiusMeters int       `json:"radius_meters" example:"250" binding:"required,min=1"`
+	CreatedAt    time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// Locations array of Location type
+type Locations []Location
+
+// AttendanceMark is the markAttendance payload. Coordinates are optional;
+// when given, the device position is checked against the geofence of
+// LocationID, or of the nearest location when LocationID is 0.
+type AttendanceMark struct {
+	Attendance
+	Latitude   *float64 `json:"latitude,omitempty" example:"12.971599"`
+	Longitude  *float64 `json:"longitude,omitempty" example:"77.594566"`
+	LocationID int64    `json:"location_id,omitempty" example:"1"`
+}
+
+// Review states of a flagged attendance record
+const (
+	FlagStatePending  = "pending"
+	FlagStateApproved = "approved"
+	FlagStateRejected = "rejected"
+)
+
+// AttendanceFlag is a mark stored as Flagged because it was submitted from
+// outside its geofence
+type AttendanceFlag struct {
+	AttendanceID    int64      `json:"attendance_id" example:"1"`
+	StudentID       int64      `json:"student_id" example:"1"`
+	StudentName     string     `json:"student_name" example:"John Doe"`
+	Date            string     `json:"date" example:"2023-10-27"`
+	SessionID       int64      `json:"session_id" example:"0"`
+	Status          string     `json:"status" example:"Flagged"`
+	RequestedStatus string     `json:"requested_status" example:"Present"`
+	State           string     `json:"state" example:"pending"`
+	LocationID      *int64     `json:"location_id" example:"1"`
+	Latitude        float64    `json:"latitude" example:"12.981599"`
+	Longitude       float64    `json:"longitude" example:"77.594566"`
+	DistanceMeters  int        `json:"distance_meters" example:"1112"`
+	ReviewedBy      *int64     `json:"reviewed_by" example:"1"`
+	ReviewNote      string     `json:"review_note" example:"Field trip"`
+	CreatedAt       time.Time  `json:"created_at" example:"2023-10-27T09:00:00Z"`
+	ReviewedAt      *time.Time `json:"reviewed_at" example:"2023-10-27T12:00:00Z"`
+}
+
+// AttendanceFlags array of AttendanceFlag type
+type AttendanceFlags []AttendanceFlag
+
+// FlagReview is the payload for approving or rejecting a flagged mark
+type FlagReview struct {
+	Note string `json:"note" example:"Confirmed with the class teacher"`
+}
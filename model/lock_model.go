@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Actions recorded when a lock override is used
+const (
+	LockActionMark   = "mark"
+	LockActionUpdate = "update"
+	LockActionDelete = "delete"
+)
+
+// LockOverride records a change to attendance older than the lock window
+// made by a user allowed to override the lock
+type LockOverride struct {
+	ID           int64     `json:"id" example:"1"`
+	Action       string    `json:"action" example:"update"`
+	AttendanceID int64     `json:"attendance_id" example:"1"`
+	StudentID    int64     `json:"student_id" example:"1"`
+	Date         string    `json:"date" example:"2023-10-02"`
+	SessionID    int64     `json:"session_id" example:"0"`
+	Status       string    `json:"status" example:"Present"`
+	UserID       int64     `json:"user_id" example:"1"`
+	CreatedAt    time.Time `json:"created_at" example:"2023-10-27T09:00:00Z"`
+}
+
+// LockOverrides array of LockOverride type
+type LockOverrides []LockOverride
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// LoginSession is a signed-in device: the token pairs issued by one login and
+// rotated by its refreshes
+type LoginSession struct {
+	ID          string    `json:"id" example:"2X4lTdsBd1kTZ9LSXjPx0xcZKbW"`
+	CreatedAt   time.Time `json:"created_at" example:"2023-10-27T09:00:00Z"`
+	RefreshedAt time.Time `json:"refreshed_at" example:"2023-10-27T10:00:00Z"`
+	IP          string    `json:"ip" example:"203.0.113.7"`
+	UserAgent   string    `json:"user_agent" example:"Mozilla/5.0"`
+	Current     bool      `json:"current" example:"true"`
+}
+
+// LoginSessions array of LoginSession type
+type LoginSessions []LoginSession
@@ -0,0 +1,18 @@
+package model
+
+// PasswordChange changes the caller's own password
+type PasswordChange struct {
+	CurrentPassword string `json:"currentPassword" example:"passlogin" binding:"required"`
+	NewPassword     string `json:"newPassword" example:"n3w-passlogin" binding:"required"`
+}
+
+// PasswordResetRequest asks for a reset token to be emailed to an account
+type PasswordResetRequest struct {
+	Email string `json:"email" example:"budi@scopex.local" binding:"required,email"`
+}
+
+// PasswordReset sets a new password with an emailed reset token
+type PasswordReset struct {
+	Token       string `json:"token" example:"Vq3hXb2J4mS0x1cQeR9uYw7tKz5nLp8aDf6gHj2kMo4" binding:"required"`
+	NewPassword string `json:"newPassword" example:"n3w-passlogin" binding:"required"`
+}
@@ -1,13 +1,56 @@
 package model
 
-// AttendanceReport struct to hold aggregated report data
+// StatusCount is the number of records a student has with one status
+type StatusCount struct {
+	Code            string `json:"code"`
+	Label           string `json:"label"`
+	CountsAsPresent bool   `json:"counts_as_present"`
+	Excused         bool   `json:"excused"`
+	Count           int    `json:"count"`
+}
+
+// AttendanceReport struct to hold aggregated report data. PresentCount sums
+// the statuses that count as present and AbsentCount the others, leaving out
+// excused statuses.
+// UnmarkedWorkingDays counts calendar working days without any record.
 type AttendanceReport struct {
-	StudentID    int64  `json:"student_id"`
-	StudentName  string `json:"student_name"`
-	StudentEmail string `json:"student_email"`
-	PresentCount int    `json:"present_count"`
-	AbsentCount  int    `json:"absent_count"`
+	StudentID           int64         `json:"student_id"`
+	StudentName         string        `json:"student_name"`
+	StudentEmail        string        `json:"student_email"`
+	PresentCount        int           `json:"present_count"`
+	AbsentCount         int           `json:"absent_count"`
+	UnmarkedWorkingDays int           `json:"unmarked_working_days"`
+	StatusCounts        []StatusCount `json:"status_counts"`
 }
 
 // AttendanceReports array of AttendanceReport
 type AttendanceReports []AttendanceReport
+
+// ReportScope narrows an attendance report. A non-zero SectionID covers the
+// section's enrolled students and only its sessions; a non-zero DepartmentID
+// covers the department's students. The zero value reports on everyone.
+type ReportScope struct {
+	SectionID    int64
+	DepartmentID int64
+}
+
+// MonthlyAttendance is one month of a student's attendance summary
+type MonthlyAttendance struct {
+	Month        string  `json:"month" example:"2023-10"`
+	PresentCount int     `json:"present_count" example:"18"`
+	AbsentCount  int     `json:"absent_count" example:"2"`
+	Percentage   float64 `json:"percentage" example:"90"`
+}
+
+// AttendanceSummary extends a student's AttendanceReport with the attendance
+// percentage, day streaks and a per-month breakdown for a date range
+type AttendanceSummary struct {
+	AttendanceReport
+	From                 string              `json:"from" example:"2023-09-01"`
+	To                   string              `json:"to" example:"2023-10-31"`
+	Percentage           float64             `json:"percentage" example:"87.5"`
+	CurrentPresentStreak int                 `json:"current_present_streak" example:"4"`
+	LongestPresentStreak int                 `json:"longest_present_streak" example:"12"`
+	LongestAbsentStreak  int                 `json:"longest_absent_streak" example:"2"`
+	Months               []MonthlyAttendance `json:"months"`
+}
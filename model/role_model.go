@@ -0,0 +1,85 @@
+package model
+
+// Roles seeded by migration.sql
+const (
+	RoleAdmin   = "admin"
+	RoleTeacher = "teacher"
+	RoleStudent = "student"
+	RoleAuditor = "auditor"
+)
+
+// Permissions checked by the routes, named resource:action. The catalogue
+// and the role grants live in the permissions and role_permissions tables.
+const (
+	PermUsersRead   = "users:read"
+	PermUsersWrite  = "users:write"
+	PermUsersDelete = "users:delete"
+	PermRolesRead   = "roles:read"
+	PermRolesWrite  = "roles:write"
+
+	PermTeachersRead  = "teachers:read"
+	PermTeachersWrite = "teachers:write"
+
+	PermStudentsRead   = "students:read"
+	PermStudentsWrite  = "students:write"
+	PermStudentsDelete = "students:delete"
+
+	PermAttendanceRead          = "attendance:read"
+	PermAttendanceMark          = "attendance:mark"
+	PermAttendanceCheckin       = "attendance:checkin"
+	PermAttendanceCorrect       = "attendance:correct"
+	PermAttendanceDelete        = "attendance:delete"
+	PermAttendanceImport        = "attendance:import"
+	PermAttendanceExport        = "attendance:export"
+	PermAttendanceReview        = "attendance:review"
+	PermAttendanceAudit         = "attendance:audit"
+	PermAttendanceOverrideLock  = "attendance:override_lock"
+	PermAttendanceStatusesWrite = "attendance_statuses:write"
+
+	PermReportsRead = "reports:read"
+
+	PermSessionsRead  = "sessions:read"
+	PermSessionsWrite = "sessions:write"
+
+	PermCalendarRead  = "calendar:read"
+	PermCalendarWrite = "calendar:write"
+
+	PermLeaveRead    = "leave:read"
+	PermLeaveRequest = "leave:request"
+	PermLeaveDecide  = "leave:decide"
+
+	PermAlertsRead = "alerts:read"
+
+	PermLocationsRead  = "locations:read"
+	PermLocationsWrite = "locations:write"
+
+	PermDepartmentsRead  = "departments:read"
+	PermDepartmentsWrite = "departments:write"
+
+	PermCoursesRead  = "courses:read"
+	PermCoursesWrite = "courses:write"
+
+	PermTimetableRead  = "timetable:read"
+	PermTimetableWrite = "timetable:write"
+)
+
+// Role is a named set of permissions
+type Role struct {
+	Code        string   `json:"code" example:"teacher"`
+	Description string   `json:"description" example:"Marks and views the attendance of their departments"`
+	Permissions []string `json:"permissions" example:"students:read,attendance:mark"`
+}
+
+// Roles array of Role type
+type Roles []Role
+
+// Grants are the roles of a user and the permissions they add up to
+type Grants struct {
+	Roles       []string `json:"roles" example:"teacher"`
+	Permissions []string `json:"permissions" example:"students:read,attendance:mark"`
+}
+
+// UserRoles replaces the roles of a user
+type UserRoles struct {
+	Roles []string `json:"roles" binding:"required" example:"teacher"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Session is a single lecture or period on a given date that attendance can
+// be marked against. Attendance without a session covers the whole day. A
+// session belongs to the department of its section's course, or to
+// DepartmentID when it has no section, and is closed once the absentees of
+// that department have been marked.
+type Session struct {
+	ID               int64      `json:"id" example:"1"`
+	Date             string     `json:"date" example:"2023-10-27" binding:"required"`
+	Period           int        `json:"period" example:"1" binding:"min=0"`
+	StartTime        string     `json:"start_time" example:"09:00" binding:"required"`
+	EndTime          string     `json:"end_time" example:"09:50" binding:"required"`
+	Title            string     `json:"title" example:"Data Structures"`
+	SectionID        int64      `json:"section_id,omitempty" example:"1"`         // 0 when the session is open to every student
+	DepartmentID     int64      `json:"department_id,omitempty" example:"1"`      // only for sessions without a section
+	TimetableEntryID int64      `json:"timetable_entry_id,omitempty" example:"1"` // set when generated from the timetable
+	ClosedAt         *time.Time `json:"closed_at,omitempty" example:"2023-10-27T10:20:00Z"`
+	CreatedAt        time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// Sessions array of Session type
+type Sessions []Session
@@ -2,13 +2,15 @@ package model
 
 import "time"
 
-// Student struct
+// Student struct. A department is given either by department_id or by
+// department, the name, code or an alias of an existing department.
 type Student struct {
-	ID         int64     `json:"id" example:"1"`
-	Name       string    `json:"name" example:"John Doe" binding:"required"`
-	Email      string    `json:"email" example:"john.doe@example.com" binding:"required,email"`
-	Department string    `json:"department" example:"Computer Science"`
-	CreatedAt  time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	ID           int64     `json:"id" example:"1"`
+	Name         string    `json:"name" example:"John Doe" binding:"required"`
+	Email        string    `json:"email" example:"john.doe@example.com" binding:"required,email"`
+	DepartmentID int64     `json:"department_id" example:"1"`
+	Department   string    `json:"department" example:"Computer Science"`
+	CreatedAt    time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
 }
 
 // Students array of Student type
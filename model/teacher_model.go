@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// Teacher is the teacher profile of a login account (m_user). A teacher can
+// only work with the students of the departments assigned to them.
+type Teacher struct {
+	UserID        int64     `json:"user_id" example:"2"`
+	UserName      string    `json:"user_name" example:"budi"` // read-only, the account's login name
+	Name          string    `json:"name" example:"Budi Santoso" binding:"required"`
+	Email         string    `json:"email" example:"budi@example.com" binding:"required"`
+	DepartmentIDs []int64   `json:"department_ids" example:"1,2"`
+	CreatedAt     time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// Teachers array of Teacher type
+type Teachers []Teacher
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// TimetableEntry is a weekly slot of a section. Weekday follows Go's
+// time.Weekday: 0 is Sunday, 6 is Saturday.
+type TimetableEntry struct {
+	ID        int64     `json:"id" example:"1"`
+	SectionID int64     `json:"section_id" example:"1" binding:"required"`
+	Weekday   int       `json:"weekday" example:"1" binding:"min=0,max=6"`
+	StartTime string    `json:"start_time" example:"09:00" binding:"required"`
+	EndTime   string    `json:"end_time" example:"09:50" binding:"required"`
+	Title     string    `json:"title" example:"Data Structures"`
+	Room      string    `json:"room" example:"B-204" binding:"max=64"`
+	Teacher   string    `json:"teacher" example:"Dr. Rao"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// TimetableEntries array of TimetableEntry type
+type TimetableEntries []TimetableEntry
+
+// SessionGenerationRequest asks for the sessions of the timetable between
+// From and To. SectionID 0 generates them for every section.
+type SessionGenerationRequest struct {
+	From      string `json:"from" example:"2023-10-01" binding:"required"`
+	To        string `json:"to" example:"2023-10-31" binding:"required"`
+	SectionID int64  `json:"section_id,omitempty" example:"1"`
+}
+
+// SessionGenerationResult reports how many sessions were generated and how
+// many already existed from an earlier run
+type SessionGenerationResult struct {
+	Created  int `json:"created" example:"42"`
+	Existing int `json:"existing" example:"0"`
+}
@@ -1,15 +1,70 @@
 package model
 
-// MUser struct
+// MUser struct. It holds the password hash and is never returned by the
+// API; handlers answer with UserView.
 type MUser struct {
 	ID                 int64  `json:"id" example:"1"`
 	UserName           string `json:"userName" example:"userlogin"`
-	Password           string `json:"password" example:"passlogin"`
+	Password           string `json:"-"`
 	AccountExpired     bool   `json:"accountExpired" example:"false"`
 	AccountLocked      bool   `json:"accountLocked" example:"false"`
 	CredentialsExpired bool   `json:"credentialsExpired" example:"false"`
 	Enabled            bool   `json:"enabled" example:"true"`
+	Email              string `json:"email" example:"budi@scopex.local"`
 }
 
 // MUsers array of MUser type
 type MUsers []MUser
+
+// UserView is a user as returned by the API, without the password hash
+type UserView struct {
+	ID                 int64  `json:"id" example:"1"`
+	UserName           string `json:"userName" example:"userlogin"`
+	Email              string `json:"email" example:"budi@scopex.local"`
+	AccountExpired     bool   `json:"accountExpired" example:"false"`
+	AccountLocked      bool   `json:"accountLocked" example:"false"`
+	CredentialsExpired bool   `json:"credentialsExpired" example:"false"`
+	Enabled            bool   `json:"enabled" example:"true"`
+}
+
+// UserViews array of UserView type
+type UserViews []UserView
+
+// UserPage is one page of the user listing
+type UserPage struct {
+	Data  UserViews `json:"data"`
+	Total int       `json:"total" example:"42"`
+	Page  int       `json:"page" example:"1"`
+	Limit int       `json:"limit" example:"10"`
+}
+
+// UserCreate is the payload for creating a user. Enabled defaults to true.
+type UserCreate struct {
+	UserName           string `json:"userName" example:"userlogin" binding:"required"`
+	Password           string `json:"password" example:"passlogin1" binding:"required"`
+	Email              string `json:"email" example:"budi@scopex.local" binding:"omitempty,email"`
+	AccountExpired     bool   `json:"accountExpired" example:"false"`
+	AccountLocked      bool   `json:"accountLocked" example:"false"`
+	CredentialsExpired bool   `json:"credentialsExpired" example:"false"`
+	Enabled            *bool  `json:"enabled" example:"true"`
+}
+
+// UserUpdate is the payload for updating a user. Omitted fields keep their
+// value; the password is only changed when one is supplied, and an empty
+// email removes it.
+type UserUpdate struct {
+	UserName           *string `json:"userName" example:"userlogin"`
+	Password           *string `json:"password" example:"passlogin1"`
+	Email              *string `json:"email" example:"budi@scopex.local"`
+	AccountExpired     *bool   `json:"accountExpired" example:"false"`
+	AccountLocked      *bool   `json:"accountLocked" example:"false"`
+	CredentialsExpired *bool   `json:"credentialsExpired" example:"false"`
+	Enabled            *bool   `json:"enabled" example:"true"`
+}
+
+// LegacyUserUpdate is the body of the deprecated PUT /user/, which names the
+// user to update by id in the body instead of the path
+type LegacyUserUpdate struct {
+	ID int64 `json:"id" example:"1" binding:"required"`
+	UserUpdate
+}
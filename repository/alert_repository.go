@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	configuration "github.com/shravanasati/scopex-go-assignment/configuration"
+	model "github.com/shravanasati/scopex-go-assignment/model"
+)
+
+type AlertRepository interface {
+	GetStudentAttendanceCounts(studentID int64, startDate, endDate string) (present, absent int, err error)
+	GetOpenAlert(studentID int64) (model.AttendanceAlert, error)
+	CreateAlert(alert model.AttendanceAlert) (int64, error)
+	RefreshAlert(id int64, alert model.AttendanceAlert) error
+	ResolveAlert(id int64) error
+	GetAlerts(filter model.AlertFilter, limit, offset int) (model.AttendanceAlerts, error)
+}
+type alertRepository struct{}
+
+var AlertRepo AlertRepository = &alertRepository{}
+
+// ErrAlertNotFound indicates that the requested alert does not exist.
+var ErrAlertNotFound = errors.New("attendance alert not found")
+
+const alertColumns = "id, student_id, DATE_FORMAT(window_start, '%Y-%m-%d'), DATE_FORMAT(window_end, '%Y-%m-%d'), present_count, absent_count, percentage, threshold, created_at, resolved_at"
+
+// GetStudentAttendanceCounts counts a student's records in a date range that
+// do and do not count as present. Excused records are in neither count.
+func (r *alertRepository) GetStudentAttendanceCounts(studentID int64, startDate, endDate string) (int, int, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT 
+			COALESCE(SUM(CASE WHEN st.counts_as_present = 1 THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN st.counts_as_present = 0 AND st.excused = 0 THEN 1 ELSE 0 END), 0)
+		FROM 
+			attendance a
+		JOIN 
+			attendance_statuses st ON st.code = a.status
+		WHERE 
+			a.student_id = ? AND a.date BETWEEN ? AND ?
+	`
+
+	var present, absent int
+	if err := db.QueryRowContext(ctx, query, studentID, startDate, endDate).Scan(&present, &absent); err != nil {
+		log.Println("Error counting student attendance: " + err.Error())
+		return 0, 0, err
+	}
+
+	return present, absent, nil
+}
+
+// GetOpenAlert retrieves the unresolved alert of a student
+func (r *alertRepository) GetOpenAlert(studentID int64) (model.AttendanceAlert, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "SELECT " + alertColumns + " FROM attendance_alerts WHERE student_id = ? AND resolved_at IS NULL ORDER BY id DESC LIMIT 1"
+	alert, err := scanAlert(db.QueryRowContext(ctx, query, studentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return alert, ErrAlertNotFound
+		}
+		log.Println("Error querying open alert: " + err.Error())
+		return alert, err
+	}
+
+	return alert, nil
+}
+
+// CreateAlert inserts a new open alert
+func (r *alertRepository) CreateAlert(alert model.AttendanceAlert) (int64, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "INSERT INTO attendance_alerts (student_id, window_start, window_end, present_count, absent_count, percentage, threshold) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, alert.StudentID, alert.WindowStart, alert.WindowEnd, alert.PresentCount, alert.AbsentCount, alert.Percentage, alert.Threshold)
+	if err != nil {
+		if isMySQLError(err, mysqlErrNoReferencedRow) {
+			return 0, ErrStudentNotFound
+		}
+		log.Println("Error inserting attendance alert: " + err.Error())
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+// RefreshAlert updates the window and counts of an open alert
+func (r *alertRepository) RefreshAlert(id int64, alert model.AttendanceAlert) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "UPDATE attendance_alerts SET window_start = ?, window_end = ?, present_count = ?, absent_count = ?, percentage = ?, threshold = ? WHERE id = ? AND resolved_at IS NULL"
+	_, err := db.ExecContext(ctx, query, alert.WindowStart, alert.WindowEnd, alert.PresentCount, alert.AbsentCount, alert.Percentage, alert.Threshold, id)
+	if err != nil {
+		log.Println("Error refreshing attendance alert: " + err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// ResolveAlert closes an open alert
+func (r *alertRepository) ResolveAlert(id int64) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := db.ExecContext(ctx, "UPDATE attendance_alerts SET resolved_at = CURRENT_TIMESTAMP WHERE id = ? AND resolved_at IS NULL", id)
+	if err != nil {
+		log.Println("Error resolving attendance alert: " + err.Error())
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrAlertNotFound
+	}
+
+	return nil
+}
+
+// GetAlerts retrieves a page of alerts, newest first
+func (r *alertRepository) GetAlerts(filter model.AlertFilter, limit, offset int) (model.AttendanceAlerts, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var conditions []string
+	var args []any
+	if filter.StudentID != 0 {
+		conditions = append(conditions, "student_id = ?")
+		args = append(args, filter.StudentID)
+	}
+	if filter.Open != nil {
+		if *filter.Open {
+			conditions = append(conditions, "resolved_at IS NULL")
+		} else {
+			conditions = append(conditions, "resolved_at IS NOT NULL")
+		}
+	}
+	if len(filter.DepartmentIDs) > 0 {
+		conditions = append(conditions, "student_id IN (SELECT id FROM students WHERE department_id IN ("+placeholders(len(filter.DepartmentIDs))+"))")
+		for _, id := range filter.DepartmentIDs {
+			args = append(args, id)
+		}
+	}
+
+	query := "SELECT " + alertColumns + " FROM attendance_alerts"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?"
+
+	rows, err := db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		log.Println("Error querying attendance alerts: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := model.AttendanceAlerts{}
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			log.Println("Error scanning attendance alert: " + err.Error())
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func scanAlert(row rowScanner) (model.AttendanceAlert, error) {
+	var alert model.AttendanceAlert
+	var resolvedAt sql.NullTime
+
+	err := row.Scan(&alert.ID, &alert.StudentID, &alert.WindowStart, &alert.WindowEnd, &alert.PresentCount, &alert.AbsentCount,
+		&alert.Percentage, &alert.Threshold, &alert.CreatedAt, &resolvedAt)
+	if err != nil {
+		return alert, err
+	}
+
+	if resolvedAt.Valid {
+		alert.ResolvedAt = &resolvedAt.Time
+	}
+	return alert, nil
+}
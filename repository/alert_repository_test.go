@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var alertRowColumns = []string{"id", "student_id", "window_start", "window_end", "present_count", "absent_count", "percentage", "threshold", "created_at", "resolved_at"}
+
+func TestGetOpenAlertNotFound(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &alertRepository{}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM attendance_alerts WHERE student_id = ? AND resolved_at IS NULL")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(alertRowColumns))
+
+	_, err := repo.GetOpenAlert(1)
+
+	assert.ErrorIs(t, err, ErrAlertNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAlertsOpenOnly(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &alertRepository{}
+
+	open := true
+	mock.ExpectQuery(regexp.QuoteMeta("FROM attendance_alerts WHERE student_id = ? AND resolved_at IS NULL ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?")).
+		WithArgs(int64(1), 10, 0).
+		WillReturnRows(sqlmock.NewRows(alertRowColumns).
+			AddRow(int64(5), int64(1), "2023-10-01", "2023-10-30", 7, 3, 70.0, 75.0, time.Now(), nil))
+
+	alerts, err := repo.GetAlerts(model.AlertFilter{StudentID: 1, Open: &open}, 10, 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, alerts, 1)
+	assert.Equal(t, 70.0, alerts[0].Percentage)
+	assert.Nil(t, alerts[0].ResolvedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetStudentAttendanceCounts(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &alertRepository{}
+
+	// excused statuses count neither as present nor as absent
+	mock.ExpectQuery(regexp.QuoteMeta("CASE WHEN st.counts_as_present = 0 AND st.excused = 0 THEN 1 ELSE 0 END")).
+		WithArgs(int64(1), "2023-10-01", "2023-10-30").
+		WillReturnRows(sqlmock.NewRows([]string{"present", "absent"}).AddRow(7, 3))
+
+	present, absent, err := repo.GetStudentAttendanceCounts(1, "2023-10-01", "2023-10-30")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, present)
+	assert.Equal(t, 3, absent)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,28 +2,66 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"log"
+	"strings"
 	"time"
 
 	configuration "github.com/shravanasati/scopex-go-assignment/configuration"
 	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// ErrAttendanceNotFound indicates that the requested attendance record does
+// not exist in persistent storage.
+var ErrAttendanceNotFound = errors.New("attendance record not found")
+
+// ErrDuplicateAttendance is returned when a student already has attendance
+// recorded for the given date and session.
+var ErrDuplicateAttendance = errors.New("attendance already marked for this student, date and session")
+
+// MySQL server error numbers the attendance repository translates
+const (
+	mysqlErrDuplicateEntry  = 1062
+	mysqlErrRowIsReferenced = 1451
+	mysqlErrNoReferencedRow = 1452
 )
 
-// MarkAttendance records attendance for a student
-func MarkAttendance(attendance model.Attendance) (int64, error) {
+func isMySQLError(err error, number uint16) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == number
+}
+
+// MarkAttendance records attendance for a student. A non-nil flag queues
+// the new record for geofence review in the same transaction.
+func MarkAttendance(attendance model.Attendance, flag *model.AttendanceFlag) (int64, error) {
 	db := configuration.DB
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := "INSERT INTO attendance (student_id, date, status) VALUES (?, ?, ?)"
-	stmt, err := db.PrepareContext(ctx, query)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO attendance (student_id, date, session_id, status) VALUES (?, ?, ?, ?)"
+	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
 		return 0, err
 	}
 	defer stmt.Close()
 
-	result, err := stmt.ExecContext(ctx, attendance.StudentID, attendance.Date, attendance.Status)
+	result, err := stmt.ExecContext(ctx, attendance.StudentID, attendance.Date, attendance.SessionID, attendance.Status)
 	if err != nil {
+		switch {
+		case isMySQLError(err, mysqlErrDuplicateEntry):
+			return 0, ErrDuplicateAttendance
+		case isMySQLError(err, mysqlErrNoReferencedRow):
+			return 0, ErrStudentNotFound
+		}
 		log.Println("Error marking attendance: " + err.Error())
 		return 0, err
 	}
@@ -33,9 +71,419 @@ func MarkAttendance(attendance model.Attendance) (int64, error) {
 		return 0, err
 	}
 
+	if flag != nil {
+		if err := flagAttendance(ctx, tx, id, *flag); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
 	return id, nil
 }
 
+// UpsertAttendance records attendance for a student, overwriting the status
+// of an existing record for the same date and session. A changed status is
+// written to the audit trail. A non-nil flag queues the record for geofence
+// review; otherwise a pending review of the record it overwrites is dropped,
+// since the mark it was raised for is gone. It reports the record ID and
+// whether a new row was created.
+func UpsertAttendance(attendance model.Attendance, flag *model.AttendanceFlag, changedBy int64) (int64, bool, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	audit := `
+		INSERT INTO attendance_audit (attendance_id, student_id, date, action, old_status, new_status, changed_by)
+		SELECT id, student_id, date, ?, status, ?, ? FROM attendance WHERE student_id = ? AND date = ? AND session_id = ? AND status <> ?
+	`
+	_, err = tx.ExecContext(ctx, audit, model.AuditActionUpdate, attendance.Status, changedBy, attendance.StudentID, attendance.Date, attendance.SessionID, attendance.Status)
+	if err != nil {
+		log.Println("Error writing attendance audit: " + err.Error())
+		return 0, false, err
+	}
+
+	// LAST_INSERT_ID(id) makes LastInsertId report the existing row on update
+	query := "INSERT INTO attendance (student_id, date, session_id, status) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id), status = VALUES(status)"
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, false, err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, attendance.StudentID, attendance.Date, attendance.SessionID, attendance.Status)
+	if err != nil {
+		if isMySQLError(err, mysqlErrNoReferencedRow) {
+			return 0, false, ErrStudentNotFound
+		}
+		log.Println("Error upserting attendance: " + err.Error())
+		return 0, false, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, false, err
+	}
+	// MySQL reports 1 affected row for an insert, 2 for an update and 0 when
+	// the existing row already had the requested status.
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, false, err
+	}
+
+	if flag != nil {
+		err = flagAttendance(ctx, tx, id, *flag)
+	} else if affected != 1 {
+		err = clearPendingFlags(ctx, tx, "a.id = ?", id)
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, false, err
+	}
+
+	return id, affected == 1, nil
+}
+
+// MarkAttendanceBulk records attendance for many students on one date and
+// session (0 for the whole day) inside a single transaction. Entries for
+// unknown students or students already marked for that slot are skipped and
+// reported instead of failing the whole batch.
+func MarkAttendanceBulk(date string, sessionID int64, entries []model.BulkAttendanceEntry) ([]model.BulkAttendanceResult, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	studentIDs := make([]any, 0, len(entries))
+	for _, e := range entries {
+		studentIDs = append(studentIDs, e.StudentID)
+	}
+
+	known, err := existingStudentIDs(ctx, tx, studentIDs)
+	if err != nil {
+		log.Println("Error querying students for bulk attendance: " + err.Error())
+		return nil, err
+	}
+
+	marked, err := attendanceIDsByStudent(ctx, tx, date, sessionID, studentIDs, true)
+	if err != nil {
+		log.Println("Error querying attendance for bulk attendance: " + err.Error())
+		return nil, err
+	}
+
+	results := make([]model.BulkAttendanceResult, 0, len(entries))
+	pending := make(map[int64]bool)
+	var insertArgs []any
+
+	for _, e := range entries {
+		r := model.BulkAttendanceResult{StudentID: e.StudentID, Status: e.Status}
+		switch {
+		case !known[e.StudentID]:
+			r.Result = model.BulkResultUnknownStudent
+		case marked[e.StudentID] != 0 || pending[e.StudentID]:
+			r.Result = model.BulkResultDuplicate
+			r.ID = marked[e.StudentID]
+		default:
+			r.Result = model.BulkResultCreated
+			pending[e.StudentID] = true
+			insertArgs = append(insertArgs, e.StudentID, date, sessionID, e.Status)
+		}
+		results = append(results, r)
+	}
+
+	if len(pending) == 0 {
+		return results, tx.Commit()
+	}
+
+	query := "INSERT INTO attendance (student_id, date, session_id, status) VALUES " + placeholderRows(len(pending), 4)
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx, insertArgs...); err != nil {
+		log.Println("Error marking bulk attendance: " + err.Error())
+		return nil, err
+	}
+
+	created, err := attendanceIDsByStudent(ctx, tx, date, sessionID, studentIDs, false)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		if results[i].Result == model.BulkResultCreated {
+			results[i].ID = created[results[i].StudentID]
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// existingStudentIDs returns the subset of the given IDs that exist in students
+func existingStudentIDs(ctx context.Context, tx *sql.Tx, studentIDs []any) (map[int64]bool, error) {
+	query := "SELECT id FROM students WHERE id IN (" + placeholders(len(studentIDs)) + ")"
+	rows, err := tx.QueryContext(ctx, query, studentIDs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	known := make(map[int64]bool)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		known[id] = true
+	}
+
+	return known, rows.Err()
+}
+
+// attendanceIDsByStudent maps student IDs to their attendance row on a date
+// and session. With forUpdate the rows (and the gaps for missing ones) are locked so that
+// concurrent markers cannot slip in between the check and the insert.
+func attendanceIDsByStudent(ctx context.Context, tx *sql.Tx, date string, sessionID int64, studentIDs []any, forUpdate bool) (map[int64]int64, error) {
+	query := "SELECT id, student_id FROM attendance WHERE date = ? AND session_id = ? AND student_id IN (" + placeholders(len(studentIDs)) + ")"
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+
+	args := append([]any{date, sessionID}, studentIDs...)
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[int64]int64)
+	for rows.Next() {
+		var id, studentID int64
+		if err := rows.Scan(&id, &studentID); err != nil {
+			return nil, err
+		}
+		ids[studentID] = id
+	}
+
+	return ids, rows.Err()
+}
+
+// placeholders builds "?, ?, ?" for n bind parameters
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// placeholderRows builds "(?, ?), (?, ?)" for a multi-row insert
+func placeholderRows(rows, cols int) string {
+	row := "(" + placeholders(cols) + ")"
+	return strings.TrimSuffix(strings.Repeat(row+", ", rows), ", ")
+}
+
+// GetAttendanceByID retrieves a single attendance record
+func GetAttendanceByID(id int64) (model.Attendance, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var a model.Attendance
+
+	query := "SELECT id, student_id, date, session_id, status FROM attendance WHERE id = ?"
+	err := db.QueryRowContext(ctx, query, id).Scan(&a.ID, &a.StudentID, &a.Date, &a.SessionID, &a.Status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return a, ErrAttendanceNotFound
+		}
+		log.Println("Error querying attendance by ID: " + err.Error())
+		return a, err
+	}
+
+	return a, nil
+}
+
+// GetAttendanceByStudentAndDate retrieves the attendance record of a student
+// for one date and session (0 for the whole day)
+func GetAttendanceByStudentAndDate(studentID int64, date string, sessionID int64) (model.Attendance, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var a model.Attendance
+
+	query := "SELECT id, student_id, date, session_id, status FROM attendance WHERE student_id = ? AND date = ? AND session_id = ?"
+	err := db.QueryRowContext(ctx, query, studentID, date, sessionID).Scan(&a.ID, &a.StudentID, &a.Date, &a.SessionID, &a.Status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return a, ErrAttendanceNotFound
+		}
+		log.Println("Error querying attendance by student and date: " + err.Error())
+		return a, err
+	}
+
+	return a, nil
+}
+
+// UpdateAttendanceStatus corrects the status of an attendance record and
+// records the change in the audit trail within the same transaction. A
+// pending geofence review of the record is dropped with the old status.
+func UpdateAttendanceStatus(id int64, status string, changedBy int64) (model.Attendance, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.Attendance{}, err
+	}
+	defer tx.Rollback()
+
+	current, err := lockAttendance(ctx, tx, id)
+	if err != nil {
+		return model.Attendance{}, err
+	}
+
+	if err := insertAttendanceAudit(ctx, tx, id, model.AuditActionUpdate, status, changedBy); err != nil {
+		return model.Attendance{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE attendance SET status = ? WHERE id = ?", status, id); err != nil {
+		log.Println("Error updating attendance: " + err.Error())
+		return model.Attendance{}, err
+	}
+
+	if err := clearPendingFlags(ctx, tx, "a.id = ?", id); err != nil {
+		return model.Attendance{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Attendance{}, err
+	}
+
+	current.Status = status
+	return current, nil
+}
+
+// DeleteAttendance removes an attendance record, keeping its last state in
+// the audit trail
+func DeleteAttendance(id int64, changedBy int64) (model.Attendance, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.Attendance{}, err
+	}
+	defer tx.Rollback()
+
+	deleted, err := lockAttendance(ctx, tx, id)
+	if err != nil {
+		return model.Attendance{}, err
+	}
+
+	if err := insertAttendanceAudit(ctx, tx, id, model.AuditActionDelete, "", changedBy); err != nil {
+		return model.Attendance{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM attendance WHERE id = ?", id); err != nil {
+		log.Println("Error deleting attendance: " + err.Error())
+		return model.Attendance{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Attendance{}, err
+	}
+
+	return deleted, nil
+}
+
+// GetAttendanceAudit retrieves the change history of an attendance record,
+// oldest change first
+func GetAttendanceAudit(attendanceID int64) (model.AttendanceAudits, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var audits model.AttendanceAudits
+
+	query := "SELECT id, attendance_id, student_id, date, action, old_status, new_status, changed_by, changed_at FROM attendance_audit WHERE attendance_id = ? ORDER BY changed_at ASC, id ASC"
+	rows, err := db.QueryContext(ctx, query, attendanceID)
+	if err != nil {
+		log.Println("Error querying attendance audit: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a model.AttendanceAudit
+		var newStatus sql.NullString
+		err := rows.Scan(&a.ID, &a.AttendanceID, &a.StudentID, &a.Date, &a.Action, &a.OldStatus, &newStatus, &a.ChangedBy, &a.ChangedAt)
+		if err != nil {
+			log.Println("Error scanning attendance audit: " + err.Error())
+			return nil, err
+		}
+		a.NewStatus = newStatus.String
+		audits = append(audits, a)
+	}
+
+	return audits, nil
+}
+
+// lockAttendance reads an attendance record and locks it for the rest of tx
+func lockAttendance(ctx context.Context, tx *sql.Tx, id int64) (model.Attendance, error) {
+	var a model.Attendance
+
+	query := "SELECT id, student_id, date, session_id, status FROM attendance WHERE id = ? FOR UPDATE"
+	err := tx.QueryRowContext(ctx, query, id).Scan(&a.ID, &a.StudentID, &a.Date, &a.SessionID, &a.Status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return a, ErrAttendanceNotFound
+		}
+		return a, err
+	}
+
+	return a, nil
+}
+
+// insertAttendanceAudit snapshots the current state of a record into the
+// audit trail. It must run before the record itself is modified.
+func insertAttendanceAudit(ctx context.Context, tx *sql.Tx, attendanceID int64, action, newStatus string, changedBy int64) error {
+	query := `
+		INSERT INTO attendance_audit (attendance_id, student_id, date, action, old_status, new_status, changed_by)
+		SELECT id, student_id, date, ?, status, ?, ? FROM attendance WHERE id = ?
+	`
+
+	var next sql.NullString
+	if newStatus != "" {
+		next = sql.NullString{String: newStatus, Valid: true}
+	}
+
+	_, err := tx.ExecContext(ctx, query, action, next, changedBy, attendanceID)
+	if err != nil {
+		log.Println("Error writing attendance audit: " + err.Error())
+	}
+	return err
+}
+
 // GetAttendanceByStudentID retrieves attendance records for a student
 func GetAttendanceByStudentID(studentID int64) (model.Attendances, error) {
 	db := configuration.DB
@@ -44,7 +492,7 @@ func GetAttendanceByStudentID(studentID int64) (model.Attendances, error) {
 
 	var attendances model.Attendances
 
-	query := "SELECT id, student_id, date, status FROM attendance WHERE student_id = ? ORDER BY date DESC"
+	query := "SELECT id, student_id, date, session_id, status FROM attendance WHERE student_id = ? ORDER BY date DESC, session_id ASC"
 	rows, err := db.QueryContext(ctx, query, studentID)
 	if err != nil {
 		log.Println("Error querying attendance: " + err.Error())
@@ -58,7 +506,7 @@ func GetAttendanceByStudentID(studentID int64) (model.Attendances, error) {
 		// Since we used ?parseTime=true, it should be time.Time, but we defined Date as string in struct.
 		// We might need to scan into a temporary variable or change struct.
 		// Let's scan into a string, MySQL driver usually handles this conversion if the target is string.
-		err := rows.Scan(&a.ID, &a.StudentID, &a.Date, &a.Status)
+		err := rows.Scan(&a.ID, &a.StudentID, &a.Date, &a.SessionID, &a.Status)
 		if err != nil {
 			log.Println("Error scanning attendance: " + err.Error())
 			return nil, err
@@ -69,6 +517,73 @@ func GetAttendanceByStudentID(studentID int64) (model.Attendances, error) {
 	return attendances, nil
 }
 
+// GetAttendancePage retrieves one page of attendance records matching the
+// filter, newest first, together with the total number of matching records
+func GetAttendancePage(filter model.AttendanceFilter, limit, offset int) (model.Attendances, int, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	where, args := attendanceFilterClause(filter)
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM attendance" + where
+	if err := db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		log.Println("Error counting attendance: " + err.Error())
+		return nil, 0, err
+	}
+
+	attendances := model.Attendances{}
+
+	query := "SELECT id, student_id, date, session_id, status FROM attendance" + where + " ORDER BY date DESC, session_id ASC LIMIT ? OFFSET ?"
+	rows, err := db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		log.Println("Error querying attendance: " + err.Error())
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a model.Attendance
+		err := rows.Scan(&a.ID, &a.StudentID, &a.Date, &a.SessionID, &a.Status)
+		if err != nil {
+			log.Println("Error scanning attendance: " + err.Error())
+			return nil, 0, err
+		}
+		attendances = append(attendances, a)
+	}
+
+	return attendances, total, nil
+}
+
+// attendanceFilterClause builds the WHERE clause and bind arguments for a filter
+func attendanceFilterClause(filter model.AttendanceFilter) (string, []any) {
+	var conditions []string
+	var args []any
+
+	if filter.StudentID != 0 {
+		conditions = append(conditions, "student_id = ?")
+		args = append(args, filter.StudentID)
+	}
+	if filter.From != "" {
+		conditions = append(conditions, "date >= ?")
+		args = append(args, filter.From)
+	}
+	if filter.To != "" {
+		conditions = append(conditions, "date <= ?")
+		args = append(args, filter.To)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
 // GetAttendanceByDateRange retrieves attendance for a student within a date range
 func GetAttendanceByDateRange(studentID int64, startDate, endDate string) (model.Attendances, error) {
 	db := configuration.DB
@@ -77,7 +592,7 @@ func GetAttendanceByDateRange(studentID int64, startDate, endDate string) (model
 
 	var attendances model.Attendances
 
-	query := "SELECT id, student_id, date, status FROM attendance WHERE student_id = ? AND date BETWEEN ? AND ? ORDER BY date ASC"
+	query := "SELECT id, student_id, date, session_id, status FROM attendance WHERE student_id = ? AND date BETWEEN ? AND ? ORDER BY date ASC, session_id ASC"
 	rows, err := db.QueryContext(ctx, query, studentID, startDate, endDate)
 	if err != nil {
 		return nil, err
@@ -86,7 +601,7 @@ func GetAttendanceByDateRange(studentID int64, startDate, endDate string) (model
 
 	for rows.Next() {
 		var a model.Attendance
-		err := rows.Scan(&a.ID, &a.StudentID, &a.Date, &a.Status)
+		err := rows.Scan(&a.ID, &a.StudentID, &a.Date, &a.SessionID, &a.Status)
 		if err != nil {
 			return nil, err
 		}
@@ -96,32 +611,44 @@ func GetAttendanceByDateRange(studentID int64, startDate, endDate string) (model
 	return attendances, nil
 }
 
-// GetAttendanceReport retrieves aggregated attendance data for all students within a date range
+// GetAttendanceReport retrieves aggregated attendance data for all students within a date range.
+// Present and absent totals follow the counts_as_present flag of the status
+// catalogue, leaving out excused statuses, and each report carries the
+// per-status breakdown.
 func GetAttendanceReport(startDate, endDate string) (model.AttendanceReports, error) {
+	return GetScopedAttendanceReport(model.ReportScope{}, startDate, endDate)
+}
+
+// GetScopedAttendanceReport is GetAttendanceReport narrowed to the students,
+// and for a section the sessions, of a report scope.
+func GetScopedAttendanceReport(scope model.ReportScope, startDate, endDate string) (model.AttendanceReports, error) {
 	db := configuration.DB
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second) // Longer timeout for report
 	defer cancel()
 
 	var reports model.AttendanceReports
 
+	join, attendanceFilter, where := reportScopeClauses(scope)
 	query := `
 		SELECT 
 			s.id, 
 			s.name, 
 			s.email, 
-			COALESCE(SUM(CASE WHEN a.status = 'Present' THEN 1 ELSE 0 END), 0) as present_count,
-			COALESCE(SUM(CASE WHEN a.status = 'Absent' THEN 1 ELSE 0 END), 0) as absent_count
+			COALESCE(SUM(CASE WHEN st.counts_as_present = 1 THEN 1 ELSE 0 END), 0) as present_count,
+			COALESCE(SUM(CASE WHEN st.counts_as_present = 0 AND st.excused = 0 THEN 1 ELSE 0 END), 0) as absent_count
 		FROM 
-			students s
+			students s` + join + `
 		LEFT JOIN 
-			attendance a ON s.id = a.student_id AND a.date BETWEEN ? AND ?
+			attendance a ON s.id = a.student_id AND a.date BETWEEN ? AND ?` + attendanceFilter + `
+		LEFT JOIN 
+			attendance_statuses st ON st.code = a.status` + where + `
 		GROUP BY 
 			s.id, s.name, s.email
 		ORDER BY 
 			s.name ASC
 	`
 
-	rows, err := db.QueryContext(ctx, query, startDate, endDate)
+	rows, err := db.QueryContext(ctx, query, reportScopeArgs(scope, startDate, endDate)...)
 	if err != nil {
 		log.Println("Error querying attendance report: " + err.Error())
 		return nil, err
@@ -138,5 +665,456 @@ func GetAttendanceReport(startDate, endDate string) (model.AttendanceReports, er
 		reports = append(reports, r)
 	}
 
+	if err := attachStatusCounts(ctx, reports, startDate, endDate, scope.SectionID); err != nil {
+		return nil, err
+	}
+
 	return reports, nil
 }
+
+// GetDailyAttendanceReport is GetAttendanceReport rolled up by day instead of
+// by record: a day counts as present when at least minPresentPercent of that
+// day's sessions were attended, and as absent otherwise. Excused records are
+// left out of a day's sessions, and a day with only excused records counts
+// as neither. StatusCounts still counts individual records.
+func GetDailyAttendanceReport(startDate, endDate string, minPresentPercent float64) (model.AttendanceReports, error) {
+	return GetScopedDailyAttendanceReport(model.ReportScope{}, startDate, endDate, minPresentPercent)
+}
+
+// GetScopedDailyAttendanceReport is GetDailyAttendanceReport narrowed to a
+// report scope. For a section only the days its sessions were marked count.
+func GetScopedDailyAttendanceReport(scope model.ReportScope, startDate, endDate string, minPresentPercent float64) (model.AttendanceReports, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second) // Longer timeout for report
+	defer cancel()
+
+	var reports model.AttendanceReports
+
+	join, attendanceFilter, where := reportScopeClauses(scope)
+	query := `
+		SELECT 
+			s.id, 
+			s.name, 
+			s.email, 
+			COALESCE(SUM(CASE WHEN d.present_ratio * 100 >= ? THEN 1 ELSE 0 END), 0) as present_count,
+			COALESCE(SUM(CASE WHEN d.present_ratio * 100 < ? THEN 1 ELSE 0 END), 0) as absent_count
+		FROM 
+			students s` + join + `
+		LEFT JOIN (
+			SELECT 
+				a.student_id, 
+				a.date, 
+				SUM(st.counts_as_present) / NULLIF(SUM(1 - st.excused), 0) as present_ratio
+			FROM 
+				attendance a
+			JOIN 
+				attendance_statuses st ON st.code = a.status
+			WHERE 
+				a.date BETWEEN ? AND ?` + attendanceFilter + `
+			GROUP BY 
+				a.student_id, a.date
+		) d ON s.id = d.student_id` + where + `
+		GROUP BY 
+			s.id, s.name, s.email
+		ORDER BY 
+			s.name ASC
+	`
+
+	args := append([]any{minPresentPercent, minPresentPercent}, reportScopeArgs(scope, startDate, endDate)...)
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error querying daily attendance report: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r model.AttendanceReport
+		err := rows.Scan(&r.StudentID, &r.StudentName, &r.StudentEmail, &r.PresentCount, &r.AbsentCount)
+		if err != nil {
+			log.Println("Error scanning daily attendance report: " + err.Error())
+			return nil, err
+		}
+		reports = append(reports, r)
+	}
+
+	if err := attachStatusCounts(ctx, reports, startDate, endDate, scope.SectionID); err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// sectionSessions restricts attendance to the sessions of one section
+const sectionSessions = "a.session_id IN (SELECT id FROM sessions WHERE section_id = ?)"
+
+// reportScopeClauses returns the SQL a report scope adds to the report
+// queries: a join limiting students to a section's enrollments, a filter on
+// the attendance rows and a WHERE clause on the students
+func reportScopeClauses(scope model.ReportScope) (join, attendance, where string) {
+	if scope.SectionID != 0 {
+		join = " JOIN enrollments e ON e.student_id = s.id AND e.section_id = ?"
+		attendance = " AND " + sectionSessions
+	}
+	if scope.DepartmentID != 0 {
+		where = " WHERE s.department_id = ?"
+	}
+	return join, attendance, where
+}
+
+// reportScopeArgs orders the bind arguments of reportScopeClauses around the
+// date range
+func reportScopeArgs(scope model.ReportScope, startDate, endDate string) []any {
+	var args []any
+	if scope.SectionID != 0 {
+		args = append(args, scope.SectionID)
+	}
+	args = append(args, startDate, endDate)
+	if scope.SectionID != 0 {
+		args = append(args, scope.SectionID)
+	}
+	if scope.DepartmentID != 0 {
+		args = append(args, scope.DepartmentID)
+	}
+	return args
+}
+
+// attachStatusCounts fills in the per-status breakdown of each report, with
+// an entry for every catalogue status so zero counts are shown too. A
+// non-zero sectionID counts only the section's sessions.
+func attachStatusCounts(ctx context.Context, reports model.AttendanceReports, startDate, endDate string, sectionID int64) error {
+	counts, err := countStatusesByStudent(ctx, startDate, endDate, sectionID)
+	if err != nil {
+		log.Println("Error querying attendance status breakdown: " + err.Error())
+		return err
+	}
+
+	statuses, err := AttendanceStatusRepo.GetStatuses()
+	if err != nil {
+		return err
+	}
+
+	for i := range reports {
+		breakdown := make([]model.StatusCount, 0, len(statuses))
+		for _, st := range statuses {
+			breakdown = append(breakdown, model.StatusCount{
+				Code:            st.Code,
+				Label:           st.Label,
+				CountsAsPresent: st.CountsAsPresent,
+				Excused:         st.Excused,
+				Count:           counts[reports[i].StudentID][st.Code],
+			})
+		}
+		reports[i].StatusCounts = breakdown
+	}
+
+	return nil
+}
+
+// countStatusesByStudent counts each student's records per status in a date
+// range, optionally only for the sessions of one section
+func countStatusesByStudent(ctx context.Context, startDate, endDate string, sectionID int64) (map[int64]map[string]int, error) {
+	db := configuration.DB
+
+	query := "SELECT student_id, status, COUNT(*) FROM attendance WHERE date BETWEEN ? AND ? GROUP BY student_id, status"
+	args := []any{startDate, endDate}
+	if sectionID != 0 {
+		query = "SELECT student_id, status, COUNT(*) FROM attendance a WHERE date BETWEEN ? AND ? AND " + sectionSessions + " GROUP BY student_id, status"
+		args = append(args, sectionID)
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]map[string]int)
+	for rows.Next() {
+		var studentID int64
+		var status string
+		var count int
+		if err := rows.Scan(&studentID, &status, &count); err != nil {
+			return nil, err
+		}
+		if counts[studentID] == nil {
+			counts[studentID] = make(map[string]int)
+		}
+		counts[studentID][status] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// GetMarkedDates returns, per student, the days in a date range that have at
+// least one attendance record
+func GetMarkedDates(startDate, endDate string) (map[int64]map[string]bool, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := "SELECT DISTINCT student_id, DATE_FORMAT(date, '%Y-%m-%d') FROM attendance WHERE date BETWEEN ? AND ?"
+	rows, err := db.QueryContext(ctx, query, startDate, endDate)
+	if err != nil {
+		log.Println("Error querying marked dates: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	marked := make(map[int64]map[string]bool)
+	for rows.Next() {
+		var studentID int64
+		var date string
+		if err := rows.Scan(&studentID, &date); err != nil {
+			log.Println("Error scanning marked date: " + err.Error())
+			return nil, err
+		}
+		if marked[studentID] == nil {
+			marked[studentID] = make(map[string]bool)
+		}
+		marked[studentID][date] = true
+	}
+
+	return marked, rows.Err()
+}
+
+// ImportAttendanceBatch inserts whole-day attendance records in one
+// statement and returns the records it inserted. Records that already exist,
+// or repeat an earlier record of the batch, are left untouched and not
+// returned; the existing rows are locked first so the result names exactly
+// the rows written.
+func ImportAttendanceBatch(records model.Attendances) (model.Attendances, error) {
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	args := make([]any, 0, len(records)*2)
+	for _, a := range records {
+		args = append(args, a.StudentID, a.Date)
+	}
+	query := "SELECT student_id, DATE_FORMAT(date, '%Y-%m-%d') FROM attendance WHERE session_id = 0 AND (student_id, date) IN (" + placeholderRows(len(records), 2) + ") FOR UPDATE"
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error importing attendance batch: " + err.Error())
+		return nil, err
+	}
+	type dayKey struct {
+		studentID int64
+		date      string
+	}
+	taken := make(map[dayKey]bool)
+	for rows.Next() {
+		var studentID int64
+		var date string
+		if err := rows.Scan(&studentID, &date); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		taken[dayKey{studentID, date}] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var fresh model.Attendances
+	args = args[:0]
+	for _, a := range records {
+		key := dayKey{a.StudentID, a.Date}
+		if taken[key] {
+			continue
+		}
+		taken[key] = true
+		fresh = append(fresh, a)
+		args = append(args, a.StudentID, a.Date, a.SessionID, a.Status)
+	}
+	if len(fresh) == 0 {
+		return nil, nil
+	}
+
+	// id = id keeps a row inserted since the lock from failing the batch
+	query = "INSERT INTO attendance (student_id, date, session_id, status) VALUES " + placeholderRows(len(fresh), 4) + " ON DUPLICATE KEY UPDATE id = id"
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Println("Error importing attendance batch: " + err.Error())
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return fresh, nil
+}
+
+// ImportAttendanceRecord inserts one whole-day attendance record like
+// ImportAttendanceBatch and returns its ID, or 0 when the record already
+// exists and was left untouched.
+func ImportAttendanceRecord(a model.Attendance) (int64, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := db.ExecContext(ctx, "INSERT INTO attendance (student_id, date, session_id, status) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE id = id",
+		a.StudentID, a.Date, a.SessionID, a.Status)
+	if err != nil {
+		log.Println("Error importing attendance record: " + err.Error())
+		return 0, err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if inserted == 0 {
+		return 0, nil
+	}
+
+	return result.LastInsertId()
+}
+
+// CloseSession marks every student of the session's department who has no
+// record for the session, and no whole-day record on its date, as Absent and
+// then closes the session. The department is the one of the section's course,
+// or the session's own department when it has no section; a session with
+// neither is closed without marks. It returns the students it marked, leaving
+// out any marked for the session meanwhile; a session closed meanwhile is left
+// alone and reports none.
+func CloseSession(sessionID int64) ([]int64, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "UPDATE sessions SET closed_at = CURRENT_TIMESTAMP WHERE id = ? AND closed_at IS NULL", sessionID)
+	if err != nil {
+		log.Println("Error closing session: " + err.Error())
+		return nil, err
+	}
+	closed, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if closed == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT s.id, DATE_FORMAT(se.date, '%Y-%m-%d')
+		FROM sessions se
+		LEFT JOIN sections sc ON sc.id = se.section_id
+		LEFT JOIN courses c ON c.id = sc.course_id
+		JOIN students s ON s.department_id = COALESCE(c.department_id, se.department_id)
+		WHERE se.id = ?
+			AND NOT EXISTS (
+				SELECT 1 FROM attendance a
+				WHERE a.student_id = s.id AND a.date = se.date AND a.session_id IN (0, se.id)
+			)
+		ORDER BY s.id
+	`
+	rows, err := tx.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		log.Println("Error finding session absentees: " + err.Error())
+		return nil, err
+	}
+	var absentees model.Attendances
+	for rows.Next() {
+		a := model.Attendance{SessionID: sessionID, Status: model.StatusAbsent}
+		if err := rows.Scan(&a.StudentID, &a.Date); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		absentees = append(absentees, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var studentIDs []int64
+	if len(absentees) > 0 {
+		// id = id leaves a mark made since the absentees were read in place
+		// instead of failing the close; only the rows inserted count
+		insert := "INSERT INTO attendance (student_id, date, session_id, status) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE id = id"
+		stmt, err := tx.PrepareContext(ctx, insert)
+		if err != nil {
+			return nil, err
+		}
+		defer stmt.Close()
+
+		for _, a := range absentees {
+			result, err := stmt.ExecContext(ctx, a.StudentID, a.Date, a.SessionID, a.Status)
+			if err != nil {
+				log.Println("Error marking session absentees: " + err.Error())
+				return nil, err
+			}
+			inserted, err := result.RowsAffected()
+			if err != nil {
+				return nil, err
+			}
+			if inserted == 1 {
+				studentIDs = append(studentIDs, a.StudentID)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return studentIDs, nil
+}
+
+// ExportAttendance streams the attendance records in the filter's date range,
+// joined with their students, to emit one row at a time. Rows are read from
+// the cursor as they are emitted, so memory use does not grow with the size
+// of the export. The caller's context bounds the query instead of the usual
+// fixed timeout because large exports take as long as the client reads.
+func ExportAttendance(ctx context.Context, filter model.AttendanceExportFilter, emit func(row model.AttendanceExportRow) error) error {
+	db := configuration.DB
+
+	query := `
+		SELECT a.id, a.student_id, s.name, s.email, COALESCE(d.name, ''), DATE_FORMAT(a.date, '%Y-%m-%d'), a.session_id, a.status
+		FROM attendance a
+		JOIN students s ON s.id = a.student_id
+		LEFT JOIN departments d ON d.id = s.department_id
+		WHERE a.date BETWEEN ? AND ?`
+	args := []any{filter.From, filter.To}
+	if filter.DepartmentID != 0 {
+		query += " AND s.department_id = ?"
+		args = append(args, filter.DepartmentID)
+	}
+	query += " ORDER BY a.date ASC, s.name ASC, a.student_id ASC, a.session_id ASC"
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error querying attendance export: " + err.Error())
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r model.AttendanceExportRow
+		err := rows.Scan(&r.AttendanceID, &r.StudentID, &r.StudentName, &r.StudentEmail, &r.Department, &r.Date, &r.SessionID, &r.Status)
+		if err != nil {
+			log.Println("Error scanning attendance export: " + err.Error())
+			return err
+		}
+		if err := emit(r); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
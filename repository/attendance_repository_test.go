@@ -1,14 +1,17 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"regexp"
 	"testing"
+	"time"
 
 	configuration "github.com/shravanasati/scopex-go-assignment/configuration"
 	model "github.com/shravanasati/scopex-go-assignment/model"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -35,12 +38,14 @@ func TestMarkAttendanceSuccess(t *testing.T) {
 		Status:    "Present",
 	}
 
-	prep := mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO attendance (student_id, date, status) VALUES (?, ?, ?)"))
+	mock.ExpectBegin()
+	prep := mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO attendance (student_id, date, session_id, status) VALUES (?, ?, ?, ?)"))
 	prep.ExpectExec().
-		WithArgs(attendance.StudentID, attendance.Date, attendance.Status).
+		WithArgs(attendance.StudentID, attendance.Date, attendance.SessionID, attendance.Status).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
-	id, err := MarkAttendance(attendance)
+	id, err := MarkAttendance(attendance, nil)
 
 	assert.NoError(t, err)
 	assert.Equal(t, int64(1), id)
@@ -56,27 +61,258 @@ func TestMarkAttendanceError(t *testing.T) {
 		Status:    "Present",
 	}
 
-	prep := mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO attendance (student_id, date, status) VALUES (?, ?, ?)"))
+	mock.ExpectBegin()
+	prep := mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO attendance (student_id, date, session_id, status) VALUES (?, ?, ?, ?)"))
 	prep.ExpectExec().
-		WithArgs(attendance.StudentID, attendance.Date, attendance.Status).
+		WithArgs(attendance.StudentID, attendance.Date, attendance.SessionID, attendance.Status).
 		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
 
-	id, err := MarkAttendance(attendance)
+	id, err := MarkAttendance(attendance, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, int64(0), id)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestMarkAttendanceDuplicate(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	attendance := model.Attendance{
+		StudentID: 1,
+		Date:      "2023-10-27",
+		Status:    "Present",
+	}
+
+	mock.ExpectBegin()
+	prep := mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO attendance (student_id, date, session_id, status) VALUES (?, ?, ?, ?)"))
+	prep.ExpectExec().
+		WithArgs(attendance.StudentID, attendance.Date, attendance.SessionID, attendance.Status).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1-2023-10-27' for key 'unique_attendance'"})
+	mock.ExpectRollback()
+
+	_, err := MarkAttendance(attendance, nil)
+
+	assert.ErrorIs(t, err, ErrDuplicateAttendance)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertAttendance(t *testing.T) {
+	attendance := model.Attendance{
+		StudentID: 1,
+		Date:      "2023-10-27",
+		Status:    "Absent",
+	}
+
+	tests := []struct {
+		name     string
+		affected int64
+		created  bool
+	}{
+		{"inserted", 1, true},
+		{"updated", 2, false},
+		{"unchanged", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, _ := setupAttendanceSQLMock(t)
+
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta("SELECT id, student_id, date, ?, status, ?, ? FROM attendance WHERE student_id = ? AND date = ? AND session_id = ? AND status <> ?")).
+				WithArgs(model.AuditActionUpdate, "Absent", int64(3), attendance.StudentID, attendance.Date, attendance.SessionID, "Absent").
+				WillReturnResult(sqlmock.NewResult(0, tt.affected/2))
+			prep := mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO attendance (student_id, date, session_id, status) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id), status = VALUES(status)"))
+			prep.ExpectExec().
+				WithArgs(attendance.StudentID, attendance.Date, attendance.SessionID, attendance.Status).
+				WillReturnResult(sqlmock.NewResult(9, tt.affected))
+			if !tt.created {
+				// the overwritten mark's pending review goes with it
+				mock.ExpectExec(regexp.QuoteMeta("DELETE f FROM attendance_flags f JOIN attendance a ON a.id = f.attendance_id WHERE a.flag = ? AND a.id = ?")).
+					WithArgs(model.FlagStatePending, int64(9)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE attendance a SET a.flag = NULL WHERE a.flag = ? AND a.id = ?")).
+					WithArgs(model.FlagStatePending, int64(9)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			mock.ExpectCommit()
+
+			id, created, err := UpsertAttendance(attendance, nil, 3)
+
+			assert.NoError(t, err)
+			assert.Equal(t, int64(9), id)
+			assert.Equal(t, tt.created, created)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMarkAttendanceBulkReportsEachEntry(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	date := "2023-10-27"
+	entries := []model.BulkAttendanceEntry{
+		{StudentID: 1, Status: "Present"},
+		{StudentID: 2, Status: "Absent"},
+		{StudentID: 3, Status: "Present"},
+		{StudentID: 4, Status: "Present"},
+		{StudentID: 1, Status: "Absent"},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM students WHERE id IN (?, ?, ?, ?, ?)")).
+		WithArgs(int64(1), int64(2), int64(3), int64(4), int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)).AddRow(int64(2)).AddRow(int64(3)))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id FROM attendance WHERE date = ? AND session_id = ? AND student_id IN (?, ?, ?, ?, ?) FOR UPDATE")).
+		WithArgs(date, int64(0), int64(1), int64(2), int64(3), int64(4), int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "student_id"}).AddRow(int64(7), int64(3)))
+	prep := mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO attendance (student_id, date, session_id, status) VALUES (?, ?, ?, ?), (?, ?, ?, ?)"))
+	prep.ExpectExec().
+		WithArgs(int64(1), date, int64(0), "Present", int64(2), date, int64(0), "Absent").
+		WillReturnResult(sqlmock.NewResult(10, 2))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id FROM attendance WHERE date = ? AND session_id = ? AND student_id IN (?, ?, ?, ?, ?)")).
+		WithArgs(date, int64(0), int64(1), int64(2), int64(3), int64(4), int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "student_id"}).AddRow(int64(7), int64(3)).AddRow(int64(10), int64(1)).AddRow(int64(11), int64(2)))
+	mock.ExpectCommit()
+
+	results, err := MarkAttendanceBulk(date, 0, entries)
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 5)
+	assert.Equal(t, model.BulkResultCreated, results[0].Result)
+	assert.Equal(t, int64(10), results[0].ID)
+	assert.Equal(t, model.BulkResultCreated, results[1].Result)
+	assert.Equal(t, int64(11), results[1].ID)
+	assert.Equal(t, model.BulkResultDuplicate, results[2].Result)
+	assert.Equal(t, int64(7), results[2].ID)
+	assert.Equal(t, model.BulkResultUnknownStudent, results[3].Result)
+	assert.Equal(t, model.BulkResultDuplicate, results[4].Result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkAttendanceBulkRollsBackOnError(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	date := "2023-10-27"
+	entries := []model.BulkAttendanceEntry{{StudentID: 1, Status: "Present"}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM students WHERE id IN (?)")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id FROM attendance WHERE date = ? AND session_id = ? AND student_id IN (?) FOR UPDATE")).
+		WithArgs(date, int64(0), int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "student_id"}))
+	prep := mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO attendance (student_id, date, session_id, status) VALUES (?, ?, ?, ?)"))
+	prep.ExpectExec().
+		WithArgs(int64(1), date, int64(0), "Present").
+		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	results, err := MarkAttendanceBulk(date, 0, entries)
+
+	assert.Error(t, err)
+	assert.Nil(t, results)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateAttendanceStatusWritesAudit(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, date, session_id, status FROM attendance WHERE id = ? FOR UPDATE")).
+		WithArgs(int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "student_id", "date", "session_id", "status"}).AddRow(int64(5), int64(1), "2023-10-27", int64(0), "Present"))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT id, student_id, date, ?, status, ?, ? FROM attendance WHERE id = ?")).
+		WithArgs(model.AuditActionUpdate, "Absent", int64(3), int64(5)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE attendance SET status = ? WHERE id = ?")).
+		WithArgs("Absent", int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE f FROM attendance_flags f JOIN attendance a ON a.id = f.attendance_id WHERE a.flag = ? AND a.id = ?")).
+		WithArgs(model.FlagStatePending, int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE attendance a SET a.flag = NULL WHERE a.flag = ? AND a.id = ?")).
+		WithArgs(model.FlagStatePending, int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	updated, err := UpdateAttendanceStatus(5, "Absent", 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Absent", updated.Status)
+	assert.Equal(t, int64(1), updated.StudentID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateAttendanceStatusNotFound(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, date, session_id, status FROM attendance WHERE id = ? FOR UPDATE")).
+		WithArgs(int64(5)).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, err := UpdateAttendanceStatus(5, "Absent", 3)
+
+	assert.ErrorIs(t, err, ErrAttendanceNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteAttendanceWritesAudit(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, date, session_id, status FROM attendance WHERE id = ? FOR UPDATE")).
+		WithArgs(int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "student_id", "date", "session_id", "status"}).AddRow(int64(5), int64(1), "2023-10-27", int64(0), "Present"))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT id, student_id, date, ?, status, ?, ? FROM attendance WHERE id = ?")).
+		WithArgs(model.AuditActionDelete, nil, int64(3), int64(5)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM attendance WHERE id = ?")).
+		WithArgs(int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	deleted, err := DeleteAttendance(5, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), deleted.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAttendanceAuditSuccess(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	changedAt := time.Date(2023, 10, 28, 9, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "attendance_id", "student_id", "date", "action", "old_status", "new_status", "changed_by", "changed_at"}).
+		AddRow(int64(1), int64(5), int64(1), "2023-10-27", "update", "Present", "Absent", int64(3), changedAt).
+		AddRow(int64(2), int64(5), int64(1), "2023-10-27", "delete", "Absent", nil, int64(3), changedAt)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, attendance_id, student_id, date, action, old_status, new_status, changed_by, changed_at FROM attendance_audit WHERE attendance_id = ? ORDER BY changed_at ASC, id ASC")).
+		WithArgs(int64(5)).
+		WillReturnRows(rows)
+
+	audits, err := GetAttendanceAudit(5)
+
+	assert.NoError(t, err)
+	assert.Len(t, audits, 2)
+	assert.Equal(t, "Absent", audits[0].NewStatus)
+	assert.Equal(t, "", audits[1].NewStatus)
+	assert.Equal(t, model.AuditActionDelete, audits[1].Action)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetAttendanceByStudentIDSuccess(t *testing.T) {
 	mock, _ := setupAttendanceSQLMock(t)
 
 	studentID := int64(1)
-	rows := sqlmock.NewRows([]string{"id", "student_id", "date", "status"}).
-		AddRow(int64(1), studentID, "2023-10-27", "Present").
-		AddRow(int64(2), studentID, "2023-10-26", "Absent")
+	rows := sqlmock.NewRows([]string{"id", "student_id", "date", "session_id", "status"}).
+		AddRow(int64(1), studentID, "2023-10-27", int64(0), "Present").
+		AddRow(int64(2), studentID, "2023-10-26", int64(0), "Absent")
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, date, status FROM attendance WHERE student_id = ? ORDER BY date DESC")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, date, session_id, status FROM attendance WHERE student_id = ? ORDER BY date DESC, session_id ASC")).
 		WithArgs(studentID).
 		WillReturnRows(rows)
 
@@ -94,9 +330,9 @@ func TestGetAttendanceByStudentIDNotFound(t *testing.T) {
 	mock, _ := setupAttendanceSQLMock(t)
 
 	studentID := int64(999)
-	rows := sqlmock.NewRows([]string{"id", "student_id", "date", "status"})
+	rows := sqlmock.NewRows([]string{"id", "student_id", "date", "session_id", "status"})
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, date, status FROM attendance WHERE student_id = ? ORDER BY date DESC")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, date, session_id, status FROM attendance WHERE student_id = ? ORDER BY date DESC, session_id ASC")).
 		WithArgs(studentID).
 		WillReturnRows(rows)
 
@@ -112,7 +348,7 @@ func TestGetAttendanceByStudentIDError(t *testing.T) {
 
 	studentID := int64(1)
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, date, status FROM attendance WHERE student_id = ? ORDER BY date DESC")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, date, session_id, status FROM attendance WHERE student_id = ? ORDER BY date DESC, session_id ASC")).
 		WithArgs(studentID).
 		WillReturnError(sql.ErrConnDone)
 
@@ -123,6 +359,29 @@ func TestGetAttendanceByStudentIDError(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetAttendancePageAppliesFilter(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	filter := model.AttendanceFilter{StudentID: 1, From: "2023-10-01", To: "2023-10-31", Status: "Absent"}
+	where := " WHERE student_id = ? AND date >= ? AND date <= ? AND status = ?"
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM attendance"+where)).
+		WithArgs(int64(1), "2023-10-01", "2023-10-31", "Absent").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, date, session_id, status FROM attendance"+where+" ORDER BY date DESC, session_id ASC LIMIT ? OFFSET ?")).
+		WithArgs(int64(1), "2023-10-01", "2023-10-31", "Absent", 2, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "student_id", "date", "session_id", "status"}).
+			AddRow(int64(7), int64(1), "2023-10-02", int64(0), "Absent"))
+
+	attendances, total, err := GetAttendancePage(filter, 2, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, attendances, 1)
+	assert.Equal(t, int64(7), attendances[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetAttendanceByDateRangeSuccess(t *testing.T) {
 	mock, _ := setupAttendanceSQLMock(t)
 
@@ -130,12 +389,12 @@ func TestGetAttendanceByDateRangeSuccess(t *testing.T) {
 	startDate := "2023-10-01"
 	endDate := "2023-10-31"
 
-	rows := sqlmock.NewRows([]string{"id", "student_id", "date", "status"}).
-		AddRow(int64(1), studentID, "2023-10-10", "Present").
-		AddRow(int64(2), studentID, "2023-10-15", "Absent").
-		AddRow(int64(3), studentID, "2023-10-20", "Present")
+	rows := sqlmock.NewRows([]string{"id", "student_id", "date", "session_id", "status"}).
+		AddRow(int64(1), studentID, "2023-10-10", int64(0), "Present").
+		AddRow(int64(2), studentID, "2023-10-15", int64(0), "Absent").
+		AddRow(int64(3), studentID, "2023-10-20", int64(0), "Present")
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, date, status FROM attendance WHERE student_id = ? AND date BETWEEN ? AND ? ORDER BY date ASC")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, date, session_id, status FROM attendance WHERE student_id = ? AND date BETWEEN ? AND ? ORDER BY date ASC, session_id ASC")).
 		WithArgs(studentID, startDate, endDate).
 		WillReturnRows(rows)
 
@@ -155,9 +414,9 @@ func TestGetAttendanceByDateRangeEmpty(t *testing.T) {
 	startDate := "2023-01-01"
 	endDate := "2023-01-31"
 
-	rows := sqlmock.NewRows([]string{"id", "student_id", "date", "status"})
+	rows := sqlmock.NewRows([]string{"id", "student_id", "date", "session_id", "status"})
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, date, status FROM attendance WHERE student_id = ? AND date BETWEEN ? AND ? ORDER BY date ASC")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, date, session_id, status FROM attendance WHERE student_id = ? AND date BETWEEN ? AND ? ORDER BY date ASC, session_id ASC")).
 		WithArgs(studentID, startDate, endDate).
 		WillReturnRows(rows)
 
@@ -175,7 +434,7 @@ func TestGetAttendanceByDateRangeError(t *testing.T) {
 	startDate := "2023-10-01"
 	endDate := "2023-10-31"
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, date, status FROM attendance WHERE student_id = ? AND date BETWEEN ? AND ? ORDER BY date ASC")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, date, session_id, status FROM attendance WHERE student_id = ? AND date BETWEEN ? AND ? ORDER BY date ASC, session_id ASC")).
 		WithArgs(studentID, startDate, endDate).
 		WillReturnError(sql.ErrConnDone)
 
@@ -202,12 +461,14 @@ func TestGetAttendanceReportSuccess(t *testing.T) {
 			s.id, 
 			s.name, 
 			s.email, 
-			COALESCE(SUM(CASE WHEN a.status = 'Present' THEN 1 ELSE 0 END), 0) as present_count,
-			COALESCE(SUM(CASE WHEN a.status = 'Absent' THEN 1 ELSE 0 END), 0) as absent_count
+			COALESCE(SUM(CASE WHEN st.counts_as_present = 1 THEN 1 ELSE 0 END), 0) as present_count,
+			COALESCE(SUM(CASE WHEN st.counts_as_present = 0 AND st.excused = 0 THEN 1 ELSE 0 END), 0) as absent_count
 		FROM 
 			students s
 		LEFT JOIN 
 			attendance a ON s.id = a.student_id AND a.date BETWEEN ? AND ?
+		LEFT JOIN 
+			attendance_statuses st ON st.code = a.status
 		GROUP BY 
 			s.id, s.name, s.email
 		ORDER BY 
@@ -217,6 +478,13 @@ func TestGetAttendanceReportSuccess(t *testing.T) {
 	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
 		WithArgs(startDate, endDate).
 		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT student_id, status, COUNT(*) FROM attendance WHERE date BETWEEN ? AND ? GROUP BY student_id, status")).
+		WithArgs(startDate, endDate).
+		WillReturnRows(sqlmock.NewRows([]string{"student_id", "status", "count"}).
+			AddRow(int64(1), "Present", 12).
+			AddRow(int64(1), "Late", 3).
+			AddRow(int64(1), "Absent", 5))
+	expectStatusCatalogue(mock)
 
 	reports, err := GetAttendanceReport(startDate, endDate)
 
@@ -225,7 +493,13 @@ func TestGetAttendanceReportSuccess(t *testing.T) {
 	assert.Equal(t, "Alice Smith", reports[0].StudentName)
 	assert.Equal(t, 15, reports[0].PresentCount)
 	assert.Equal(t, 5, reports[0].AbsentCount)
+	assert.Equal(t, []model.StatusCount{
+		{Code: "Present", Label: "Present", CountsAsPresent: true, Count: 12},
+		{Code: "Absent", Label: "Absent", CountsAsPresent: false, Count: 5},
+		{Code: "Late", Label: "Late", CountsAsPresent: true, Count: 3},
+	}, reports[0].StatusCounts)
 	assert.Equal(t, "Bob Johnson", reports[1].StudentName)
+	assert.Equal(t, 0, reports[1].StatusCounts[0].Count)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -242,12 +516,14 @@ func TestGetAttendanceReportEmpty(t *testing.T) {
 			s.id, 
 			s.name, 
 			s.email, 
-			COALESCE(SUM(CASE WHEN a.status = 'Present' THEN 1 ELSE 0 END), 0) as present_count,
-			COALESCE(SUM(CASE WHEN a.status = 'Absent' THEN 1 ELSE 0 END), 0) as absent_count
+			COALESCE(SUM(CASE WHEN st.counts_as_present = 1 THEN 1 ELSE 0 END), 0) as present_count,
+			COALESCE(SUM(CASE WHEN st.counts_as_present = 0 AND st.excused = 0 THEN 1 ELSE 0 END), 0) as absent_count
 		FROM 
 			students s
 		LEFT JOIN 
 			attendance a ON s.id = a.student_id AND a.date BETWEEN ? AND ?
+		LEFT JOIN 
+			attendance_statuses st ON st.code = a.status
 		GROUP BY 
 			s.id, s.name, s.email
 		ORDER BY 
@@ -257,6 +533,10 @@ func TestGetAttendanceReportEmpty(t *testing.T) {
 	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
 		WithArgs(startDate, endDate).
 		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT student_id, status, COUNT(*) FROM attendance WHERE date BETWEEN ? AND ? GROUP BY student_id, status")).
+		WithArgs(startDate, endDate).
+		WillReturnRows(sqlmock.NewRows([]string{"student_id", "status", "count"}))
+	expectStatusCatalogue(mock)
 
 	reports, err := GetAttendanceReport(startDate, endDate)
 
@@ -276,12 +556,14 @@ func TestGetAttendanceReportError(t *testing.T) {
 			s.id, 
 			s.name, 
 			s.email, 
-			COALESCE(SUM(CASE WHEN a.status = 'Present' THEN 1 ELSE 0 END), 0) as present_count,
-			COALESCE(SUM(CASE WHEN a.status = 'Absent' THEN 1 ELSE 0 END), 0) as absent_count
+			COALESCE(SUM(CASE WHEN st.counts_as_present = 1 THEN 1 ELSE 0 END), 0) as present_count,
+			COALESCE(SUM(CASE WHEN st.counts_as_present = 0 AND st.excused = 0 THEN 1 ELSE 0 END), 0) as absent_count
 		FROM 
 			students s
 		LEFT JOIN 
 			attendance a ON s.id = a.student_id AND a.date BETWEEN ? AND ?
+		LEFT JOIN 
+			attendance_statuses st ON st.code = a.status
 		GROUP BY 
 			s.id, s.name, s.email
 		ORDER BY 
@@ -298,3 +580,219 @@ func TestGetAttendanceReportError(t *testing.T) {
 	assert.Nil(t, reports)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestGetDailyAttendanceReportSuccess(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	startDate := "2023-10-01"
+	endDate := "2023-10-31"
+
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "present_count", "absent_count"}).
+		AddRow(int64(1), "Alice Smith", "alice@example.com", 18, 2)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SUM(st.counts_as_present) / NULLIF(SUM(1 - st.excused), 0) as present_ratio")).
+		WithArgs(75.0, 75.0, startDate, endDate).
+		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT student_id, status, COUNT(*) FROM attendance WHERE date BETWEEN ? AND ? GROUP BY student_id, status")).
+		WithArgs(startDate, endDate).
+		WillReturnRows(sqlmock.NewRows([]string{"student_id", "status", "count"}).
+			AddRow(int64(1), "Present", 70).
+			AddRow(int64(1), "Absent", 10))
+	expectStatusCatalogue(mock)
+
+	reports, err := GetDailyAttendanceReport(startDate, endDate, 75)
+
+	assert.NoError(t, err)
+	assert.Len(t, reports, 1)
+	assert.Equal(t, 18, reports[0].PresentCount)
+	assert.Equal(t, 2, reports[0].AbsentCount)
+	assert.Equal(t, 70, reports[0].StatusCounts[0].Count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetScopedAttendanceReportSection(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	startDate := "2023-10-01"
+	endDate := "2023-10-31"
+
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "present_count", "absent_count"}).
+		AddRow(int64(1), "Alice Smith", "alice@example.com", 8, 1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("a.date BETWEEN ? AND ? AND a.session_id IN (SELECT id FROM sessions WHERE section_id = ?)")).
+		WithArgs(int64(2), startDate, endDate, int64(2)).
+		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT student_id, status, COUNT(*) FROM attendance a WHERE date BETWEEN ? AND ? AND a.session_id IN (SELECT id FROM sessions WHERE section_id = ?) GROUP BY student_id, status")).
+		WithArgs(startDate, endDate, int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"student_id", "status", "count"}).
+			AddRow(int64(1), "Present", 8).
+			AddRow(int64(1), "Absent", 1))
+	expectStatusCatalogue(mock)
+
+	reports, err := GetScopedAttendanceReport(model.ReportScope{SectionID: 2}, startDate, endDate)
+
+	assert.NoError(t, err)
+	assert.Len(t, reports, 1)
+	assert.Equal(t, 8, reports[0].PresentCount)
+	assert.Equal(t, 1, reports[0].StatusCounts[1].Count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetScopedDailyAttendanceReportDepartment(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	startDate := "2023-10-01"
+	endDate := "2023-10-31"
+
+	mock.ExpectQuery(regexp.QuoteMeta(") d ON s.id = d.student_id WHERE s.department_id = ? GROUP BY")).
+		WithArgs(75.0, 75.0, startDate, endDate, int64(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "present_count", "absent_count"}))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT student_id, status, COUNT(*) FROM attendance WHERE date BETWEEN ? AND ? GROUP BY student_id, status")).
+		WithArgs(startDate, endDate).
+		WillReturnRows(sqlmock.NewRows([]string{"student_id", "status", "count"}))
+	expectStatusCatalogue(mock)
+
+	reports, err := GetScopedDailyAttendanceReport(model.ReportScope{DepartmentID: 4}, startDate, endDate, 75)
+
+	assert.NoError(t, err)
+	assert.Len(t, reports, 0)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func expectStatusCatalogue(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT code, label, counts_as_present, excused, sort_order FROM attendance_statuses ORDER BY sort_order ASC, code ASC")).
+		WillReturnRows(sqlmock.NewRows([]string{"code", "label", "counts_as_present", "excused", "sort_order"}).
+			AddRow("Present", "Present", true, false, 1).
+			AddRow("Absent", "Absent", false, false, 2).
+			AddRow("Late", "Late", true, false, 3))
+}
+
+func TestImportAttendanceBatchReturnsInsertedRecords(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	records := model.Attendances{
+		{StudentID: 1, Date: "2023-10-27", Status: "Present"},
+		{StudentID: 2, Date: "2023-10-27", Status: "Absent"},
+		{StudentID: 3, Date: "2023-10-27", Status: "Present"},
+		{StudentID: 3, Date: "2023-10-27", Status: "Late"},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT student_id, DATE_FORMAT(date, '%Y-%m-%d') FROM attendance WHERE session_id = 0 AND (student_id, date) IN ((?, ?), (?, ?), (?, ?), (?, ?)) FOR UPDATE")).
+		WithArgs(int64(1), "2023-10-27", int64(2), "2023-10-27", int64(3), "2023-10-27", int64(3), "2023-10-27").
+		WillReturnRows(sqlmock.NewRows([]string{"student_id", "date"}).AddRow(int64(1), "2023-10-27"))
+	// student 1 already has a record and student 3's second line repeats the first
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO attendance (student_id, date, session_id, status) VALUES (?, ?, ?, ?), (?, ?, ?, ?) ON DUPLICATE KEY UPDATE id = id")).
+		WithArgs(int64(2), "2023-10-27", int64(0), "Absent", int64(3), "2023-10-27", int64(0), "Present").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	inserted, err := ImportAttendanceBatch(records)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.Attendances{records[1], records[2]}, inserted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportAttendanceRecordReportsDuplicates(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	query := regexp.QuoteMeta("INSERT INTO attendance (student_id, date, session_id, status) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE id = id")
+	mock.ExpectExec(query).
+		WithArgs(int64(1), "2023-09-01", int64(0), "Present").
+		WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectExec(query).
+		WithArgs(int64(2), "2023-09-01", int64(0), "Absent").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	id, err := ImportAttendanceRecord(model.Attendance{StudentID: 1, Date: "2023-09-01", Status: "Present"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), id)
+
+	id, err = ImportAttendanceRecord(model.Attendance{StudentID: 2, Date: "2023-09-01", Status: "Absent"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportAttendanceStreamsRows(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	rows := sqlmock.NewRows([]string{"id", "student_id", "name", "email", "department", "date", "session_id", "status"}).
+		AddRow(7, 1, "Ada", "ada@example.com", "Maths", "2023-10-02", 0, "Present").
+		AddRow(8, 2, "Bob", "bob@example.com", "Maths", "2023-10-02", 3, "Absent")
+	mock.ExpectQuery(regexp.QuoteMeta("AND s.department_id = ? ORDER BY a.date ASC")).
+		WithArgs("2023-10-01", "2023-10-31", int64(4)).
+		WillReturnRows(rows)
+
+	var emitted []model.AttendanceExportRow
+	filter := model.AttendanceExportFilter{From: "2023-10-01", To: "2023-10-31", DepartmentID: 4}
+	err := ExportAttendance(context.Background(), filter, func(r model.AttendanceExportRow) error {
+		emitted = append(emitted, r)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, emitted, 2)
+	assert.Equal(t, model.AttendanceExportRow{AttendanceID: 8, StudentID: 2, StudentName: "Bob", StudentEmail: "bob@example.com", Department: "Maths", Date: "2023-10-02", SessionID: 3, Status: "Absent"}, emitted[1])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCloseSessionMarksAbsentees(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE sessions SET closed_at = CURRENT_TIMESTAMP WHERE id = ? AND closed_at IS NULL")).
+		WithArgs(int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT s.id, DATE_FORMAT(se.date, '%Y-%m-%d') FROM sessions se LEFT JOIN sections sc ON sc.id = se.section_id LEFT JOIN courses c ON c.id = sc.course_id JOIN students s ON s.department_id = COALESCE(c.department_id, se.department_id) WHERE se.id = ?")).
+		WithArgs(int64(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date"}).AddRow(1, "2023-10-27").AddRow(3, "2023-10-27").AddRow(7, "2023-10-27"))
+	prep := mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO attendance (student_id, date, session_id, status) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE id = id"))
+	prep.ExpectExec().WithArgs(int64(1), "2023-10-27", int64(4), "Absent").WillReturnResult(sqlmock.NewResult(21, 1))
+	// student 3 was marked for the session after the absentees were read
+	prep.ExpectExec().WithArgs(int64(3), "2023-10-27", int64(4), "Absent").WillReturnResult(sqlmock.NewResult(0, 0))
+	prep.ExpectExec().WithArgs(int64(7), "2023-10-27", int64(4), "Absent").WillReturnResult(sqlmock.NewResult(22, 1))
+	mock.ExpectCommit()
+
+	marked, err := CloseSession(4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 7}, marked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCloseSessionWithoutAbsentees(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE sessions SET closed_at = CURRENT_TIMESTAMP WHERE id = ? AND closed_at IS NULL")).
+		WithArgs(int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT s.id, DATE_FORMAT(se.date, '%Y-%m-%d') FROM sessions se")).
+		WithArgs(int64(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date"}))
+	mock.ExpectCommit()
+
+	marked, err := CloseSession(4)
+
+	assert.NoError(t, err)
+	assert.Empty(t, marked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCloseSessionAlreadyClosed(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE sessions SET closed_at = CURRENT_TIMESTAMP WHERE id = ? AND closed_at IS NULL")).
+		WithArgs(int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	marked, err := CloseSession(4)
+
+	assert.NoError(t, err)
+	assert.Zero(t, marked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
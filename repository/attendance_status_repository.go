@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	configuration "github.com/shravanasati/scopex-go-assignment/configuration"
+	model "github.com/shravanasati/scopex-go-assignment/model"
+)
+
+type AttendanceStatusRepository interface {
+	GetStatuses() (model.AttendanceStatuses, error)
+	GetStatusByCode(code string) (model.AttendanceStatus, error)
+	CreateStatus(status model.AttendanceStatus) error
+	UpdateStatus(code string, status model.AttendanceStatus) error
+	DeleteStatus(code string) error
+}
+type attendanceStatusRepository struct{}
+
+var AttendanceStatusRepo AttendanceStatusRepository = &attendanceStatusRepository{}
+
+// ErrStatusNotFound indicates that the requested status code is not part of
+// the catalogue.
+var ErrStatusNotFound = errors.New("attendance status not found")
+
+// ErrDuplicateStatus is returned when creating a status whose code exists.
+var ErrDuplicateStatus = errors.New("attendance status with this code already exists")
+
+// ErrStatusInUse is returned when deleting a status still referenced by
+// attendance records.
+var ErrStatusInUse = errors.New("attendance status is used by attendance records")
+
+// GetStatuses retrieves the whole status catalogue in display order
+func (r *attendanceStatusRepository) GetStatuses() (model.AttendanceStatuses, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var statuses model.AttendanceStatuses
+
+	query := "SELECT code, label, counts_as_present, excused, sort_order FROM attendance_statuses ORDER BY sort_order ASC, code ASC"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		log.Println("Error querying attendance statuses: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s model.AttendanceStatus
+		if err := rows.Scan(&s.Code, &s.Label, &s.CountsAsPresent, &s.Excused, &s.SortOrder); err != nil {
+			log.Println("Error scanning attendance status: " + err.Error())
+			return nil, err
+		}
+		statuses = append(statuses, s)
+	}
+
+	return statuses, nil
+}
+
+// GetStatusByCode retrieves one status of the catalogue
+func (r *attendanceStatusRepository) GetStatusByCode(code string) (model.AttendanceStatus, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var s model.AttendanceStatus
+
+	query := "SELECT code, label, counts_as_present, excused, sort_order FROM attendance_statuses WHERE code = ?"
+	err := db.QueryRowContext(ctx, query, code).Scan(&s.Code, &s.Label, &s.CountsAsPresent, &s.Excused, &s.SortOrder)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return s, ErrStatusNotFound
+		}
+		log.Println("Error querying attendance status: " + err.Error())
+		return s, err
+	}
+
+	return s, nil
+}
+
+// CreateStatus adds a status to the catalogue
+func (r *attendanceStatusRepository) CreateStatus(status model.AttendanceStatus) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "INSERT INTO attendance_statuses (code, label, counts_as_present, excused, sort_order) VALUES (?, ?, ?, ?, ?)"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, status.Code, status.Label, status.CountsAsPresent, status.Excused, status.SortOrder)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDuplicateEntry) {
+			return ErrDuplicateStatus
+		}
+		log.Println("Error inserting attendance status: " + err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// UpdateStatus changes the label, present flag and order of a status. The
+// code itself is immutable because attendance records reference it.
+func (r *attendanceStatusRepository) UpdateStatus(code string, status model.AttendanceStatus) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "UPDATE attendance_statuses SET label = ?, counts_as_present = ?, excused = ?, sort_order = ? WHERE code = ?"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, status.Label, status.CountsAsPresent, status.Excused, status.SortOrder, code)
+	if err != nil {
+		log.Println("Error updating attendance status: " + err.Error())
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// nothing changed or nothing matched; tell the two apart
+		if _, err := r.GetStatusByCode(code); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteStatus removes an unused status from the catalogue
+func (r *attendanceStatusRepository) DeleteStatus(code string) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "DELETE FROM attendance_statuses WHERE code = ?"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, code)
+	if err != nil {
+		if isMySQLError(err, mysqlErrRowIsReferenced) {
+			return ErrStatusInUse
+		}
+		log.Println("Error deleting attendance status: " + err.Error())
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrStatusNotFound
+	}
+
+	return nil
+}
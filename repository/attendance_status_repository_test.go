@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStatusesSuccess(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &attendanceStatusRepository{}
+
+	expectStatusCatalogue(mock)
+
+	statuses, err := repo.GetStatuses()
+
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 3)
+	assert.Equal(t, "Late", statuses[2].Code)
+	assert.True(t, statuses[2].CountsAsPresent)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateStatusDuplicate(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &attendanceStatusRepository{}
+
+	status := model.AttendanceStatus{Code: "Late", Label: "Late", CountsAsPresent: true, SortOrder: 3}
+
+	prep := mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO attendance_statuses (code, label, counts_as_present, excused, sort_order) VALUES (?, ?, ?, ?, ?)"))
+	prep.ExpectExec().
+		WithArgs(status.Code, status.Label, status.CountsAsPresent, status.Excused, status.SortOrder).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'Late' for key 'PRIMARY'"})
+
+	err := repo.CreateStatus(status)
+
+	assert.ErrorIs(t, err, ErrDuplicateStatus)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteStatusInUse(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &attendanceStatusRepository{}
+
+	prep := mock.ExpectPrepare(regexp.QuoteMeta("DELETE FROM attendance_statuses WHERE code = ?"))
+	prep.ExpectExec().
+		WithArgs("Late").
+		WillReturnError(&mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row"})
+
+	err := repo.DeleteStatus("Late")
+
+	assert.ErrorIs(t, err, ErrStatusInUse)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteStatusNotFound(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &attendanceStatusRepository{}
+
+	prep := mock.ExpectPrepare(regexp.QuoteMeta("DELETE FROM attendance_statuses WHERE code = ?"))
+	prep.ExpectExec().
+		WithArgs("Unknown").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.DeleteStatus("Unknown")
+
+	assert.ErrorIs(t, err, ErrStatusNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,364 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	configuration "github.com/shravanasati/scopex-go-assignment/configuration"
+	model "github.com/shravanasati/scopex-go-assignment/model"
+)
+
+type CalendarRepository interface {
+	CreateTerm(term model.Term) (int64, error)
+	GetTerms() (model.Terms, error)
+	GetTermByID(id int64) (model.Term, error)
+	UpdateTerm(id int64, term model.Term) error
+	DeleteTerm(id int64) error
+	CreateHoliday(holiday model.Holiday) (int64, error)
+	GetHolidays(from, to string) (model.Holidays, error)
+	GetHolidayByID(id int64) (model.Holiday, error)
+	UpdateHoliday(id int64, holiday model.Holiday) error
+	DeleteHoliday(id int64) error
+	GetWeekendDays() ([]time.Weekday, error)
+	SetWeekendDays(days []time.Weekday) error
+}
+type calendarRepository struct{}
+
+var CalendarRepo CalendarRepository = &calendarRepository{}
+
+// ErrTermNotFound indicates that the requested term does not exist.
+var ErrTermNotFound = errors.New("term not found")
+
+// ErrHolidayNotFound indicates that the requested holiday does not exist.
+var ErrHolidayNotFound = errors.New("holiday not found")
+
+// ErrDuplicateHoliday is returned when a date already has a holiday.
+var ErrDuplicateHoliday = errors.New("a holiday already exists on this date")
+
+// CreateTerm inserts a new term
+func (r *calendarRepository) CreateTerm(term model.Term) (int64, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "INSERT INTO terms (name, start_date, end_date) VALUES (?, ?, ?)"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, term.Name, term.StartDate, term.EndDate)
+	if err != nil {
+		log.Println("Error inserting term: " + err.Error())
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+// GetTerms retrieves all terms in calendar order
+func (r *calendarRepository) GetTerms() (model.Terms, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var terms model.Terms
+
+	query := "SELECT id, name, DATE_FORMAT(start_date, '%Y-%m-%d'), DATE_FORMAT(end_date, '%Y-%m-%d') FROM terms ORDER BY start_date ASC, id ASC"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		log.Println("Error querying terms: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t model.Term
+		if err := rows.Scan(&t.ID, &t.Name, &t.StartDate, &t.EndDate); err != nil {
+			log.Println("Error scanning term: " + err.Error())
+			return nil, err
+		}
+		terms = append(terms, t)
+	}
+
+	return terms, nil
+}
+
+// GetTermByID retrieves a term by ID
+func (r *calendarRepository) GetTermByID(id int64) (model.Term, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var t model.Term
+
+	query := "SELECT id, name, DATE_FORMAT(start_date, '%Y-%m-%d'), DATE_FORMAT(end_date, '%Y-%m-%d') FROM terms WHERE id = ?"
+	err := db.QueryRowContext(ctx, query, id).Scan(&t.ID, &t.Name, &t.StartDate, &t.EndDate)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return t, ErrTermNotFound
+		}
+		log.Println("Error querying term by ID: " + err.Error())
+		return t, err
+	}
+
+	return t, nil
+}
+
+// UpdateTerm changes the name and dates of a term
+func (r *calendarRepository) UpdateTerm(id int64, term model.Term) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "UPDATE terms SET name = ?, start_date = ?, end_date = ? WHERE id = ?"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, term.Name, term.StartDate, term.EndDate, id)
+	if err != nil {
+		log.Println("Error updating term: " + err.Error())
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// nothing changed or nothing matched; tell the two apart
+		if _, err := r.GetTermByID(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteTerm deletes a term by ID
+func (r *calendarRepository) DeleteTerm(id int64) error {
+	return deleteByID("terms", id, ErrTermNotFound)
+}
+
+// CreateHoliday inserts a new holiday
+func (r *calendarRepository) CreateHoliday(holiday model.Holiday) (int64, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "INSERT INTO holidays (date, name) VALUES (?, ?)"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, holiday.Date, holiday.Name)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDuplicateEntry) {
+			return 0, ErrDuplicateHoliday
+		}
+		log.Println("Error inserting holiday: " + err.Error())
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+// GetHolidays retrieves the holidays between two dates inclusive. An empty
+// bound leaves that side of the range open.
+func (r *calendarRepository) GetHolidays(from, to string) (model.Holidays, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var holidays model.Holidays
+
+	query := "SELECT id, DATE_FORMAT(date, '%Y-%m-%d'), name FROM holidays"
+	var args []any
+	switch {
+	case from != "" && to != "":
+		query += " WHERE date BETWEEN ? AND ?"
+		args = append(args, from, to)
+	case from != "":
+		query += " WHERE date >= ?"
+		args = append(args, from)
+	case to != "":
+		query += " WHERE date <= ?"
+		args = append(args, to)
+	}
+	query += " ORDER BY date ASC"
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error querying holidays: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var h model.Holiday
+		if err := rows.Scan(&h.ID, &h.Date, &h.Name); err != nil {
+			log.Println("Error scanning holiday: " + err.Error())
+			return nil, err
+		}
+		holidays = append(holidays, h)
+	}
+
+	return holidays, nil
+}
+
+// GetHolidayByID retrieves a holiday by ID
+func (r *calendarRepository) GetHolidayByID(id int64) (model.Holiday, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var h model.Holiday
+
+	query := "SELECT id, DATE_FORMAT(date, '%Y-%m-%d'), name FROM holidays WHERE id = ?"
+	err := db.QueryRowContext(ctx, query, id).Scan(&h.ID, &h.Date, &h.Name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return h, ErrHolidayNotFound
+		}
+		log.Println("Error querying holiday by ID: " + err.Error())
+		return h, err
+	}
+
+	return h, nil
+}
+
+// UpdateHoliday changes the date and name of a holiday
+func (r *calendarRepository) UpdateHoliday(id int64, holiday model.Holiday) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "UPDATE holidays SET date = ?, name = ? WHERE id = ?"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, holiday.Date, holiday.Name, id)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDuplicateEntry) {
+			return ErrDuplicateHoliday
+		}
+		log.Println("Error updating holiday: " + err.Error())
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// nothing changed or nothing matched; tell the two apart
+		if _, err := r.GetHolidayByID(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteHoliday deletes a holiday by ID
+func (r *calendarRepository) DeleteHoliday(id int64) error {
+	return deleteByID("holidays", id, ErrHolidayNotFound)
+}
+
+// GetWeekendDays retrieves the weekdays that are never working days
+func (r *calendarRepository) GetWeekendDays() ([]time.Weekday, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var days []time.Weekday
+
+	rows, err := db.QueryContext(ctx, "SELECT weekday FROM weekend_days ORDER BY weekday ASC")
+	if err != nil {
+		log.Println("Error querying weekend days: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d int
+		if err := rows.Scan(&d); err != nil {
+			log.Println("Error scanning weekend day: " + err.Error())
+			return nil, err
+		}
+		days = append(days, time.Weekday(d))
+	}
+
+	return days, nil
+}
+
+// SetWeekendDays replaces the weekend rule
+func (r *calendarRepository) SetWeekendDays(days []time.Weekday) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM weekend_days"); err != nil {
+		log.Println("Error clearing weekend days: " + err.Error())
+		return err
+	}
+
+	if len(days) > 0 {
+		args := make([]any, len(days))
+		for i, d := range days {
+			args[i] = int(d)
+		}
+		query := "INSERT INTO weekend_days (weekday) VALUES " + placeholderRows(len(days), 1)
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			log.Println("Error inserting weekend days: " + err.Error())
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// deleteByID deletes one row of table by primary key, returning notFound
+// when no row matched.
+func deleteByID(table string, id int64, notFound error) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stmt, err := db.PrepareContext(ctx, "DELETE FROM "+table+" WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		log.Println("Error deleting from " + table + ": " + err.Error())
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound
+	}
+
+	return nil
+}
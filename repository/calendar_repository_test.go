@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateHolidayDuplicate(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &calendarRepository{}
+
+	mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO holidays (date, name) VALUES (?, ?)")).
+		ExpectExec().
+		WithArgs("2023-11-12", "Diwali").
+		WillReturnError(&mysql.MySQLError{Number: mysqlErrDuplicateEntry})
+
+	_, err := repo.CreateHoliday(model.Holiday{Date: "2023-11-12", Name: "Diwali"})
+
+	assert.ErrorIs(t, err, ErrDuplicateHoliday)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetHolidaysInRange(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &calendarRepository{}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM holidays WHERE date BETWEEN ? AND ? ORDER BY date ASC")).
+		WithArgs("2023-11-01", "2023-11-30").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date", "name"}).AddRow(int64(1), "2023-11-12", "Diwali"))
+
+	holidays, err := repo.GetHolidays("2023-11-01", "2023-11-30")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.Holidays{{ID: 1, Date: "2023-11-12", Name: "Diwali"}}, holidays)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetWeekendDaysReplacesRule(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &calendarRepository{}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM weekend_days")).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO weekend_days (weekday) VALUES (?), (?)")).
+		WithArgs(5, 6).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := repo.SetWeekendDays([]time.Weekday{time.Friday, time.Saturday})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteTermNotFound(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &calendarRepository{}
+
+	mock.ExpectPrepare(regexp.QuoteMeta("DELETE FROM terms WHERE id = ?")).
+		ExpectExec().
+		WithArgs(int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.DeleteTerm(9)
+
+	assert.ErrorIs(t, err, ErrTermNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
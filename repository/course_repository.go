@@ -0,0 +1,503 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	configuration "github.com/shravanasati/scopex-go-assignment/configuration"
+	model "github.com/shravanasati/scopex-go-assignment/model"
+)
+
+type CourseRepository interface {
+	CreateCourse(course model.Course) (int64, error)
+	GetCourses() (model.Courses, error)
+	GetCourseByID(id int64) (model.Course, error)
+	UpdateCourse(id int64, course model.Course) error
+	DeleteCourse(id int64) error
+
+	CreateSection(section model.Section) (int64, error)
+	GetSections(courseID int64) (model.Sections, error)
+	GetSectionByID(id int64) (model.Section, error)
+	UpdateSection(id int64, section model.Section) error
+	DeleteSection(id int64) error
+
+	EnrollStudents(sectionID int64, studentIDs []int64) (model.EnrollmentResult, error)
+	GetEnrollments(sectionID int64) (model.Enrollments, error)
+	UnenrollStudent(sectionID, studentID int64) error
+	EnrolledStudents(sectionID int64, studentIDs []int64) (map[int64]bool, error)
+	GetStudentSections(studentID int64) (model.Sections, error)
+}
+type courseRepository struct{}
+
+var CourseRepo CourseRepository = &courseRepository{}
+
+// ErrCourseNotFound indicates that the requested course does not exist.
+var ErrCourseNotFound = errors.New("course not found")
+
+// ErrDuplicateCourse is returned when a course code is already taken.
+var ErrDuplicateCourse = errors.New("a course with this code already exists")
+
+// ErrCourseInUse is returned when deleting a course that still has sections.
+var ErrCourseInUse = errors.New("course still has sections")
+
+// ErrSectionNotFound indicates that the requested section does not exist.
+var ErrSectionNotFound = errors.New("section not found")
+
+// ErrDuplicateSection is returned when a course already has a section of that name.
+var ErrDuplicateSection = errors.New("the course already has a section with this name")
+
+// ErrSectionInUse is returned when deleting a section that sessions are scheduled for.
+var ErrSectionInUse = errors.New("section still has sessions")
+
+// ErrEnrollmentNotFound indicates that the student is not enrolled in the section.
+var ErrEnrollmentNotFound = errors.New("student is not enrolled in this section")
+
+// CreateCourse inserts a new course
+func (r *courseRepository) CreateCourse(course model.Course) (int64, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "INSERT INTO courses (code, name, department_id) VALUES (?, ?, ?)"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, course.Code, course.Name, nullableID(course.DepartmentID))
+	if err != nil {
+		switch {
+		case isMySQLError(err, mysqlErrDuplicateEntry):
+			return 0, ErrDuplicateCourse
+		case isMySQLError(err, mysqlErrNoReferencedRow):
+			return 0, ErrDepartmentNotFound
+		}
+		log.Println("Error inserting course: " + err.Error())
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+// GetCourses retrieves every course ordered by code
+func (r *courseRepository) GetCourses() (model.Courses, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	courses := model.Courses{}
+
+	query := "SELECT id, code, name, COALESCE(department_id, 0), created_at FROM courses ORDER BY code ASC"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		log.Println("Error querying courses: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c model.Course
+		if err := rows.Scan(&c.ID, &c.Code, &c.Name, &c.DepartmentID, &c.CreatedAt); err != nil {
+			log.Println("Error scanning course: " + err.Error())
+			return nil, err
+		}
+		courses = append(courses, c)
+	}
+
+	return courses, rows.Err()
+}
+
+// GetCourseByID retrieves a course by ID
+func (r *courseRepository) GetCourseByID(id int64) (model.Course, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var c model.Course
+
+	query := "SELECT id, code, name, COALESCE(department_id, 0), created_at FROM courses WHERE id = ?"
+	err := db.QueryRowContext(ctx, query, id).Scan(&c.ID, &c.Code, &c.Name, &c.DepartmentID, &c.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c, ErrCourseNotFound
+		}
+		log.Println("Error querying course by ID: " + err.Error())
+		return c, err
+	}
+
+	return c, nil
+}
+
+// UpdateCourse replaces the code, name and department of a course
+func (r *courseRepository) UpdateCourse(id int64, course model.Course) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "UPDATE courses SET code = ?, name = ?, department_id = ? WHERE id = ?"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, course.Code, course.Name, nullableID(course.DepartmentID), id)
+	if err != nil {
+		switch {
+		case isMySQLError(err, mysqlErrDuplicateEntry):
+			return ErrDuplicateCourse
+		case isMySQLError(err, mysqlErrNoReferencedRow):
+			return ErrDepartmentNotFound
+		}
+		log.Println("Error updating course: " + err.Error())
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// nothing changed or nothing matched; tell the two apart
+		if _, err := r.GetCourseByID(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteCourse deletes a course that has no sections left
+func (r *courseRepository) DeleteCourse(id int64) error {
+	err := deleteByID("courses", id, ErrCourseNotFound)
+	if isMySQLError(err, mysqlErrRowIsReferenced) {
+		return ErrCourseInUse
+	}
+	return err
+}
+
+// CreateSection inserts a new section of a course
+func (r *courseRepository) CreateSection(section model.Section) (int64, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "INSERT INTO sections (course_id, name) VALUES (?, ?)"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, section.CourseID, section.Name)
+	if err != nil {
+		switch {
+		case isMySQLError(err, mysqlErrDuplicateEntry):
+			return 0, ErrDuplicateSection
+		case isMySQLError(err, mysqlErrNoReferencedRow):
+			return 0, ErrCourseNotFound
+		}
+		log.Println("Error inserting section: " + err.Error())
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+// GetSections retrieves the sections of a course, or of every course when
+// courseID is 0
+func (r *courseRepository) GetSections(courseID int64) (model.Sections, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sections := model.Sections{}
+
+	query := "SELECT id, course_id, name, created_at FROM sections"
+	var args []any
+	if courseID != 0 {
+		query += " WHERE course_id = ?"
+		args = append(args, courseID)
+	}
+	query += " ORDER BY course_id ASC, name ASC"
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error querying sections: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s model.Section
+		if err := rows.Scan(&s.ID, &s.CourseID, &s.Name, &s.CreatedAt); err != nil {
+			log.Println("Error scanning section: " + err.Error())
+			return nil, err
+		}
+		sections = append(sections, s)
+	}
+
+	return sections, rows.Err()
+}
+
+// GetSectionByID retrieves a section by ID
+func (r *courseRepository) GetSectionByID(id int64) (model.Section, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var s model.Section
+
+	query := "SELECT id, course_id, name, created_at FROM sections WHERE id = ?"
+	err := db.QueryRowContext(ctx, query, id).Scan(&s.ID, &s.CourseID, &s.Name, &s.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return s, ErrSectionNotFound
+		}
+		log.Println("Error querying section by ID: " + err.Error())
+		return s, err
+	}
+
+	return s, nil
+}
+
+// UpdateSection moves a section to another course or renames it
+func (r *courseRepository) UpdateSection(id int64, section model.Section) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "UPDATE sections SET course_id = ?, name = ? WHERE id = ?"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, section.CourseID, section.Name, id)
+	if err != nil {
+		switch {
+		case isMySQLError(err, mysqlErrDuplicateEntry):
+			return ErrDuplicateSection
+		case isMySQLError(err, mysqlErrNoReferencedRow):
+			return ErrCourseNotFound
+		}
+		log.Println("Error updating section: " + err.Error())
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// nothing changed or nothing matched; tell the two apart
+		if _, err := r.GetSectionByID(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteSection deletes a section and its enrollments. Sections that
+// sessions are scheduled for cannot be deleted.
+func (r *courseRepository) DeleteSection(id int64) error {
+	err := deleteByID("sections", id, ErrSectionNotFound)
+	if isMySQLError(err, mysqlErrRowIsReferenced) {
+		return ErrSectionInUse
+	}
+	return err
+}
+
+// EnrollStudents enrolls students in a section in a single transaction.
+// Students that are already enrolled are counted, and unknown students are
+// reported instead of failing the whole request.
+func (r *courseRepository) EnrollStudents(sectionID int64, studentIDs []int64) (model.EnrollmentResult, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result := model.EnrollmentResult{UnknownStudents: []int64{}}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	var exists int64
+	err = tx.QueryRowContext(ctx, "SELECT id FROM sections WHERE id = ? FOR SHARE", sectionID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return result, ErrSectionNotFound
+	}
+	if err != nil {
+		log.Println("Error querying section for enrollment: " + err.Error())
+		return result, err
+	}
+
+	var ids []any
+	seen := make(map[int64]bool)
+	for _, id := range studentIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	known, err := existingStudentIDs(ctx, tx, ids)
+	if err != nil {
+		log.Println("Error querying students for enrollment: " + err.Error())
+		return result, err
+	}
+
+	var insertArgs []any
+	for _, id := range ids {
+		if !known[id.(int64)] {
+			result.UnknownStudents = append(result.UnknownStudents, id.(int64))
+			continue
+		}
+		insertArgs = append(insertArgs, sectionID, id)
+	}
+
+	if len(insertArgs) > 0 {
+		query := "INSERT INTO enrollments (section_id, student_id) VALUES " + placeholderRows(len(insertArgs)/2, 2) + " ON DUPLICATE KEY UPDATE section_id = section_id"
+		res, err := tx.ExecContext(ctx, query, insertArgs...)
+		if err != nil {
+			log.Println("Error inserting enrollments: " + err.Error())
+			return result, err
+		}
+		inserted, err := res.RowsAffected()
+		if err != nil {
+			return result, err
+		}
+		result.Enrolled = int(inserted)
+		result.AlreadyEnrolled = len(insertArgs)/2 - result.Enrolled
+	}
+
+	return result, tx.Commit()
+}
+
+// GetEnrollments retrieves the students enrolled in a section ordered by name
+func (r *courseRepository) GetEnrollments(sectionID int64) (model.Enrollments, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	enrollments := model.Enrollments{}
+
+	query := "SELECT e.section_id, e.student_id, s.name, s.email, e.created_at FROM enrollments e JOIN students s ON s.id = e.student_id WHERE e.section_id = ? ORDER BY s.name ASC, s.id ASC"
+	rows, err := db.QueryContext(ctx, query, sectionID)
+	if err != nil {
+		log.Println("Error querying enrollments: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e model.Enrollment
+		if err := rows.Scan(&e.SectionID, &e.StudentID, &e.StudentName, &e.StudentEmail, &e.CreatedAt); err != nil {
+			log.Println("Error scanning enrollment: " + err.Error())
+			return nil, err
+		}
+		enrollments = append(enrollments, e)
+	}
+
+	return enrollments, rows.Err()
+}
+
+// UnenrollStudent removes a student from a section. Attendance already
+// marked for the section's sessions is kept.
+func (r *courseRepository) UnenrollStudent(sectionID, studentID int64) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "DELETE FROM enrollments WHERE section_id = ? AND student_id = ?"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, sectionID, studentID)
+	if err != nil {
+		log.Println("Error deleting enrollment: " + err.Error())
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrEnrollmentNotFound
+	}
+
+	return nil
+}
+
+// EnrolledStudents returns the subset of the given students enrolled in a
+// section
+func (r *courseRepository) EnrolledStudents(sectionID int64, studentIDs []int64) (map[int64]bool, error) {
+	enrolled := make(map[int64]bool)
+	if len(studentIDs) == 0 {
+		return enrolled, nil
+	}
+
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	args := make([]any, 0, len(studentIDs)+1)
+	args = append(args, sectionID)
+	for _, id := range studentIDs {
+		args = append(args, id)
+	}
+
+	query := "SELECT student_id FROM enrollments WHERE section_id = ? AND student_id IN (" + placeholders(len(studentIDs)) + ")"
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error querying enrolled students: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		enrolled[id] = true
+	}
+
+	return enrolled, rows.Err()
+}
+
+// GetStudentSections retrieves the sections a student is enrolled in
+func (r *courseRepository) GetStudentSections(studentID int64) (model.Sections, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sections := model.Sections{}
+
+	query := "SELECT sec.id, sec.course_id, sec.name, sec.created_at FROM enrollments e JOIN sections sec ON sec.id = e.section_id WHERE e.student_id = ? ORDER BY sec.course_id ASC, sec.name ASC"
+	rows, err := db.QueryContext(ctx, query, studentID)
+	if err != nil {
+		log.Println("Error querying student sections: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s model.Section
+		if err := rows.Scan(&s.ID, &s.CourseID, &s.Name, &s.CreatedAt); err != nil {
+			log.Println("Error scanning section: " + err.Error())
+			return nil, err
+		}
+		sections = append(sections, s)
+	}
+
+	return sections, rows.Err()
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCourseDuplicateCode(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &courseRepository{}
+
+	mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO courses (code, name, department_id) VALUES (?, ?, ?)")).
+		ExpectExec().
+		WithArgs("CS101", "Data Structures", nil).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'CS101' for key 'code'"})
+
+	_, err := repo.CreateCourse(model.Course{Code: "CS101", Name: "Data Structures"})
+
+	assert.ErrorIs(t, err, ErrDuplicateCourse)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteSectionWithSessions(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &courseRepository{}
+
+	mock.ExpectPrepare(regexp.QuoteMeta("DELETE FROM sections WHERE id = ?")).
+		ExpectExec().
+		WithArgs(int64(2)).
+		WillReturnError(&mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row"})
+
+	err := repo.DeleteSection(2)
+
+	assert.ErrorIs(t, err, ErrSectionInUse)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEnrollStudentsReportsUnknownAndExisting(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &courseRepository{}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM sections WHERE id = ? FOR SHARE")).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(2)))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM students WHERE id IN (?, ?, ?)")).
+		WithArgs(int64(1), int64(3), int64(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)).AddRow(int64(3)))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO enrollments (section_id, student_id) VALUES (?, ?), (?, ?) ON DUPLICATE KEY UPDATE section_id = section_id")).
+		WithArgs(int64(2), int64(1), int64(2), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	result, err := repo.EnrollStudents(2, []int64{1, 3, 9, 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.EnrollmentResult{Enrolled: 1, AlreadyEnrolled: 1, UnknownStudents: []int64{9}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEnrollStudentsUnknownSection(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &courseRepository{}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM sections WHERE id = ? FOR SHARE")).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	_, err := repo.EnrollStudents(2, []int64{1})
+
+	assert.ErrorIs(t, err, ErrSectionNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
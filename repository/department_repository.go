@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	configuration "github.com/shravanasati/scopex-go-assignment/configuration"
+	model "github.com/shravanasati/scopex-go-assignment/model"
+)
+
+type DepartmentRepository interface {
+	CreateDepartment(department model.Department) (int64, error)
+	GetDepartments() (model.Departments, error)
+	GetDepartmentByID(id int64) (model.Department, error)
+	UpdateDepartment(id int64, department model.Department) error
+	DeleteDepartment(id int64) error
+	ResolveDepartment(name string) (model.Department, error)
+}
+type departmentRepository struct{}
+
+var DepartmentRepo DepartmentRepository = &departmentRepository{}
+
+// ErrDepartmentNotFound indicates that the requested department does not exist.
+var ErrDepartmentNotFound = errors.New("department not found")
+
+// ErrDuplicateDepartment is returned when a department code, name or alias is
+// already taken.
+var ErrDuplicateDepartment = errors.New("a department with this code, name or alias already exists")
+
+// ErrDepartmentInUse is returned when deleting a department that still has
+// students, courses or sessions.
+var ErrDepartmentInUse = errors.New("department still has students, courses or sessions")
+
+// CreateDepartment inserts a new department together with its aliases
+func (r *departmentRepository) CreateDepartment(department model.Department) (int64, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "INSERT INTO departments (code, name) VALUES (?, ?)", department.Code, department.Name)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDuplicateEntry) {
+			return 0, ErrDuplicateDepartment
+		}
+		log.Println("Error inserting department: " + err.Error())
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := insertDepartmentAliases(ctx, tx, id, department.Aliases); err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+// GetDepartments retrieves every department ordered by name
+func (r *departmentRepository) GetDepartments() (model.Departments, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	departments := model.Departments{}
+
+	query := "SELECT id, code, name, created_at FROM departments ORDER BY name ASC"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		log.Println("Error querying departments: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		d := model.Department{Aliases: []string{}}
+		if err := rows.Scan(&d.ID, &d.Code, &d.Name, &d.CreatedAt); err != nil {
+			log.Println("Error scanning department: " + err.Error())
+			return nil, err
+		}
+		departments = append(departments, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	aliases, err := departmentAliases(ctx, "SELECT department_id, alias FROM department_aliases ORDER BY alias ASC")
+	if err != nil {
+		return nil, err
+	}
+	for i := range departments {
+		if a, ok := aliases[departments[i].ID]; ok {
+			departments[i].Aliases = a
+		}
+	}
+
+	return departments, nil
+}
+
+// GetDepartmentByID retrieves a department and its aliases by ID
+func (r *departmentRepository) GetDepartmentByID(id int64) (model.Department, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	d := model.Department{Aliases: []string{}}
+
+	query := "SELECT id, code, name, created_at FROM departments WHERE id = ?"
+	err := db.QueryRowContext(ctx, query, id).Scan(&d.ID, &d.Code, &d.Name, &d.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return d, ErrDepartmentNotFound
+		}
+		log.Println("Error querying department by ID: " + err.Error())
+		return d, err
+	}
+
+	aliases, err := departmentAliases(ctx, "SELECT department_id, alias FROM department_aliases WHERE department_id = ? ORDER BY alias ASC", id)
+	if err != nil {
+		return d, err
+	}
+	if a, ok := aliases[id]; ok {
+		d.Aliases = a
+	}
+
+	return d, nil
+}
+
+// UpdateDepartment replaces the code, name and aliases of a department
+func (r *departmentRepository) UpdateDepartment(id int64, department model.Department) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists int64
+	err = tx.QueryRowContext(ctx, "SELECT id FROM departments WHERE id = ? FOR UPDATE", id).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrDepartmentNotFound
+	}
+	if err != nil {
+		log.Println("Error querying department for update: " + err.Error())
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE departments SET code = ?, name = ? WHERE id = ?", department.Code, department.Name, id)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDuplicateEntry) {
+			return ErrDuplicateDepartment
+		}
+		log.Println("Error updating department: " + err.Error())
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM department_aliases WHERE department_id = ?", id); err != nil {
+		log.Println("Error deleting department aliases: " + err.Error())
+		return err
+	}
+	if err := insertDepartmentAliases(ctx, tx, id, department.Aliases); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteDepartment deletes a department that no student or course belongs to
+func (r *departmentRepository) DeleteDepartment(id int64) error {
+	err := deleteByID("departments", id, ErrDepartmentNotFound)
+	if isMySQLError(err, mysqlErrRowIsReferenced) {
+		return ErrDepartmentInUse
+	}
+	return err
+}
+
+// ResolveDepartment finds the department a free-text value refers to by its
+// name, code or one of its aliases, compared case-insensitively. Exact name
+// matches win over codes, and codes over aliases.
+func (r *departmentRepository) ResolveDepartment(name string) (model.Department, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var d model.Department
+
+	query := `
+		SELECT d.id, d.code, d.name, d.created_at
+		FROM departments d
+		LEFT JOIN department_aliases a ON a.department_id = d.id AND a.alias = ?
+		WHERE d.name = ? OR d.code = ? OR a.alias IS NOT NULL
+		ORDER BY d.name = ? DESC, d.code = ? DESC
+		LIMIT 1`
+	err := db.QueryRowContext(ctx, query, name, name, name, name, name).Scan(&d.ID, &d.Code, &d.Name, &d.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return d, ErrDepartmentNotFound
+		}
+		log.Println("Error resolving department: " + err.Error())
+		return d, err
+	}
+
+	return d, nil
+}
+
+func insertDepartmentAliases(ctx context.Context, tx *sql.Tx, departmentID int64, aliases []string) error {
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, 2*len(aliases))
+	for _, alias := range aliases {
+		args = append(args, alias, departmentID)
+	}
+
+	query := "INSERT INTO department_aliases (alias, department_id) VALUES " + placeholderRows(len(aliases), 2)
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		if isMySQLError(err, mysqlErrDuplicateEntry) {
+			return ErrDuplicateDepartment
+		}
+		log.Println("Error inserting department aliases: " + err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// departmentAliases groups the aliases returned by query per department
+func departmentAliases(ctx context.Context, query string, args ...any) (map[int64][]string, error) {
+	db := configuration.DB
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error querying department aliases: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := make(map[int64][]string)
+	for rows.Next() {
+		var id int64
+		var alias string
+		if err := rows.Scan(&id, &alias); err != nil {
+			return nil, err
+		}
+		aliases[id] = append(aliases[id], alias)
+	}
+
+	return aliases, rows.Err()
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveDepartmentByAlias(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &departmentRepository{}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT d.id, d.code, d.name, d.created_at FROM departments d LEFT JOIN department_aliases a ON a.department_id = d.id AND a.alias = ? WHERE d.name = ? OR d.code = ? OR a.alias IS NOT NULL")).
+		WithArgs("Comp Sci", "Comp Sci", "Comp Sci", "Comp Sci", "Comp Sci").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name", "created_at"}).AddRow(int64(1), "CS", "Computer Science", time.Now()))
+
+	department, err := repo.ResolveDepartment("Comp Sci")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), department.ID)
+	assert.Equal(t, "Computer Science", department.Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResolveDepartmentNotFound(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &departmentRepository{}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM departments d")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name", "created_at"}))
+
+	_, err := repo.ResolveDepartment("Astrology")
+
+	assert.ErrorIs(t, err, ErrDepartmentNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteDepartmentWithStudents(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &departmentRepository{}
+
+	mock.ExpectPrepare(regexp.QuoteMeta("DELETE FROM departments WHERE id = ?")).
+		ExpectExec().
+		WithArgs(int64(1)).
+		WillReturnError(&mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row"})
+
+	err := repo.DeleteDepartment(1)
+
+	assert.ErrorIs(t, err, ErrDepartmentInUse)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,331 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	configuration "github.com/shravanasati/scopex-go-assignment/configuration"
+	model "github.com/shravanasati/scopex-go-assignment/model"
+)
+
+type LeaveRepository interface {
+	CreateLeaveRequest(leave model.LeaveRequest) (int64, error)
+	GetLeaveRequestByID(id int64) (model.LeaveRequest, error)
+	GetLeaveRequests(filter model.LeaveRequestFilter, limit, offset int) (model.LeaveRequests, error)
+	HasOverlappingLeave(studentID int64, from, to string) (bool, error)
+	SetLeaveState(id int64, from, to string, decidedBy int64, note string) error
+	ApproveLeaveRequest(leave model.LeaveRequest, decidedBy int64, note string, days []string) error
+	CancelApprovedLeaveRequest(leave model.LeaveRequest, cancelledBy int64, note string) error
+	GetStudentsOnLeave(date string, studentIDs []int64) (map[int64]bool, error)
+}
+type leaveRepository struct{}
+
+var LeaveRepo LeaveRepository = &leaveRepository{}
+
+// ErrLeaveRequestNotFound indicates that the requested leave request does not
+// exist.
+var ErrLeaveRequestNotFound = errors.New("leave request not found")
+
+// ErrLeaveStateChanged is returned when a leave request left the expected
+// state before a transition could be applied.
+var ErrLeaveStateChanged = errors.New("leave request was changed concurrently")
+
+const leaveRequestColumns = "id, student_id, DATE_FORMAT(from_date, '%Y-%m-%d'), DATE_FORMAT(to_date, '%Y-%m-%d'), reason, state, requested_by, decided_by, decision_note, created_at, decided_at"
+
+// CreateLeaveRequest inserts a new pending leave request
+func (r *leaveRepository) CreateLeaveRequest(leave model.LeaveRequest) (int64, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "INSERT INTO leave_requests (student_id, from_date, to_date, reason, state, requested_by) VALUES (?, ?, ?, ?, ?, ?)"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, leave.StudentID, leave.FromDate, leave.ToDate, leave.Reason, model.LeaveStatePending, leave.RequestedBy)
+	if err != nil {
+		if isMySQLError(err, mysqlErrNoReferencedRow) {
+			return 0, ErrStudentNotFound
+		}
+		log.Println("Error inserting leave request: " + err.Error())
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+// GetLeaveRequestByID retrieves a leave request by ID
+func (r *leaveRepository) GetLeaveRequestByID(id int64) (model.LeaveRequest, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "SELECT " + leaveRequestColumns + " FROM leave_requests WHERE id = ?"
+	leave, err := scanLeaveRequest(db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return leave, ErrLeaveRequestNotFound
+		}
+		log.Println("Error querying leave request by ID: " + err.Error())
+		return leave, err
+	}
+
+	return leave, nil
+}
+
+// GetLeaveRequests retrieves a page of leave requests, newest first
+func (r *leaveRepository) GetLeaveRequests(filter model.LeaveRequestFilter, limit, offset int) (model.LeaveRequests, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var conditions []string
+	var args []any
+	if filter.StudentID != 0 {
+		conditions = append(conditions, "student_id = ?")
+		args = append(args, filter.StudentID)
+	}
+	if filter.State != "" {
+		conditions = append(conditions, "state = ?")
+		args = append(args, filter.State)
+	}
+	if len(filter.DepartmentIDs) > 0 {
+		conditions = append(conditions, "student_id IN (SELECT id FROM students WHERE department_id IN ("+placeholders(len(filter.DepartmentIDs))+"))")
+		for _, id := range filter.DepartmentIDs {
+			args = append(args, id)
+		}
+	}
+
+	query := "SELECT " + leaveRequestColumns + " FROM leave_requests"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?"
+
+	rows, err := db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		log.Println("Error querying leave requests: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	leaves := model.LeaveRequests{}
+	for rows.Next() {
+		leave, err := scanLeaveRequest(rows)
+		if err != nil {
+			log.Println("Error scanning leave request: " + err.Error())
+			return nil, err
+		}
+		leaves = append(leaves, leave)
+	}
+
+	return leaves, nil
+}
+
+// HasOverlappingLeave reports whether a pending or approved leave request of
+// the student overlaps the date range
+func (r *leaveRepository) HasOverlappingLeave(studentID int64, from, to string) (bool, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "SELECT COUNT(*) FROM leave_requests WHERE student_id = ? AND state IN (?, ?) AND from_date <= ? AND to_date >= ?"
+	var count int
+	err := db.QueryRowContext(ctx, query, studentID, model.LeaveStatePending, model.LeaveStateApproved, to, from).Scan(&count)
+	if err != nil {
+		log.Println("Error checking overlapping leave: " + err.Error())
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// SetLeaveState moves a leave request from one state to another, recording
+// who decided and why. It fails with ErrLeaveStateChanged when the request is
+// no longer in the from state.
+func (r *leaveRepository) SetLeaveState(id int64, from, to string, decidedBy int64, note string) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return setLeaveState(ctx, db, id, from, to, decidedBy, note)
+}
+
+// ApproveLeaveRequest approves a pending leave request and marks the student
+// OnLeave for the whole of each given day in the same transaction. Existing
+// whole-day marks on those days are overwritten and audited, and pending
+// geofence reviews of them are dropped.
+func (r *leaveRepository) ApproveLeaveRequest(leave model.LeaveRequest, decidedBy int64, note string, days []string) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := setLeaveState(ctx, tx, leave.ID, model.LeaveStatePending, model.LeaveStateApproved, decidedBy, note); err != nil {
+		return err
+	}
+
+	if len(days) > 0 {
+		audit := `
+			INSERT INTO attendance_audit (attendance_id, student_id, date, action, old_status, new_status, changed_by)
+			SELECT id, student_id, date, ?, status, ?, ? FROM attendance WHERE student_id = ? AND session_id = 0 AND status <> ? AND date IN (` + placeholders(len(days)) + `)
+		`
+		args := []any{model.AuditActionUpdate, model.StatusOnLeave, decidedBy, leave.StudentID, model.StatusOnLeave}
+		for _, d := range days {
+			args = append(args, d)
+		}
+		if _, err := tx.ExecContext(ctx, audit, args...); err != nil {
+			log.Println("Error writing attendance audit: " + err.Error())
+			return err
+		}
+
+		args = []any{leave.StudentID}
+		for _, d := range days {
+			args = append(args, d)
+		}
+		if err := clearPendingFlags(ctx, tx, "a.student_id = ? AND a.session_id = 0 AND a.date IN ("+placeholders(len(days))+")", args...); err != nil {
+			return err
+		}
+
+		args = make([]any, 0, len(days)*4)
+		for _, d := range days {
+			args = append(args, leave.StudentID, d, 0, model.StatusOnLeave)
+		}
+		query := "INSERT INTO attendance (student_id, date, session_id, status) VALUES " + placeholderRows(len(days), 4) + " ON DUPLICATE KEY UPDATE status = VALUES(status)"
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			log.Println("Error marking leave attendance: " + err.Error())
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// CancelApprovedLeaveRequest cancels an approved leave request and removes
+// the whole-day OnLeave marks it created, auditing each deletion.
+func (r *leaveRepository) CancelApprovedLeaveRequest(leave model.LeaveRequest, cancelledBy int64, note string) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := setLeaveState(ctx, tx, leave.ID, model.LeaveStateApproved, model.LeaveStateCancelled, cancelledBy, note); err != nil {
+		return err
+	}
+
+	audit := `
+		INSERT INTO attendance_audit (attendance_id, student_id, date, action, old_status, new_status, changed_by)
+		SELECT id, student_id, date, ?, status, NULL, ? FROM attendance WHERE student_id = ? AND session_id = 0 AND status = ? AND date BETWEEN ? AND ?
+	`
+	if _, err := tx.ExecContext(ctx, audit, model.AuditActionDelete, cancelledBy, leave.StudentID, model.StatusOnLeave, leave.FromDate, leave.ToDate); err != nil {
+		log.Println("Error writing attendance audit: " + err.Error())
+		return err
+	}
+
+	query := "DELETE FROM attendance WHERE student_id = ? AND session_id = 0 AND status = ? AND date BETWEEN ? AND ?"
+	if _, err := tx.ExecContext(ctx, query, leave.StudentID, model.StatusOnLeave, leave.FromDate, leave.ToDate); err != nil {
+		log.Println("Error removing leave attendance: " + err.Error())
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetStudentsOnLeave returns which of the students have approved leave
+// covering the date
+func (r *leaveRepository) GetStudentsOnLeave(date string, studentIDs []int64) (map[int64]bool, error) {
+	onLeave := make(map[int64]bool)
+	if len(studentIDs) == 0 {
+		return onLeave, nil
+	}
+
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "SELECT DISTINCT student_id FROM leave_requests WHERE state = ? AND from_date <= ? AND to_date >= ? AND student_id IN (" + placeholders(len(studentIDs)) + ")"
+	args := []any{model.LeaveStateApproved, date, date}
+	for _, id := range studentIDs {
+		args = append(args, id)
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error querying students on leave: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		onLeave[id] = true
+	}
+
+	return onLeave, rows.Err()
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func setLeaveState(ctx context.Context, db execer, id int64, from, to string, decidedBy int64, note string) error {
+	query := "UPDATE leave_requests SET state = ?, decided_by = ?, decision_note = ?, decided_at = CURRENT_TIMESTAMP WHERE id = ? AND state = ?"
+	result, err := db.ExecContext(ctx, query, to, decidedBy, note, id, from)
+	if err != nil {
+		log.Println("Error updating leave request state: " + err.Error())
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrLeaveStateChanged
+	}
+
+	return nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanLeaveRequest(row rowScanner) (model.LeaveRequest, error) {
+	var leave model.LeaveRequest
+	var decidedBy sql.NullInt64
+	var decidedAt sql.NullTime
+
+	err := row.Scan(&leave.ID, &leave.StudentID, &leave.FromDate, &leave.ToDate, &leave.Reason, &leave.State,
+		&leave.RequestedBy, &decidedBy, &leave.DecisionNote, &leave.CreatedAt, &decidedAt)
+	if err != nil {
+		return leave, err
+	}
+
+	leave.DecidedBy = decidedBy.Int64
+	if decidedAt.Valid {
+		leave.DecidedAt = &decidedAt.Time
+	}
+	return leave, nil
+}
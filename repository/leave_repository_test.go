@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApproveLeaveRequestMarksDays(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &leaveRepository{}
+
+	leave := model.LeaveRequest{ID: 3, StudentID: 1, FromDate: "2023-10-27", ToDate: "2023-10-30"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE leave_requests SET state = ?, decided_by = ?, decision_note = ?, decided_at = CURRENT_TIMESTAMP WHERE id = ? AND state = ?")).
+		WithArgs(model.LeaveStateApproved, int64(9), "ok", int64(3), model.LeaveStatePending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO attendance_audit")).
+		WithArgs(model.AuditActionUpdate, model.StatusOnLeave, int64(9), int64(1), model.StatusOnLeave, "2023-10-27", "2023-10-30").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE f FROM attendance_flags f JOIN attendance a ON a.id = f.attendance_id WHERE a.flag = ? AND a.student_id = ? AND a.session_id = 0 AND a.date IN (?, ?)")).
+		WithArgs(model.FlagStatePending, int64(1), "2023-10-27", "2023-10-30").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE attendance a SET a.flag = NULL WHERE a.flag = ? AND a.student_id = ? AND a.session_id = 0 AND a.date IN (?, ?)")).
+		WithArgs(model.FlagStatePending, int64(1), "2023-10-27", "2023-10-30").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO attendance (student_id, date, session_id, status) VALUES (?, ?, ?, ?), (?, ?, ?, ?) ON DUPLICATE KEY UPDATE status = VALUES(status)")).
+		WithArgs(int64(1), "2023-10-27", 0, model.StatusOnLeave, int64(1), "2023-10-30", 0, model.StatusOnLeave).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	err := repo.ApproveLeaveRequest(leave, 9, "ok", []string{"2023-10-27", "2023-10-30"})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApproveLeaveRequestStateChanged(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &leaveRepository{}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE leave_requests SET state = ?")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.ApproveLeaveRequest(model.LeaveRequest{ID: 3, StudentID: 1}, 9, "", []string{"2023-10-27"})
+
+	assert.ErrorIs(t, err, ErrLeaveStateChanged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetStudentsOnLeave(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &leaveRepository{}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT student_id FROM leave_requests WHERE state = ? AND from_date <= ? AND to_date >= ? AND student_id IN (?, ?)")).
+		WithArgs(model.LeaveStateApproved, "2023-10-30", "2023-10-30", int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"student_id"}).AddRow(int64(2)))
+
+	onLeave, err := repo.GetStudentsOnLeave("2023-10-30", []int64{1, 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]bool{2: true}, onLeave)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,329 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	configuration "github.com/shravanasati/scopex-go-assignment/configuration"
+	model "github.com/shravanasati/scopex-go-assignment/model"
+)
+
+type LocationRepository interface {
+	CreateLocation(location model.Location) (int64, error)
+	GetLocations() (model.Locations, error)
+	GetLocationByID(id int64) (model.Location, error)
+	UpdateLocation(id int64, location model.Location) error
+	DeleteLocation(id int64) error
+
+	GetAttendanceFlags(state string, departmentIDs []int64, limit, offset int) (model.AttendanceFlags, error)
+	GetAttendanceFlag(attendanceID int64) (model.AttendanceFlag, error)
+	ReviewAttendanceFlag(attendanceID int64, state, newStatus string, reviewedBy int64, note string) error
+}
+type locationRepository struct{}
+
+var LocationRepo LocationRepository = &locationRepository{}
+
+// ErrLocationNotFound indicates that the requested location does not exist.
+var ErrLocationNotFound = errors.New("location not found")
+
+// ErrFlagNotFound indicates that the attendance record was never flagged.
+var ErrFlagNotFound = errors.New("attendance flag not found")
+
+// ErrFlagReviewed is returned when reviewing a flag that is no longer pending.
+var ErrFlagReviewed = errors.New("attendance flag has already been reviewed")
+
+// CreateLocation inserts a new location
+func (r *locationRepository) CreateLocation(location model.Location) (int64, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "INSERT INTO locations (name, latitude, longitude, radius_meters) VALUES (?, ?, ?, ?)"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, location.Name, location.Latitude, location.Longitude, location.RadiusMeters)
+	if err != nil {
+		log.Println("Error inserting location: " + err.Error())
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+// GetLocations retrieves every location ordered by name
+func (r *locationRepository) GetLocations() (model.Locations, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	locations := model.Locations{}
+
+	query := "SELECT id, name, latitude, longitude, radius_meters, created_at FROM locations ORDER BY name ASC, id ASC"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		log.Println("Error querying locations: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l model.Location
+		if err := rows.Scan(&l.ID, &l.Name, &l.Latitude, &l.Longitude, &l.RadiusMeters, &l.CreatedAt); err != nil {
+			log.Println("Error scanning location: " + err.Error())
+			return nil, err
+		}
+		locations = append(locations, l)
+	}
+
+	return locations, rows.Err()
+}
+
+// GetLocationByID retrieves a location by ID
+func (r *locationRepository) GetLocationByID(id int64) (model.Location, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var l model.Location
+
+	query := "SELECT id, name, latitude, longitude, radius_meters, created_at FROM locations WHERE id = ?"
+	err := db.QueryRowContext(ctx, query, id).Scan(&l.ID, &l.Name, &l.Latitude, &l.Longitude, &l.RadiusMeters, &l.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return l, ErrLocationNotFound
+		}
+		log.Println("Error querying location by ID: " + err.Error())
+		return l, err
+	}
+
+	return l, nil
+}
+
+// UpdateLocation replaces the name, centre and radius of a location
+func (r *locationRepository) UpdateLocation(id int64, location model.Location) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "UPDATE locations SET name = ?, latitude = ?, longitude = ?, radius_meters = ? WHERE id = ?"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, location.Name, location.Latitude, location.Longitude, location.RadiusMeters, id)
+	if err != nil {
+		log.Println("Error updating location: " + err.Error())
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// nothing changed or nothing matched; tell the two apart
+		if _, err := r.GetLocationByID(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteLocation removes a location. Flags raised against it keep their
+// coordinates and distance but lose the location reference.
+func (r *locationRepository) DeleteLocation(id int64) error {
+	return deleteByID("locations", id, ErrLocationNotFound)
+}
+
+// flagAttendance records why a mark was stored as Flagged and puts it in
+// the review queue, inside the transaction that wrote the mark. Flagging the
+// same record again replaces the details and reopens the review.
+func flagAttendance(ctx context.Context, tx *sql.Tx, attendanceID int64, flag model.AttendanceFlag) error {
+	query := `
+		INSERT INTO attendance_flags (attendance_id, requested_status, location_id, latitude, longitude, distance_meters)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE requested_status = VALUES(requested_status), location_id = VALUES(location_id),
+			latitude = VALUES(latitude), longitude = VALUES(longitude), distance_meters = VALUES(distance_meters),
+			reviewed_by = NULL, review_note = '', reviewed_at = NULL, created_at = CURRENT_TIMESTAMP
+	`
+	_, err := tx.ExecContext(ctx, query, attendanceID, flag.RequestedStatus, flag.LocationID, flag.Latitude, flag.Longitude, flag.DistanceMeters)
+	if err != nil {
+		log.Println("Error inserting attendance flag: " + err.Error())
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE attendance SET flag = ? WHERE id = ?", model.FlagStatePending, attendanceID); err != nil {
+		log.Println("Error flagging attendance: " + err.Error())
+		return err
+	}
+	return nil
+}
+
+// clearPendingFlags drops the pending reviews of the attendance records
+// matching where (over attendance a), for marks that are being overwritten.
+// Reviewed flags are kept as history.
+func clearPendingFlags(ctx context.Context, tx *sql.Tx, where string, args ...any) error {
+	query := "DELETE f FROM attendance_flags f JOIN attendance a ON a.id = f.attendance_id WHERE a.flag = ? AND " + where
+	if _, err := tx.ExecContext(ctx, query, append([]any{model.FlagStatePending}, args...)...); err != nil {
+		log.Println("Error clearing attendance flags: " + err.Error())
+		return err
+	}
+
+	query = "UPDATE attendance a SET a.flag = NULL WHERE a.flag = ? AND " + where
+	if _, err := tx.ExecContext(ctx, query, append([]any{model.FlagStatePending}, args...)...); err != nil {
+		log.Println("Error clearing attendance flags: " + err.Error())
+		return err
+	}
+	return nil
+}
+
+const attendanceFlagQuery = `
+	SELECT a.id, a.student_id, s.name, DATE_FORMAT(a.date, '%Y-%m-%d'), a.session_id, a.status, f.requested_status, a.flag,
+		f.location_id, f.latitude, f.longitude, f.distance_meters, f.reviewed_by, f.review_note, f.created_at, f.reviewed_at
+	FROM attendance_flags f
+	JOIN attendance a ON a.id = f.attendance_id
+	JOIN students s ON s.id = a.student_id`
+
+func scanAttendanceFlag(row rowScanner) (model.AttendanceFlag, error) {
+	var f model.AttendanceFlag
+	var locationID, reviewedBy sql.NullInt64
+	var reviewedAt sql.NullTime
+
+	err := row.Scan(&f.AttendanceID, &f.StudentID, &f.StudentName, &f.Date, &f.SessionID, &f.Status, &f.RequestedStatus, &f.State,
+		&locationID, &f.Latitude, &f.Longitude, &f.DistanceMeters, &reviewedBy, &f.ReviewNote, &f.CreatedAt, &reviewedAt)
+	if err != nil {
+		return f, err
+	}
+
+	if locationID.Valid {
+		f.LocationID = &locationID.Int64
+	}
+	if reviewedBy.Valid {
+		f.ReviewedBy = &reviewedBy.Int64
+	}
+	if reviewedAt.Valid {
+		f.ReviewedAt = &reviewedAt.Time
+	}
+	return f, nil
+}
+
+// GetAttendanceFlags retrieves flagged marks in a review state, oldest
+// first so the queue is worked in order. An empty state returns every flag,
+// and departmentIDs limits them to students of those departments when set.
+func (r *locationRepository) GetAttendanceFlags(state string, departmentIDs []int64, limit, offset int) (model.AttendanceFlags, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	flags := model.AttendanceFlags{}
+
+	var conditions []string
+	var args []any
+	if state != "" {
+		conditions = append(conditions, "a.flag = ?")
+		args = append(args, state)
+	}
+	if len(departmentIDs) > 0 {
+		conditions = append(conditions, "s.department_id IN ("+placeholders(len(departmentIDs))+")")
+		for _, id := range departmentIDs {
+			args = append(args, id)
+		}
+	}
+
+	query := attendanceFlagQuery
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY f.created_at ASC, a.id ASC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error querying attendance flags: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		f, err := scanAttendanceFlag(rows)
+		if err != nil {
+			log.Println("Error scanning attendance flag: " + err.Error())
+			return nil, err
+		}
+		flags = append(flags, f)
+	}
+
+	return flags, rows.Err()
+}
+
+// GetAttendanceFlag retrieves the flag of one attendance record
+func (r *locationRepository) GetAttendanceFlag(attendanceID int64) (model.AttendanceFlag, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	f, err := scanAttendanceFlag(db.QueryRowContext(ctx, attendanceFlagQuery+" WHERE a.id = ?", attendanceID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return f, ErrFlagNotFound
+		}
+		log.Println("Error querying attendance flag: " + err.Error())
+		return f, err
+	}
+
+	return f, nil
+}
+
+// ReviewAttendanceFlag closes a pending flag with the given state and sets
+// the record's status, keeping the Flagged status in the audit trail.
+func (r *locationRepository) ReviewAttendanceFlag(attendanceID int64, state, newStatus string, reviewedBy int64, note string) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockAttendance(ctx, tx, attendanceID); err != nil {
+		return err
+	}
+
+	if err := insertAttendanceAudit(ctx, tx, attendanceID, model.AuditActionUpdate, newStatus, reviewedBy); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, "UPDATE attendance SET status = ?, flag = ? WHERE id = ? AND flag = ?",
+		newStatus, state, attendanceID, model.FlagStatePending)
+	if err != nil {
+		log.Println("Error reviewing attendance flag: " + err.Error())
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrFlagReviewed
+	}
+
+	query := "UPDATE attendance_flags SET reviewed_by = ?, review_note = ?, reviewed_at = CURRENT_TIMESTAMP WHERE attendance_id = ?"
+	if _, err := tx.ExecContext(ctx, query, reviewedBy, note, attendanceID); err != nil {
+		log.Println("Error reviewing attendance flag: " + err.Error())
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func expectLockAttendance(mock sqlmock.Sqlmock, id int64, status string) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, date, session_id, status FROM attendance WHERE id = ? FOR UPDATE")).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "student_id", "date", "session_id", "status"}).AddRow(id, 1, "2023-10-27", 0, status))
+}
+
+func TestReviewAttendanceFlagApproves(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &locationRepository{}
+
+	mock.ExpectBegin()
+	expectLockAttendance(mock, 42, model.StatusFlagged)
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO attendance_audit")).
+		WithArgs(model.AuditActionUpdate, driver.Value("Present"), int64(7), int64(42)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE attendance SET status = ?, flag = ? WHERE id = ? AND flag = ?")).
+		WithArgs("Present", model.FlagStateApproved, int64(42), model.FlagStatePending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE attendance_flags SET reviewed_by = ?, review_note = ?, reviewed_at = CURRENT_TIMESTAMP WHERE attendance_id = ?")).
+		WithArgs(int64(7), "ok", int64(42)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.ReviewAttendanceFlag(42, model.FlagStateApproved, "Present", 7, "ok")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewAttendanceFlagAlreadyReviewed(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &locationRepository{}
+
+	mock.ExpectBegin()
+	expectLockAttendance(mock, 42, "Present")
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO attendance_audit")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE attendance SET status = ?, flag = ? WHERE id = ? AND flag = ?")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.ReviewAttendanceFlag(42, model.FlagStateRejected, model.StatusAbsent, 7, "")
+
+	assert.ErrorIs(t, err, ErrFlagReviewed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkAttendanceQueuesFlagInSameTransaction(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	locationID := int64(1)
+	attendance := model.Attendance{StudentID: 1, Date: "2023-10-27", Status: model.StatusFlagged}
+	flag := model.AttendanceFlag{RequestedStatus: "Present", LocationID: &locationID, Latitude: 12.98, Longitude: 77.59, DistanceMeters: 1112}
+
+	mock.ExpectBegin()
+	prep := mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO attendance (student_id, date, session_id, status) VALUES (?, ?, ?, ?)"))
+	prep.ExpectExec().
+		WithArgs(int64(1), "2023-10-27", int64(0), model.StatusFlagged).
+		WillReturnResult(sqlmock.NewResult(42, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO attendance_flags")).
+		WithArgs(int64(42), "Present", &locationID, 12.98, 77.59, 1112).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE attendance SET flag = ? WHERE id = ?")).
+		WithArgs(model.FlagStatePending, int64(42)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	id, err := MarkAttendance(attendance, &flag)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertAttendanceReflagsOverwrittenMark(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	attendance := model.Attendance{StudentID: 1, Date: "2023-10-27", Status: model.StatusFlagged}
+	flag := model.AttendanceFlag{RequestedStatus: "Present", Latitude: 12.98, Longitude: 77.59, DistanceMeters: 1112}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO attendance_audit")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	prep := mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO attendance (student_id, date, session_id, status) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE"))
+	prep.ExpectExec().
+		WithArgs(int64(1), "2023-10-27", int64(0), model.StatusFlagged).
+		WillReturnResult(sqlmock.NewResult(42, 2))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO attendance_flags")).
+		WithArgs(int64(42), "Present", (*int64)(nil), 12.98, 77.59, 1112).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE attendance SET flag = ? WHERE id = ?")).
+		WithArgs(model.FlagStatePending, int64(42)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	id, created, err := UpsertAttendance(attendance, &flag, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), id)
+	assert.False(t, created)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkAttendanceFlagFailureRollsBackMark(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+
+	attendance := model.Attendance{StudentID: 1, Date: "2023-10-27", Status: model.StatusFlagged}
+	flag := model.AttendanceFlag{RequestedStatus: "Present", Latitude: 12.98, Longitude: 77.59, DistanceMeters: 1112}
+
+	mock.ExpectBegin()
+	prep := mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO attendance (student_id, date, session_id, status) VALUES (?, ?, ?, ?)"))
+	prep.ExpectExec().
+		WithArgs(int64(1), "2023-10-27", int64(0), model.StatusFlagged).
+		WillReturnResult(sqlmock.NewResult(42, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO attendance_flags")).
+		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	_, err := MarkAttendance(attendance, &flag)
+
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	configuration "github.com/shravanasati/scopex-go-assignment/configuration"
+	model "github.com/shravanasati/scopex-go-assignment/model"
+)
+
+type LockOverrideRepository interface {
+	CreateOverride(override model.LockOverride) error
+	GetOverrides(limit, offset int) (model.LockOverrides, error)
+}
+type lockOverrideRepository struct{}
+
+var LockOverrideRepo LockOverrideRepository = &lockOverrideRepository{}
+
+// CreateOverride appends an entry to the lock override audit log
+func (r *lockOverrideRepository) CreateOverride(override model.LockOverride) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO attendance_lock_overrides (action, attendance_id, student_id, date, session_id, status, user_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := db.ExecContext(ctx, query, override.Action, override.AttendanceID, override.StudentID, override.Date,
+		override.SessionID, override.Status, override.UserID)
+	if err != nil {
+		log.Println("Error inserting lock override: " + err.Error())
+	}
+	return err
+}
+
+// GetOverrides retrieves the lock override audit log, newest first
+func (r *lockOverrideRepository) GetOverrides(limit, offset int) (model.LockOverrides, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	overrides := model.LockOverrides{}
+
+	query := `
+		SELECT id, action, attendance_id, student_id, DATE_FORMAT(date, '%Y-%m-%d'), session_id, status, user_id, created_at
+		FROM attendance_lock_overrides
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+	rows, err := db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		log.Println("Error querying lock overrides: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var o model.LockOverride
+		err := rows.Scan(&o.ID, &o.Action, &o.AttendanceID, &o.StudentID, &o.Date, &o.SessionID, &o.Status, &o.UserID, &o.CreatedAt)
+		if err != nil {
+			log.Println("Error scanning lock override: " + err.Error())
+			return nil, err
+		}
+		overrides = append(overrides, o)
+	}
+
+	return overrides, rows.Err()
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	configuration "github.com/shravanasati/scopex-go-assignment/configuration"
+	model "github.com/shravanasati/scopex-go-assignment/model"
+)
+
+type RoleRepository interface {
+	GetRoles() (model.Roles, error)
+	GetUserGrants(userID int64) (model.Grants, error)
+	SetUserRoles(userID int64, roles []string) error
+}
+type roleRepository struct{}
+
+var RoleRepo RoleRepository = &roleRepository{}
+
+// ErrRoleNotFound is returned when a role to assign does not exist.
+var ErrRoleNotFound = errors.New("role not found")
+
+// GetRoles retrieves every role with its permissions
+func (r *roleRepository) GetRoles() (model.Roles, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT r.code, r.description, COALESCE(rp.permission_code, '')
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role_code = r.code
+		ORDER BY r.code ASC, rp.permission_code ASC
+	`
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		log.Println("Error querying roles: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := model.Roles{}
+	for rows.Next() {
+		var code, description, permission string
+		if err := rows.Scan(&code, &description, &permission); err != nil {
+			log.Println("Error scanning role: " + err.Error())
+			return nil, err
+		}
+		if len(roles) == 0 || roles[len(roles)-1].Code != code {
+			roles = append(roles, model.Role{Code: code, Description: description, Permissions: []string{}})
+		}
+		if permission != "" {
+			last := &roles[len(roles)-1]
+			last.Permissions = append(last.Permissions, permission)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// GetUserGrants retrieves the roles of a user and the distinct permissions
+// they grant
+func (r *roleRepository) GetUserGrants(userID int64) (model.Grants, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	grants := model.Grants{Roles: []string{}, Permissions: []string{}}
+
+	roles, err := db.QueryContext(ctx, "SELECT role_code FROM user_roles WHERE user_id = ? ORDER BY role_code ASC", userID)
+	if err != nil {
+		log.Println("Error querying user roles: " + err.Error())
+		return model.Grants{}, err
+	}
+	defer roles.Close()
+
+	for roles.Next() {
+		var role string
+		if err := roles.Scan(&role); err != nil {
+			return model.Grants{}, err
+		}
+		grants.Roles = append(grants.Roles, role)
+	}
+	if err := roles.Err(); err != nil {
+		return model.Grants{}, err
+	}
+
+	query := `
+		SELECT DISTINCT rp.permission_code
+		FROM user_roles ur
+		JOIN role_permissions rp ON rp.role_code = ur.role_code
+		WHERE ur.user_id = ?
+		ORDER BY rp.permission_code ASC
+	`
+	permissions, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		log.Println("Error querying user permissions: " + err.Error())
+		return model.Grants{}, err
+	}
+	defer permissions.Close()
+
+	for permissions.Next() {
+		var permission string
+		if err := permissions.Scan(&permission); err != nil {
+			return model.Grants{}, err
+		}
+		grants.Permissions = append(grants.Permissions, permission)
+	}
+	if err := permissions.Err(); err != nil {
+		return model.Grants{}, err
+	}
+
+	return grants, nil
+}
+
+// SetUserRoles replaces the roles of an existing user
+func (r *roleRepository) SetUserRoles(userID int64, roles []string) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_roles WHERE user_id = ?", userID); err != nil {
+		log.Println("Error clearing user roles: " + err.Error())
+		return err
+	}
+
+	if len(roles) > 0 {
+		args := make([]interface{}, 0, len(roles)*2)
+		for _, role := range roles {
+			args = append(args, userID, role)
+		}
+		query := "INSERT INTO user_roles (user_id, role_code) VALUES " + placeholderRows(len(roles), 2)
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			if isMySQLError(err, mysqlErrNoReferencedRow) {
+				return ErrRoleNotFound
+			}
+			log.Println("Error inserting user roles: " + err.Error())
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRolesGroupsPermissions(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &roleRepository{}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM roles r LEFT JOIN role_permissions rp ON rp.role_code = r.code")).
+		WillReturnRows(sqlmock.NewRows([]string{"code", "description", "permission_code"}).
+			AddRow("auditor", "Read-only", "").
+			AddRow("teacher", "Marks attendance", "attendance:mark").
+			AddRow("teacher", "Marks attendance", "students:read"))
+
+	roles, err := repo.GetRoles()
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.Roles{
+		{Code: "auditor", Description: "Read-only", Permissions: []string{}},
+		{Code: "teacher", Description: "Marks attendance", Permissions: []string{"attendance:mark", "students:read"}},
+	}, roles)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUserGrants(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &roleRepository{}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT role_code FROM user_roles WHERE user_id = ?")).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"role_code"}).AddRow("student").AddRow("teacher"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT rp.permission_code FROM user_roles ur")).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"permission_code"}).AddRow("attendance:mark").AddRow("leave:request"))
+
+	grants, err := repo.GetUserGrants(2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"student", "teacher"}, grants.Roles)
+	assert.Equal(t, []string{"attendance:mark", "leave:request"}, grants.Permissions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetUserRolesUnknownRole(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &roleRepository{}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM user_roles WHERE user_id = ?")).
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO user_roles (user_id, role_code) VALUES (?, ?), (?, ?)")).
+		WithArgs(int64(2), "teacher", int64(2), "janitor").
+		WillReturnError(&mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"})
+	mock.ExpectRollback()
+
+	err := repo.SetUserRoles(2, []string{"teacher", "janitor"})
+
+	assert.ErrorIs(t, err, ErrRoleNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	configuration "github.com/shravanasati/scopex-go-assignment/configuration"
+	model "github.com/shravanasati/scopex-go-assignment/model"
+)
+
+type SessionRepository interface {
+	CreateSession(session model.Session) (int64, error)
+	GetSessionsByDate(date string) (model.Sessions, error)
+	GetSessionByID(id int64) (model.Session, error)
+	DeleteSession(id, deletedBy int64) (model.Attendances, error)
+	CreateSessions(sessions model.Sessions) (int, error)
+	GetSessionsToClose(endedBefore time.Time) (model.Sessions, error)
+}
+type sessionRepository struct{}
+
+var SessionRepo SessionRepository = &sessionRepository{}
+
+// ErrSessionNotFound indicates that the requested session does not exist.
+var ErrSessionNotFound = errors.New("session not found")
+
+const sessionQuery = `
+	SELECT id, DATE_FORMAT(date, '%Y-%m-%d'), period, TIME_FORMAT(start_time, '%H:%i'), TIME_FORMAT(end_time, '%H:%i'), title,
+		COALESCE(section_id, 0), COALESCE(department_id, 0), COALESCE(timetable_entry_id, 0), closed_at, created_at
+	FROM sessions`
+
+func scanSession(row rowScanner) (model.Session, error) {
+	var s model.Session
+	var closedAt sql.NullTime
+
+	err := row.Scan(&s.ID, &s.Date, &s.Period, &s.StartTime, &s.EndTime, &s.Title, &s.SectionID, &s.DepartmentID, &s.TimetableEntryID, &closedAt, &s.CreatedAt)
+	if err != nil {
+		return s, err
+	}
+	if closedAt.Valid {
+		s.ClosedAt = &closedAt.Time
+	}
+
+	return s, nil
+}
+
+// CreateSession inserts a new session
+func (r *sessionRepository) CreateSession(session model.Session) (int64, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "INSERT INTO sessions (date, period, start_time, end_time, title, section_id, department_id) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, session.Date, session.Period, session.StartTime, session.EndTime, session.Title, nullableID(session.SectionID), nullableID(session.DepartmentID))
+	if err != nil {
+		if isMySQLError(err, mysqlErrNoReferencedRow) {
+			if session.DepartmentID != 0 {
+				return 0, ErrDepartmentNotFound
+			}
+			return 0, ErrSectionNotFound
+		}
+		log.Println("Error inserting session: " + err.Error())
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+// GetSessionsByDate retrieves the sessions held on a date ordered by start time
+func (r *sessionRepository) GetSessionsByDate(date string) (model.Sessions, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var sessions model.Sessions
+
+	query := sessionQuery + " WHERE date = ? ORDER BY start_time ASC, period ASC"
+	rows, err := db.QueryContext(ctx, query, date)
+	if err != nil {
+		log.Println("Error querying sessions: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			log.Println("Error scanning session: " + err.Error())
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+
+	return sessions, nil
+}
+
+// GetSessionByID retrieves a session by ID
+func (r *sessionRepository) GetSessionByID(id int64) (model.Session, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s, err := scanSession(db.QueryRowContext(ctx, sessionQuery+" WHERE id = ?", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return s, ErrSessionNotFound
+		}
+		log.Println("Error querying session by ID: " + err.Error())
+		return s, err
+	}
+
+	return s, nil
+}
+
+// DeleteSession deletes a session together with the attendance marked for it,
+// auditing each removed mark against deletedBy. It returns the removed marks.
+func (r *sessionRepository) DeleteSession(id, deletedBy int64) (model.Attendances, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM sessions WHERE id = ?", id)
+	if err != nil {
+		log.Println("Error deleting session: " + err.Error())
+		return nil, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, ErrSessionNotFound
+	}
+
+	rows, err := tx.QueryContext(ctx, "SELECT id, student_id, DATE_FORMAT(date, '%Y-%m-%d'), session_id, status FROM attendance WHERE session_id = ? FOR UPDATE", id)
+	if err != nil {
+		log.Println("Error loading session attendance: " + err.Error())
+		return nil, err
+	}
+	var removed model.Attendances
+	for rows.Next() {
+		var a model.Attendance
+		if err := rows.Scan(&a.ID, &a.StudentID, &a.Date, &a.SessionID, &a.Status); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		removed = append(removed, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO attendance_audit (attendance_id, student_id, date, action, old_status, new_status, changed_by)
+		SELECT id, student_id, date, ?, status, NULL, ?
+		FROM attendance
+		WHERE session_id = ?`, model.AuditActionDelete, deletedBy, id); err != nil {
+		log.Println("Error auditing session attendance: " + err.Error())
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM attendance WHERE session_id = ?", id); err != nil {
+		log.Println("Error deleting session attendance: " + err.Error())
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// sessionInsertBatchSize caps the rows of one multi-row session INSERT
+const sessionInsertBatchSize = 500
+
+// CreateSessions inserts generated sessions in one transaction and returns
+// how many were new. A timetable entry yields at most one session per date,
+// so sessions generated before are left untouched.
+func (r *sessionRepository) CreateSessions(sessions model.Sessions) (int, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	created := 0
+	for start := 0; start < len(sessions); start += sessionInsertBatchSize {
+		batch := sessions[start:min(start+sessionInsertBatchSize, len(sessions))]
+
+		args := make([]any, 0, 7*len(batch))
+		for _, s := range batch {
+			args = append(args, s.Date, s.Period, s.StartTime, s.EndTime, s.Title, nullableID(s.SectionID), nullableID(s.TimetableEntryID))
+		}
+
+		query := "INSERT INTO sessions (date, period, start_time, end_time, title, section_id, timetable_entry_id) VALUES " +
+			placeholderRows(len(batch), 7) + " ON DUPLICATE KEY UPDATE id = id"
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			if isMySQLError(err, mysqlErrNoReferencedRow) {
+				return 0, ErrSectionNotFound
+			}
+			log.Println("Error inserting generated sessions: " + err.Error())
+			return 0, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		created += int(affected)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return created, nil
+}
+
+// GetSessionsToClose retrieves the open sessions that ended before the given
+// time, oldest first
+func (r *sessionRepository) GetSessionsToClose(endedBefore time.Time) (model.Sessions, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var sessions model.Sessions
+
+	query := sessionQuery + " WHERE closed_at IS NULL AND TIMESTAMP(date, end_time) <= ? ORDER BY date ASC, end_time ASC"
+	rows, err := db.QueryContext(ctx, query, endedBefore.Format("2006-01-02 15:04:05"))
+	if err != nil {
+		log.Println("Error querying sessions to close: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			log.Println("Error scanning session: " + err.Error())
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+
+	return sessions, rows.Err()
+}
+
+// nullableID stores an optional foreign key, where 0 stands for none, as NULL
+func nullableID(id int64) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var sessionColumns = []string{"id", "date", "period", "start_time", "end_time", "title", "section_id", "department_id", "timetable_entry_id", "closed_at", "created_at"}
+
+func TestGetSessionByIDNotFound(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &sessionRepository{}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM sessions WHERE id = ?")).
+		WithArgs(int64(4)).
+		WillReturnRows(sqlmock.NewRows(sessionColumns))
+
+	_, err := repo.GetSessionByID(4)
+
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSessionsByDateSuccess(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &sessionRepository{}
+
+	rows := sqlmock.NewRows(sessionColumns).
+		AddRow(int64(1), "2023-10-27", 1, "09:00", "09:50", "Algebra", int64(0), int64(2), int64(0), nil, time.Now()).
+		AddRow(int64(2), "2023-10-27", 2, "10:00", "10:50", "Physics", int64(3), int64(0), int64(5), time.Now(), time.Now())
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM sessions WHERE date = ? ORDER BY start_time ASC, period ASC")).
+		WithArgs("2023-10-27").
+		WillReturnRows(rows)
+
+	sessions, err := repo.GetSessionsByDate("2023-10-27")
+
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	assert.Equal(t, "10:00", sessions[1].StartTime)
+	assert.Equal(t, int64(2), sessions[0].DepartmentID)
+	assert.Equal(t, int64(3), sessions[1].SectionID)
+	assert.Equal(t, int64(5), sessions[1].TimetableEntryID)
+	assert.Nil(t, sessions[0].ClosedAt)
+	assert.NotNil(t, sessions[1].ClosedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteSessionRemovesAttendance(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &sessionRepository{}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM sessions WHERE id = ?")).
+		WithArgs(int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, student_id, DATE_FORMAT(date, '%Y-%m-%d'), session_id, status FROM attendance WHERE session_id = ? FOR UPDATE")).
+		WithArgs(int64(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "student_id", "date", "session_id", "status"}).
+			AddRow(int64(11), int64(1), "2023-10-27", int64(4), "Present").
+			AddRow(int64(12), int64(2), "2023-10-27", int64(4), "Absent"))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO attendance_audit (attendance_id, student_id, date, action, old_status, new_status, changed_by) SELECT id, student_id, date, ?, status, NULL, ? FROM attendance WHERE session_id = ?")).
+		WithArgs(model.AuditActionDelete, int64(9), int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM attendance WHERE session_id = ?")).
+		WithArgs(int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	removed, err := repo.DeleteSession(4, 9)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.Attendances{
+		{ID: 11, StudentID: 1, Date: "2023-10-27", SessionID: 4, Status: "Present"},
+		{ID: 12, StudentID: 2, Date: "2023-10-27", SessionID: 4, Status: "Absent"},
+	}, removed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteSessionNotFound(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &sessionRepository{}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM sessions WHERE id = ?")).
+		WithArgs(int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err := repo.DeleteSession(4, 9)
+
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateSessionsCountsNewSessions(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &sessionRepository{}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO sessions (date, period, start_time, end_time, title, section_id, timetable_entry_id) VALUES (?, ?, ?, ?, ?, ?, ?), (?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE id = id")).
+		WithArgs("2023-10-23", 0, "09:00", "09:50", "Algebra", int64(2), int64(7), "2023-10-30", 0, "09:00", "09:50", "Algebra", int64(2), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	created, err := repo.CreateSessions(model.Sessions{
+		{Date: "2023-10-23", StartTime: "09:00", EndTime: "09:50", Title: "Algebra", SectionID: 2, TimetableEntryID: 7},
+		{Date: "2023-10-30", StartTime: "09:00", EndTime: "09:50", Title: "Algebra", SectionID: 2, TimetableEntryID: 7},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSessionsToClose(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &sessionRepository{}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM sessions WHERE closed_at IS NULL AND TIMESTAMP(date, end_time) <= ?")).
+		WithArgs("2023-10-27 10:20:00").
+		WillReturnRows(sqlmock.NewRows(sessionColumns).
+			AddRow(int64(4), "2023-10-27", 1, "09:00", "09:50", "Algebra", int64(2), int64(0), int64(7), nil, time.Now()))
+
+	sessions, err := repo.GetSessionsToClose(time.Date(2023, 10, 27, 10, 20, 0, 0, time.Local))
+
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -13,9 +13,10 @@ import (
 
 type StudentRepository interface {
 	CreateStudent(student model.Student) (int64, error)
-	GetAllStudents(limit, offset int) (model.Students, error)
+	GetAllStudents(departmentIDs []int64, limit, offset int) (model.Students, error)
 	GetStudentByID(id int64) (model.Student, error)
 	GetStudentByEmail(email string) (model.Student, error)
+	GetStudentDepartments(ids []int64) (map[int64]int64, error)
 	UpdateStudent(id int64, student model.Student) error
 	DeleteStudent(id int64) error
 }
@@ -33,7 +34,7 @@ func (r *studentRepository) CreateStudent(student model.Student) (int64, error)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := "INSERT INTO students (name, email, department) VALUES (?, ?, ?)"
+	query := "INSERT INTO students (name, email, department_id) VALUES (?, ?, ?)"
 	stmt, err := db.PrepareContext(ctx, query)
 	if err != nil {
 		log.Println("Error preparing statement: " + err.Error())
@@ -41,8 +42,11 @@ func (r *studentRepository) CreateStudent(student model.Student) (int64, error)
 	}
 	defer stmt.Close()
 
-	result, err := stmt.ExecContext(ctx, student.Name, student.Email, student.Department)
+	result, err := stmt.ExecContext(ctx, student.Name, student.Email, nullableID(student.DepartmentID))
 	if err != nil {
+		if isMySQLError(err, mysqlErrNoReferencedRow) {
+			return 0, ErrDepartmentNotFound
+		}
 		log.Println("Error inserting student: " + err.Error())
 		return 0, err
 	}
@@ -55,16 +59,27 @@ func (r *studentRepository) CreateStudent(student model.Student) (int64, error)
 	return id, nil
 }
 
-// GetAllStudents retrieves all students with pagination
-func (r *studentRepository) GetAllStudents(limit, offset int) (model.Students, error) {
+// GetAllStudents retrieves all students with pagination, or only those of the
+// given departments when departmentIDs is not empty
+func (r *studentRepository) GetAllStudents(departmentIDs []int64, limit, offset int) (model.Students, error) {
 	db := configuration.DB
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	var students model.Students
 
-	query := "SELECT id, name, email, department, created_at FROM students LIMIT ? OFFSET ?"
-	rows, err := db.QueryContext(ctx, query, limit, offset)
+	query := "SELECT s.id, s.name, s.email, COALESCE(s.department_id, 0), COALESCE(d.name, ''), s.created_at FROM students s LEFT JOIN departments d ON d.id = s.department_id"
+	var args []any
+	if len(departmentIDs) > 0 {
+		query += " WHERE s.department_id IN (" + placeholders(len(departmentIDs)) + ")"
+		for _, id := range departmentIDs {
+			args = append(args, id)
+		}
+	}
+	query += " ORDER BY s.id ASC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		log.Println("Error querying students: " + err.Error())
 		return nil, err
@@ -76,7 +91,7 @@ func (r *studentRepository) GetAllStudents(limit, offset int) (model.Students, e
 		// Scan created_at as []uint8 (byte slice) if driver returns it as such, or time.Time if configured.
 		// The mysql driver usually handles time.Time if parseTime=true is in DSN.
 		// Let's assume standard scanning works.
-		err := rows.Scan(&s.ID, &s.Name, &s.Email, &s.Department, &s.CreatedAt)
+		err := rows.Scan(&s.ID, &s.Name, &s.Email, &s.DepartmentID, &s.Department, &s.CreatedAt)
 		if err != nil {
 			log.Println("Error scanning student: " + err.Error())
 			return nil, err
@@ -95,8 +110,8 @@ func (r *studentRepository) GetStudentByID(id int64) (model.Student, error) {
 
 	var s model.Student
 
-	query := "SELECT id, name, email, department, created_at FROM students WHERE id = ?"
-	err := db.QueryRowContext(ctx, query, id).Scan(&s.ID, &s.Name, &s.Email, &s.Department, &s.CreatedAt)
+	query := "SELECT s.id, s.name, s.email, COALESCE(s.department_id, 0), COALESCE(d.name, ''), s.created_at FROM students s LEFT JOIN departments d ON d.id = s.department_id WHERE s.id = ?"
+	err := db.QueryRowContext(ctx, query, id).Scan(&s.ID, &s.Name, &s.Email, &s.DepartmentID, &s.Department, &s.CreatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return s, ErrStudentNotFound
@@ -116,8 +131,8 @@ func (r *studentRepository) GetStudentByEmail(email string) (model.Student, erro
 
 	var s model.Student
 
-	query := "SELECT id, name, email, department, created_at FROM students WHERE email = ?"
-	err := db.QueryRowContext(ctx, query, email).Scan(&s.ID, &s.Name, &s.Email, &s.Department, &s.CreatedAt)
+	query := "SELECT s.id, s.name, s.email, COALESCE(s.department_id, 0), COALESCE(d.name, ''), s.created_at FROM students s LEFT JOIN departments d ON d.id = s.department_id WHERE s.email = ?"
+	err := db.QueryRowContext(ctx, query, email).Scan(&s.ID, &s.Name, &s.Email, &s.DepartmentID, &s.Department, &s.CreatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return model.Student{}, nil
@@ -128,21 +143,61 @@ func (r *studentRepository) GetStudentByEmail(email string) (model.Student, erro
 	return s, nil
 }
 
+// GetStudentDepartments maps each of the given students to its department
+// ID (0 for none) in one query. Unknown students are left out of the map.
+func (r *studentRepository) GetStudentDepartments(ids []int64) (map[int64]int64, error) {
+	departments := make(map[int64]int64, len(ids))
+	if len(ids) == 0 {
+		return departments, nil
+	}
+
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	query := "SELECT id, COALESCE(department_id, 0) FROM students WHERE id IN (" + placeholders(len(ids)) + ")"
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error querying student departments: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, departmentID int64
+		if err := rows.Scan(&id, &departmentID); err != nil {
+			log.Println("Error scanning student department: " + err.Error())
+			return nil, err
+		}
+		departments[id] = departmentID
+	}
+
+	return departments, rows.Err()
+}
+
 // UpdateStudent updates an existing student
 func (r *studentRepository) UpdateStudent(id int64, student model.Student) error {
 	db := configuration.DB
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := "UPDATE students SET name = ?, email = ?, department = ? WHERE id = ?"
+	query := "UPDATE students SET name = ?, email = ?, department_id = ? WHERE id = ?"
 	stmt, err := db.PrepareContext(ctx, query)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	result, err := stmt.ExecContext(ctx, student.Name, student.Email, student.Department, id)
+	result, err := stmt.ExecContext(ctx, student.Name, student.Email, nullableID(student.DepartmentID), id)
 	if err != nil {
+		if isMySQLError(err, mysqlErrNoReferencedRow) {
+			return ErrDepartmentNotFound
+		}
 		log.Println("Error updating student: " + err.Error())
 		return err
 	}
@@ -33,10 +33,10 @@ func TestGetStudentByEmailFound(t *testing.T) {
 
 	email := "jane@example.com"
 	now := time.Now()
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "department", "created_at"}).
-		AddRow(int64(1), "Jane", email, "Science", now)
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "department_id", "department", "created_at"}).
+		AddRow(int64(1), "Jane", email, int64(3), "Science", now)
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, department, created_at FROM students WHERE email = ?")).
+	mock.ExpectQuery(regexp.QuoteMeta("FROM students s LEFT JOIN departments d ON d.id = s.department_id WHERE s.email = ?")).
 		WithArgs(email).
 		WillReturnRows(rows)
 
@@ -44,6 +44,8 @@ func TestGetStudentByEmailFound(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Equal(t, email, student.Email)
+	assert.Equal(t, int64(3), student.DepartmentID)
+	assert.Equal(t, "Science", student.Department)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -51,7 +53,7 @@ func TestGetStudentByEmailNotFound(t *testing.T) {
 	mock, _ := setupStudentSQLMock(t)
 	repo := &studentRepository{}
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, department, created_at FROM students WHERE email = ?")).
+	mock.ExpectQuery(regexp.QuoteMeta("FROM students s LEFT JOIN departments d ON d.id = s.department_id WHERE s.email = ?")).
 		WithArgs("ghost@example.com").
 		WillReturnError(sql.ErrNoRows)
 
@@ -66,11 +68,11 @@ func TestUpdateStudentNotFound(t *testing.T) {
 	mock, _ := setupStudentSQLMock(t)
 	repo := &studentRepository{}
 
-	input := model.Student{Name: "Jane", Email: "jane@example.com", Department: "Science"}
+	input := model.Student{Name: "Jane", Email: "jane@example.com", DepartmentID: 3}
 
-	prep := mock.ExpectPrepare(regexp.QuoteMeta("UPDATE students SET name = ?, email = ?, department = ? WHERE id = ?"))
+	prep := mock.ExpectPrepare(regexp.QuoteMeta("UPDATE students SET name = ?, email = ?, department_id = ? WHERE id = ?"))
 	prep.ExpectExec().
-		WithArgs(input.Name, input.Email, input.Department, int64(99)).
+		WithArgs(input.Name, input.Email, input.DepartmentID, int64(99)).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
 	err := repo.UpdateStudent(99, input)
@@ -79,6 +81,42 @@ func TestUpdateStudentNotFound(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetAllStudentsByDepartment(t *testing.T) {
+	mock, _ := setupStudentSQLMock(t)
+	repo := &studentRepository{}
+
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "department_id", "department", "created_at"}).
+		AddRow(int64(1), "Jane", "jane@example.com", int64(3), "Science", time.Now())
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE s.department_id IN (?, ?) ORDER BY s.id ASC LIMIT ? OFFSET ?")).
+		WithArgs(int64(3), int64(5), 10, 20).
+		WillReturnRows(rows)
+
+	students, err := repo.GetAllStudents([]int64{3, 5}, 10, 20)
+
+	assert.NoError(t, err)
+	assert.Len(t, students, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetStudentDepartments(t *testing.T) {
+	mock, _ := setupStudentSQLMock(t)
+	repo := &studentRepository{}
+
+	rows := sqlmock.NewRows([]string{"id", "department_id"}).
+		AddRow(int64(1), int64(3)).
+		AddRow(int64(9), int64(0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, COALESCE(department_id, 0) FROM students WHERE id IN (?, ?, ?)")).
+		WithArgs(int64(1), int64(9), int64(11)).
+		WillReturnRows(rows)
+
+	departments, err := repo.GetStudentDepartments([]int64{1, 9, 11})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int64{1: 3, 9: 0}, departments)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestDeleteStudentNotFound(t *testing.T) {
 	mock, _ := setupStudentSQLMock(t)
 	repo := &studentRepository{}
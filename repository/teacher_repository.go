@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	configuration "github.com/shravanasati/scopex-go-assignment/configuration"
+	model "github.com/shravanasati/scopex-go-assignment/model"
+)
+
+type TeacherRepository interface {
+	CreateTeacher(teacher model.Teacher) error
+	GetTeachers() (model.Teachers, error)
+	GetTeacherByUserID(userID int64) (model.Teacher, error)
+	UpdateTeacher(userID int64, teacher model.Teacher) error
+	DeleteTeacher(userID int64) error
+}
+type teacherRepository struct{}
+
+var TeacherRepo TeacherRepository = &teacherRepository{}
+
+// ErrTeacherNotFound indicates that the user has no teacher profile.
+var ErrTeacherNotFound = errors.New("teacher not found")
+
+// ErrDuplicateTeacher is returned when the user already has a teacher profile
+// or the email belongs to another teacher.
+var ErrDuplicateTeacher = errors.New("teacher profile or email already exists")
+
+// ErrUserNotFound indicates that the referenced login account does not exist.
+var ErrUserNotFound = errors.New("user not found")
+
+// CreateTeacher adds a teacher profile to an existing user together with its
+// departments
+func (r *teacherRepository) CreateTeacher(teacher model.Teacher) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO teachers (user_id, name, email) VALUES (?, ?, ?)", teacher.UserID, teacher.Name, teacher.Email)
+	if err != nil {
+		switch {
+		case isMySQLError(err, mysqlErrDuplicateEntry):
+			return ErrDuplicateTeacher
+		case isMySQLError(err, mysqlErrNoReferencedRow):
+			return ErrUserNotFound
+		}
+		log.Println("Error inserting teacher: " + err.Error())
+		return err
+	}
+
+	if err := insertTeacherDepartments(ctx, tx, teacher.UserID, teacher.DepartmentIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const teacherQuery = `
+	SELECT t.user_id, COALESCE(u.USER_NAME, ''), t.name, t.email, t.created_at
+	FROM teachers t
+	JOIN m_user u ON u.ID = t.user_id`
+
+// GetTeachers retrieves every teacher ordered by name
+func (r *teacherRepository) GetTeachers() (model.Teachers, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	teachers := model.Teachers{}
+
+	rows, err := db.QueryContext(ctx, teacherQuery+" ORDER BY t.name ASC, t.user_id ASC")
+	if err != nil {
+		log.Println("Error querying teachers: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t := model.Teacher{DepartmentIDs: []int64{}}
+		if err := rows.Scan(&t.UserID, &t.UserName, &t.Name, &t.Email, &t.CreatedAt); err != nil {
+			log.Println("Error scanning teacher: " + err.Error())
+			return nil, err
+		}
+		teachers = append(teachers, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	departments, err := teacherDepartments(ctx, "SELECT user_id, department_id FROM teacher_departments ORDER BY department_id ASC")
+	if err != nil {
+		return nil, err
+	}
+	for i := range teachers {
+		if ids, ok := departments[teachers[i].UserID]; ok {
+			teachers[i].DepartmentIDs = ids
+		}
+	}
+
+	return teachers, nil
+}
+
+// GetTeacherByUserID retrieves the teacher profile of a user and its
+// departments
+func (r *teacherRepository) GetTeacherByUserID(userID int64) (model.Teacher, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t := model.Teacher{DepartmentIDs: []int64{}}
+
+	err := db.QueryRowContext(ctx, teacherQuery+" WHERE t.user_id = ?", userID).Scan(&t.UserID, &t.UserName, &t.Name, &t.Email, &t.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return t, ErrTeacherNotFound
+		}
+		log.Println("Error querying teacher by user ID: " + err.Error())
+		return t, err
+	}
+
+	departments, err := teacherDepartments(ctx, "SELECT user_id, department_id FROM teacher_departments WHERE user_id = ? ORDER BY department_id ASC", userID)
+	if err != nil {
+		return t, err
+	}
+	if ids, ok := departments[userID]; ok {
+		t.DepartmentIDs = ids
+	}
+
+	return t, nil
+}
+
+// UpdateTeacher replaces the name, email and departments of a teacher
+func (r *teacherRepository) UpdateTeacher(userID int64, teacher model.Teacher) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists int64
+	err = tx.QueryRowContext(ctx, "SELECT user_id FROM teachers WHERE user_id = ? FOR UPDATE", userID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTeacherNotFound
+	}
+	if err != nil {
+		log.Println("Error querying teacher for update: " + err.Error())
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE teachers SET name = ?, email = ? WHERE user_id = ?", teacher.Name, teacher.Email, userID)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDuplicateEntry) {
+			return ErrDuplicateTeacher
+		}
+		log.Println("Error updating teacher: " + err.Error())
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM teacher_departments WHERE user_id = ?", userID); err != nil {
+		log.Println("Error deleting teacher departments: " + err.Error())
+		return err
+	}
+	if err := insertTeacherDepartments(ctx, tx, userID, teacher.DepartmentIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteTeacher removes the teacher profile of a user. The login account is
+// kept.
+func (r *teacherRepository) DeleteTeacher(userID int64) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := db.ExecContext(ctx, "DELETE FROM teachers WHERE user_id = ?", userID)
+	if err != nil {
+		log.Println("Error deleting teacher: " + err.Error())
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrTeacherNotFound
+	}
+
+	return nil
+}
+
+func insertTeacherDepartments(ctx context.Context, tx *sql.Tx, userID int64, departmentIDs []int64) error {
+	if len(departmentIDs) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, 2*len(departmentIDs))
+	for _, id := range departmentIDs {
+		args = append(args, userID, id)
+	}
+
+	query := "INSERT INTO teacher_departments (user_id, department_id) VALUES " + placeholderRows(len(departmentIDs), 2)
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		if isMySQLError(err, mysqlErrNoReferencedRow) {
+			return ErrDepartmentNotFound
+		}
+		log.Println("Error inserting teacher departments: " + err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// teacherDepartments groups the department IDs returned by query per teacher
+func teacherDepartments(ctx context.Context, query string, args ...any) (map[int64][]int64, error) {
+	db := configuration.DB
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error querying teacher departments: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	departments := make(map[int64][]int64)
+	for rows.Next() {
+		var userID, departmentID int64
+		if err := rows.Scan(&userID, &departmentID); err != nil {
+			return nil, err
+		}
+		departments[userID] = append(departments[userID], departmentID)
+	}
+
+	return departments, rows.Err()
+}
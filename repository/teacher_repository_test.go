@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTeacherUnknownUser(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &teacherRepository{}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO teachers (user_id, name, email) VALUES (?, ?, ?)")).
+		WithArgs(int64(99), "Budi", "budi@example.com").
+		WillReturnError(&mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"})
+	mock.ExpectRollback()
+
+	err := repo.CreateTeacher(model.Teacher{UserID: 99, Name: "Budi", Email: "budi@example.com", DepartmentIDs: []int64{3}})
+
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTeacherByUserIDLoadsDepartments(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	repo := &teacherRepository{}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM teachers t JOIN m_user u ON u.ID = t.user_id WHERE t.user_id = ?")).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "user_name", "name", "email", "created_at"}).
+			AddRow(int64(2), "budi", "Budi", "budi@example.com", time.Now()))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id, department_id FROM teacher_departments WHERE user_id = ?")).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "department_id"}).AddRow(int64(2), int64(3)).AddRow(int64(2), int64(4)))
+
+	teacher, err := repo.GetTeacherByUserID(2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "budi", teacher.UserName)
+	assert.Equal(t, []int64{3, 4}, teacher.DepartmentIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	configuration "github.com/shravanasati/scopex-go-assignment/configuration"
+	model "github.com/shravanasati/scopex-go-assignment/model"
+)
+
+type TimetableRepository interface {
+	CreateTimetableEntry(entry model.TimetableEntry) (int64, error)
+	GetTimetableEntries(sectionID int64) (model.TimetableEntries, error)
+	GetTimetableEntryByID(id int64) (model.TimetableEntry, error)
+	UpdateTimetableEntry(id int64, entry model.TimetableEntry) error
+	DeleteTimetableEntry(id int64) error
+}
+type timetableRepository struct{}
+
+var TimetableRepo TimetableRepository = &timetableRepository{}
+
+// ErrTimetableEntryNotFound indicates that the requested timetable entry does
+// not exist.
+var ErrTimetableEntryNotFound = errors.New("timetable entry not found")
+
+// CreateTimetableEntry inserts a new weekly slot
+func (r *timetableRepository) CreateTimetableEntry(entry model.TimetableEntry) (int64, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "INSERT INTO timetable_entries (section_id, weekday, start_time, end_time, title, room, teacher) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, entry.SectionID, entry.Weekday, entry.StartTime, entry.EndTime, entry.Title, entry.Room, entry.Teacher)
+	if err != nil {
+		if isMySQLError(err, mysqlErrNoReferencedRow) {
+			return 0, ErrSectionNotFound
+		}
+		log.Println("Error inserting timetable entry: " + err.Error())
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+const timetableEntryQuery = "SELECT id, section_id, weekday, TIME_FORMAT(start_time, '%H:%i'), TIME_FORMAT(end_time, '%H:%i'), title, room, teacher, created_at FROM timetable_entries"
+
+// GetTimetableEntries retrieves the timetable ordered by weekday and start
+// time, optionally only a section's
+func (r *timetableRepository) GetTimetableEntries(sectionID int64) (model.TimetableEntries, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	entries := model.TimetableEntries{}
+
+	query := timetableEntryQuery
+	var args []any
+	if sectionID != 0 {
+		query += " WHERE section_id = ?"
+		args = append(args, sectionID)
+	}
+	query += " ORDER BY weekday ASC, start_time ASC, section_id ASC"
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error querying timetable: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e model.TimetableEntry
+		if err := rows.Scan(&e.ID, &e.SectionID, &e.Weekday, &e.StartTime, &e.EndTime, &e.Title, &e.Room, &e.Teacher, &e.CreatedAt); err != nil {
+			log.Println("Error scanning timetable entry: " + err.Error())
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// GetTimetableEntryByID retrieves a timetable entry by ID
+func (r *timetableRepository) GetTimetableEntryByID(id int64) (model.TimetableEntry, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var e model.TimetableEntry
+
+	err := db.QueryRowContext(ctx, timetableEntryQuery+" WHERE id = ?", id).
+		Scan(&e.ID, &e.SectionID, &e.Weekday, &e.StartTime, &e.EndTime, &e.Title, &e.Room, &e.Teacher, &e.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return e, ErrTimetableEntryNotFound
+		}
+		log.Println("Error querying timetable entry by ID: " + err.Error())
+		return e, err
+	}
+
+	return e, nil
+}
+
+// UpdateTimetableEntry replaces a weekly slot. Sessions generated from it
+// before keep their times.
+func (r *timetableRepository) UpdateTimetableEntry(id int64, entry model.TimetableEntry) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "UPDATE timetable_entries SET section_id = ?, weekday = ?, start_time = ?, end_time = ?, title = ?, room = ?, teacher = ? WHERE id = ?"
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, entry.SectionID, entry.Weekday, entry.StartTime, entry.EndTime, entry.Title, entry.Room, entry.Teacher, id)
+	if err != nil {
+		if isMySQLError(err, mysqlErrNoReferencedRow) {
+			return ErrSectionNotFound
+		}
+		log.Println("Error updating timetable entry: " + err.Error())
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// nothing changed or nothing matched; tell the two apart
+		if _, err := r.GetTimetableEntryByID(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteTimetableEntry removes a weekly slot. Sessions generated from it
+// remain and lose the reference.
+func (r *timetableRepository) DeleteTimetableEntry(id int64) error {
+	return deleteByID("timetable_entries", id, ErrTimetableEntryNotFound)
+}
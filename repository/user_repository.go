@@ -5,14 +5,13 @@ import (
 	"errors"
 	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	configuration "github.com/shravanasati/scopex-go-assignment/configuration"
 	model "github.com/shravanasati/scopex-go-assignment/model"
 	util "github.com/shravanasati/scopex-go-assignment/util"
 
-	"golang.org/x/crypto/bcrypt"
-
 	// Use prefix blank identifier _ when importing driver for its side
 	// effect and not use it explicity anywhere in our code.
 	// When a package is imported prefixed with a blank identifier,the init
@@ -29,7 +28,7 @@ func GetUserByID(id int64) (model.MUser, error) {
 
 	var user model.MUser
 
-	result, err := db.QueryContext(ctx, "select id, user_name, password, account_expired, account_locked, credentials_expired, enabled from m_user where id = ?", id)
+	result, err := db.QueryContext(ctx, "select id, user_name, password, account_expired, account_locked, credentials_expired, enabled, coalesce(email, '') from m_user where id = ?", id)
 	if err != nil {
 		// print stack trace
 		log.Println("Error query user: " + err.Error())
@@ -37,7 +36,7 @@ func GetUserByID(id int64) (model.MUser, error) {
 	}
 
 	for result.Next() {
-		err := result.Scan(&user.ID, &user.UserName, &user.Password, &user.AccountExpired, &user.AccountLocked, &user.CredentialsExpired, &user.Enabled)
+		err := result.Scan(&user.ID, &user.UserName, &user.Password, &user.AccountExpired, &user.AccountLocked, &user.CredentialsExpired, &user.Enabled, &user.Email)
 		if err != nil {
 			return user, err
 		}
@@ -46,7 +45,18 @@ func GetUserByID(id int64) (model.MUser, error) {
 	return user, nil
 }
 
-// GetUserLogin ...
+// Login failures reported by GetUserLogin. An unknown username and a wrong
+// password are both ErrBadCredentials so usernames cannot be probed; the
+// account flags are only reported once the password is right.
+var (
+	ErrBadCredentials     = errors.New("bad credential")
+	ErrAccountDisabled    = errors.New("account is disabled")
+	ErrAccountLocked      = errors.New("account is locked")
+	ErrAccountExpired     = errors.New("account has expired")
+	ErrCredentialsExpired = errors.New("credentials have expired")
+)
+
+// GetUserLogin checks the password of a user and the flags of their account
 func GetUserLogin(username string, password string) (model.MUser, error) {
 
 	var mUser model.MUser
@@ -59,15 +69,31 @@ func GetUserLogin(username string, password string) (model.MUser, error) {
 	}
 
 	if (model.MUser{} == mUser) {
-		return mUser, errors.New("bad credential")
+		return mUser, ErrBadCredentials
 	}
 
 	var retVal bool = util.CheckPasswordHash(password, mUser.Password)
 	if !retVal {
-		return mUser, errors.New("wrong password")
+		return mUser, ErrBadCredentials
 	}
 
-	return mUser, nil
+	return mUser, CheckAccountFlags(mUser)
+}
+
+// CheckAccountFlags reports the first account flag that forbids the user to
+// log in or refresh their tokens, or nil
+func CheckAccountFlags(mUser model.MUser) error {
+	switch {
+	case !mUser.Enabled:
+		return ErrAccountDisabled
+	case mUser.AccountLocked:
+		return ErrAccountLocked
+	case mUser.AccountExpired:
+		return ErrAccountExpired
+	case mUser.CredentialsExpired:
+		return ErrCredentialsExpired
+	}
+	return nil
 }
 
 // GetUserByUsername ...
@@ -77,13 +103,13 @@ func GetUserByUsername(username string) (model.MUser, error) {
 	defer cancel()
 
 	var mUser model.MUser
-	result, err := db.QueryContext(ctx, "select id, user_name, password, account_expired, account_locked, credentials_expired, enabled from m_user where user_name = ?", username)
+	result, err := db.QueryContext(ctx, "select id, user_name, password, account_expired, account_locked, credentials_expired, enabled, coalesce(email, '') from m_user where user_name = ?", username)
 	if err != nil {
 		return mUser, err
 	}
 
 	for result.Next() {
-		err := result.Scan(&mUser.ID, &mUser.UserName, &mUser.Password, &mUser.AccountExpired, &mUser.AccountLocked, &mUser.CredentialsExpired, &mUser.Enabled)
+		err := result.Scan(&mUser.ID, &mUser.UserName, &mUser.Password, &mUser.AccountExpired, &mUser.AccountLocked, &mUser.CredentialsExpired, &mUser.Enabled, &mUser.Email)
 		if err != nil {
 			return mUser, err
 		}
@@ -92,102 +118,200 @@ func GetUserByUsername(username string) (model.MUser, error) {
 	return mUser, nil
 }
 
-// GetUserAll ...
-func GetUserAll() ([]model.MUser, error) {
+// GetUserByEmail finds the user an email address belongs to. Like
+// GetUserByUsername it returns an empty user when there is none.
+func GetUserByEmail(email string) (model.MUser, error) {
 	db := configuration.DB
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	var mUser model.MUser
-	var mUsers []model.MUser
+	result, err := db.QueryContext(ctx, "select id, user_name, password, account_expired, account_locked, credentials_expired, enabled, coalesce(email, '') from m_user where email = ?", email)
+	if err != nil {
+		log.Println("Error query user: " + err.Error())
+		return mUser, err
+	}
+	defer result.Close()
+
+	for result.Next() {
+		err := result.Scan(&mUser.ID, &mUser.UserName, &mUser.Password, &mUser.AccountExpired, &mUser.AccountLocked, &mUser.CredentialsExpired, &mUser.Enabled, &mUser.Email)
+		if err != nil {
+			return mUser, err
+		}
+	}
+
+	return mUser, nil
+}
+
+// emails are unique when set, so a user without one stores NULL
+func nullableEmail(email string) any {
+	if email == "" {
+		return nil
+	}
+	return email
+}
+
+// ErrUserExists is returned when the user name or email of a user is already
+// taken by another
+var ErrUserExists = errors.New("user name or email is already taken")
+
+// GetUserPage retrieves one page of users ordered by ID, optionally only those
+// whose user name or email contains search, and the number of matches
+func GetUserPage(search string, limit, offset int) ([]model.MUser, int, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var where string
+	var args []any
+	if search != "" {
+		pattern := "%" + escapeLike(search) + "%"
+		where = " where user_name like ? or email like ?"
+		args = append(args, pattern, pattern)
+	}
+
+	var total int
+	if err := db.QueryRowContext(ctx, "select count(*) from m_user"+where, args...).Scan(&total); err != nil {
+		log.Println("Error counting users: " + err.Error())
+		return nil, 0, err
+	}
 
-	rows, err := db.QueryContext(ctx, "select id, user_name, password, account_expired, account_locked, credentials_expired, enabled from m_user")
+	mUsers := []model.MUser{}
+
+	query := "select id, user_name, password, account_expired, account_locked, credentials_expired, enabled, coalesce(email, '') from m_user" + where + " order by id asc limit ? offset ?"
+	rows, err := db.QueryContext(ctx, query, append(args, limit, offset)...)
 	if err != nil {
 		log.Println("Error query user: " + err.Error())
-		return mUsers, err
+		return nil, 0, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
+		var mUser model.MUser
 		if err := rows.Scan(&mUser.ID, &mUser.UserName, &mUser.Password, &mUser.AccountExpired,
-			&mUser.AccountLocked, &mUser.CredentialsExpired, &mUser.Enabled); err != nil {
-			return mUsers, err
+			&mUser.AccountLocked, &mUser.CredentialsExpired, &mUser.Enabled, &mUser.Email); err != nil {
+			return nil, 0, err
 		}
 		mUsers = append(mUsers, mUser)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return mUsers, total, nil
+}
 
-	return mUsers, nil
+// escapeLike makes the LIKE wildcards in s match themselves
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
-// CreateUser ...
+// CreateUser inserts a user. mUser.Password must already be hashed.
 func CreateUser(mUser model.MUser) (model.MUser, error) {
 	db := configuration.DB
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var err error
-
-	hash, _ := util.HashPassword(mUser.Password, bcrypt.DefaultCost)
-	mUser.Password = hash
-
-	crt, err := db.PrepareContext(ctx, "insert into m_user (user_name, password, account_expired, account_locked, credentials_expired, enabled) values (?, ?, ?, ?, ?, ?)")
+	res, err := db.ExecContext(ctx, "insert into m_user (user_name, password, account_expired, account_locked, credentials_expired, enabled, email) values (?, ?, ?, ?, ?, ?, ?)",
+		mUser.UserName, mUser.Password, mUser.AccountExpired,
+		mUser.AccountLocked, mUser.CredentialsExpired, mUser.Enabled, nullableEmail(mUser.Email))
 	if err != nil {
-		log.Panic(err)
+		if isMySQLError(err, mysqlErrDuplicateEntry) {
+			return mUser, ErrUserExists
+		}
+		log.Println("Error inserting user: " + err.Error())
 		return mUser, err
 	}
 
-	res, err := crt.ExecContext(ctx, mUser.UserName, mUser.Password, mUser.AccountExpired,
-		mUser.AccountLocked, mUser.CredentialsExpired, mUser.Enabled)
+	rowID, err := res.LastInsertId()
 	if err != nil {
-		log.Panic(err)
 		return mUser, err
 	}
 
-	rowID, err := res.LastInsertId()
+	// find user by id
+	return GetUserByID(rowID)
+}
+
+// UpdateUser writes every field of an existing user. mUser.Password must
+// already be hashed.
+func UpdateUser(mUser model.MUser) (model.MUser, error) {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := db.ExecContext(ctx, "update m_user set user_name = ?, password = ?, account_expired = ?, account_locked = ?, credentials_expired = ?, enabled = ?, email = ? where id = ?",
+		mUser.UserName, mUser.Password, mUser.AccountExpired,
+		mUser.AccountLocked, mUser.CredentialsExpired, mUser.Enabled, nullableEmail(mUser.Email), mUser.ID)
 	if err != nil {
-		log.Panic(err)
+		if isMySQLError(err, mysqlErrDuplicateEntry) {
+			return mUser, ErrUserExists
+		}
+		log.Println("Error updating user: " + err.Error())
 		return mUser, err
 	}
 
-	mUser.ID = int64(rowID)
-
 	// find user by id
-	resval, err := GetUserByID(mUser.ID)
+	res, err := GetUserByID(mUser.ID)
 	if err != nil {
-		log.Panic(err)
 		return mUser, err
 	}
+	if res.ID == 0 {
+		return mUser, ErrUserNotFound
+	}
 
-	return resval, nil
+	return res, nil
 }
 
-// UpdateUser ...
-func UpdateUser(mUser model.MUser) (model.MUser, error) {
+// UnlockUser clears the locked flag of a user
+func UnlockUser(id int64) error {
 	db := configuration.DB
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var err error
-
-	hash, _ := util.HashPassword(mUser.Password, bcrypt.DefaultCost)
-	mUser.Password = hash
-
-	crt, err := db.PrepareContext(ctx, "update m_user set user_name =?, password =?, account_expired =?, account_locked =?, credentials_expired =?, enabled =? where id=?")
+	result, err := db.ExecContext(ctx, "update m_user set account_locked = 0 where id = ?", id)
 	if err != nil {
-		return mUser, err
+		log.Println("Error unlocking user: " + err.Error())
+		return err
 	}
-	_, queryError := crt.ExecContext(ctx, mUser.ID, mUser.UserName, mUser.Password, mUser.AccountExpired,
-		mUser.AccountLocked, mUser.CredentialsExpired, mUser.Enabled)
-	if queryError != nil {
-		return mUser, err
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// already unlocked or no such user; tell the two apart
+		user, err := GetUserByID(id)
+		if err != nil {
+			return err
+		}
+		if user.ID == 0 {
+			return ErrUserNotFound
+		}
 	}
 
-	// find user by id
-	res, err := GetUserByID(mUser.ID)
+	return nil
+}
+
+// SetUserPassword stores a new password hash for a user and clears their
+// credentials expired flag
+func SetUserPassword(id int64, hash string) error {
+	db := configuration.DB
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := db.ExecContext(ctx, "update m_user set password = ?, credentials_expired = 0 where id = ?", hash, id)
 	if err != nil {
-		return mUser, err
+		log.Println("Error updating password: " + err.Error())
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrUserNotFound
 	}
 
-	return res, nil
+	return nil
 }
 
 // DeleteUserByID ...
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var userColumns = []string{"id", "user_name", "password", "account_expired", "account_locked", "credentials_expired", "enabled", "email"}
+
+func TestGetUserLoginChecksAccountFlags(t *testing.T) {
+	hash, _ := util.HashPassword("secret123", bcrypt.MinCost)
+	cases := []struct {
+		name                                  string
+		password                              string
+		expired, locked, credExpired, enabled bool
+		want                                  error
+	}{
+		{"wrong password is checked before the flags", "nope", false, true, false, false, ErrBadCredentials},
+		{"disabled", "secret123", false, false, false, false, ErrAccountDisabled},
+		{"locked", "secret123", false, true, false, true, ErrAccountLocked},
+		{"expired", "secret123", true, false, false, true, ErrAccountExpired},
+		{"credentials expired", "secret123", false, false, true, true, ErrCredentialsExpired},
+		{"active", "secret123", false, false, false, true, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock, _ := setupAttendanceSQLMock(t)
+			mock.ExpectQuery(regexp.QuoteMeta("from m_user where user_name = ?")).
+				WithArgs("budi").
+				WillReturnRows(sqlmock.NewRows(userColumns).
+					AddRow(int64(2), "budi", hash, tc.expired, tc.locked, tc.credExpired, tc.enabled, "budi@scopex.local"))
+
+			_, err := GetUserLogin("budi", tc.password)
+
+			if tc.want == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetUserLoginUnknownUser(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	mock.ExpectQuery(regexp.QuoteMeta("from m_user where user_name = ?")).
+		WithArgs("ghost").
+		WillReturnRows(sqlmock.NewRows(userColumns))
+
+	_, err := GetUserLogin("ghost", "secret123")
+
+	assert.ErrorIs(t, err, ErrBadCredentials)
+}
+
+func TestUnlockUserNotFound(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	mock.ExpectExec(regexp.QuoteMeta("update m_user set account_locked = 0 where id = ?")).
+		WithArgs(int64(99)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("from m_user where id = ?")).
+		WithArgs(int64(99)).
+		WillReturnRows(sqlmock.NewRows(userColumns))
+
+	err := UnlockUser(99)
+
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUserPageSearch(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	mock.ExpectQuery(regexp.QuoteMeta("select count(*) from m_user where user_name like ? or email like ?")).
+		WithArgs(`%bu\_di%`, `%bu\_di%`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("from m_user where user_name like ? or email like ? order by id asc limit ? offset ?")).
+		WithArgs(`%bu\_di%`, `%bu\_di%`, 10, 20).
+		WillReturnRows(sqlmock.NewRows(userColumns).
+			AddRow(int64(2), "bu_di", "hash", false, false, false, true, ""))
+
+	users, total, err := GetUserPage("bu_di", 10, 20)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, users, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateUserWritesEveryColumn(t *testing.T) {
+	mock, _ := setupAttendanceSQLMock(t)
+	mock.ExpectExec(regexp.QuoteMeta("update m_user set user_name = ?, password = ?, account_expired = ?, account_locked = ?, credentials_expired = ?, enabled = ?, email = ? where id = ?")).
+		WithArgs("budi", "hash", false, true, false, true, nil, int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("from m_user where id = ?")).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows(userColumns).
+			AddRow(int64(2), "budi", "hash", false, true, false, true, ""))
+
+	user, err := UpdateUser(model.MUser{ID: 2, UserName: "budi", Password: "hash", AccountLocked: true, Enabled: true})
+
+	assert.NoError(t, err)
+	assert.True(t, user.AccountLocked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
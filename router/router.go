@@ -14,10 +14,24 @@ func NewRoutes() *gin.Engine {
 
 	// register router from each controller service
 	service.RoutesLoginLogout(v1)
+	service.RoutesMe(v1)
+	service.RoutesLoginSession(v1)
+	service.RoutesPassword(v1)
 	service.RoutesUser(v1)
+	service.RoutesRole(v1)
+	service.RoutesTeacher(v1)
 
 	service.RoutesStudent(v1)
 	service.RoutesAttendance(v1)
+	service.RoutesAttendanceStatus(v1)
+	service.RoutesSession(v1)
+	service.RoutesCalendar(v1)
+	service.RoutesLeave(v1)
+	service.RoutesAlert(v1)
+	service.RoutesLocation(v1)
+	service.RoutesDepartment(v1)
+	service.RoutesCourse(v1)
+	service.RoutesTimetable(v1)
 
 	return router
 }
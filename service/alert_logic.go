@@ -0,0 +1,202 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/spf13/viper"
+)
+
+// Defaults used when ALERTS.THRESHOLD_PERCENT or ALERTS.WINDOW_DAYS is unset
+const (
+	defaultAlertThresholdPercent = 75.0
+	defaultAlertWindowDays       = 30
+)
+
+// alertRule flags students whose attendance over the trailing WindowDays is
+// below ThresholdPercent
+type alertRule struct {
+	ThresholdPercent float64
+	WindowDays       int
+}
+
+func currentAlertRule() alertRule {
+	rule := alertRule{ThresholdPercent: defaultAlertThresholdPercent, WindowDays: defaultAlertWindowDays}
+	if viper.IsSet("ALERTS.THRESHOLD_PERCENT") {
+		rule.ThresholdPercent = viper.GetFloat64("ALERTS.THRESHOLD_PERCENT")
+	}
+	if viper.IsSet("ALERTS.WINDOW_DAYS") {
+		rule.WindowDays = viper.GetInt("ALERTS.WINDOW_DAYS")
+	}
+	return rule
+}
+
+// window returns the trailing date range ending on today
+func (r alertRule) window(today time.Time) (string, string) {
+	return today.AddDate(0, 0, -(r.WindowDays - 1)).Format(isoDateLayout), today.Format(isoDateLayout)
+}
+
+// AlertService describes the low-attendance alert operations the HTTP layer,
+// attendance writes and the cron job rely on.
+type AlertService interface {
+	EvaluateStudent(studentID int64) error
+	EvaluateAll() error
+	GetAlerts(filter model.AlertFilter, scope departmentScope, limit, offset int) (model.AttendanceAlerts, error)
+}
+
+type alertService struct {
+	repo     repository.AlertRepository
+	students repository.StudentRepository
+	reports  func(startDate, endDate string) (model.AttendanceReports, error)
+	notify   func(alert model.AttendanceAlert, student model.Student)
+	now      func() time.Time
+}
+
+var alertSvc AlertService = newAlertService(repository.AlertRepo, repository.StudentRepo)
+
+func newAlertService(repo repository.AlertRepository, students repository.StudentRepository) *alertService {
+	return &alertService{
+		repo:     repo,
+		students: students,
+		reports:  repository.GetAttendanceReport,
+		notify:   util.SendAlertEmail,
+		now:      time.Now,
+	}
+}
+
+// EvaluateStudent applies the alert rule to one student's trailing window
+func (s *alertService) EvaluateStudent(studentID int64) error {
+	rule := currentAlertRule()
+	start, end := rule.window(s.now())
+
+	present, absent, err := s.repo.GetStudentAttendanceCounts(studentID, start, end)
+	if err != nil {
+		return err
+	}
+
+	return s.evaluate(rule, model.AttendanceAlert{
+		StudentID:    studentID,
+		WindowStart:  start,
+		WindowEnd:    end,
+		PresentCount: present,
+		AbsentCount:  absent,
+	}, nil)
+}
+
+// EvaluateAll applies the alert rule to every student, reusing the
+// attendance report aggregation
+func (s *alertService) EvaluateAll() error {
+	rule := currentAlertRule()
+	start, end := rule.window(s.now())
+
+	reports, err := s.reports(start, end)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, report := range reports {
+		student := model.Student{ID: report.StudentID, Name: report.StudentName, Email: report.StudentEmail}
+		err := s.evaluate(rule, model.AttendanceAlert{
+			StudentID:    report.StudentID,
+			WindowStart:  start,
+			WindowEnd:    end,
+			PresentCount: report.PresentCount,
+			AbsentCount:  report.AbsentCount,
+		}, &student)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// GetAlerts lists the alerts of the students in scope
+func (s *alertService) GetAlerts(filter model.AlertFilter, scope departmentScope, limit, offset int) (model.AttendanceAlerts, error) {
+	if scope.restricted && len(scope.departments) == 0 {
+		return model.AttendanceAlerts{}, nil
+	}
+	filter.DepartmentIDs = scope.departments
+	return s.repo.GetAlerts(filter, limit, offset)
+}
+
+// evaluate opens an alert when the student is below the threshold and has
+// none open, refreshes an open one while they stay below, and resolves it
+// once they recover. Students without records in the window are skipped.
+func (s *alertService) evaluate(rule alertRule, candidate model.AttendanceAlert, student *model.Student) error {
+	if candidate.PresentCount+candidate.AbsentCount == 0 {
+		return nil
+	}
+	candidate.Percentage = attendancePercentage(candidate.PresentCount, candidate.AbsentCount)
+	candidate.Threshold = rule.ThresholdPercent
+	below := candidate.Percentage < rule.ThresholdPercent
+
+	open, err := s.repo.GetOpenAlert(candidate.StudentID)
+	if err != nil && !errors.Is(err, repository.ErrAlertNotFound) {
+		return err
+	}
+	hasOpen := err == nil
+
+	switch {
+	case below && hasOpen:
+		return s.repo.RefreshAlert(open.ID, candidate)
+	case below:
+		id, err := s.repo.CreateAlert(candidate)
+		if err != nil {
+			return err
+		}
+		candidate.ID = id
+		candidate.CreatedAt = s.now()
+
+		if student == nil {
+			found, err := s.students.GetStudentByID(candidate.StudentID)
+			if err != nil {
+				return err
+			}
+			student = &found
+		}
+		s.notify(candidate, *student)
+		return nil
+	case hasOpen:
+		return s.repo.ResolveAlert(open.ID)
+	}
+
+	return nil
+}
+
+// evaluateAlertsAfterWrite re-evaluates the alert rule for the students whose
+// attendance just changed. It runs in the background so a slow evaluation
+// never delays the write.
+func evaluateAlertsAfterWrite(studentIDs ...int64) {
+	go func() {
+		seen := make(map[int64]bool, len(studentIDs))
+		for _, id := range studentIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if err := alertSvc.EvaluateStudent(id); err != nil {
+				log.Println("Error evaluating attendance alert: " + err.Error())
+			}
+		}
+	}()
+}
+
+// EvaluateAttendanceAlerts applies the low-attendance rule to every student.
+// It is run by the cron scheduler.
+func EvaluateAttendanceAlerts() {
+	log.Println("Starting Attendance Alert Evaluation...")
+
+	if err := alertSvc.EvaluateAll(); err != nil {
+		log.Println("Error evaluating attendance alerts: ", err)
+		return
+	}
+
+	log.Println("Attendance Alert Evaluation Completed.")
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockAlertRepository struct {
+	mock.Mock
+}
+
+func (m *mockAlertRepository) GetStudentAttendanceCounts(studentID int64, startDate, endDate string) (int, int, error) {
+	args := m.Called(studentID, startDate, endDate)
+	return args.Int(0), args.Int(1), args.Error(2)
+}
+
+func (m *mockAlertRepository) GetOpenAlert(studentID int64) (model.AttendanceAlert, error) {
+	args := m.Called(studentID)
+	return args.Get(0).(model.AttendanceAlert), args.Error(1)
+}
+
+func (m *mockAlertRepository) CreateAlert(alert model.AttendanceAlert) (int64, error) {
+	args := m.Called(alert)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockAlertRepository) RefreshAlert(id int64, alert model.AttendanceAlert) error {
+	return m.Called(id, alert).Error(0)
+}
+
+func (m *mockAlertRepository) ResolveAlert(id int64) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *mockAlertRepository) GetAlerts(filter model.AlertFilter, limit, offset int) (model.AttendanceAlerts, error) {
+	args := m.Called(filter, limit, offset)
+	alerts, _ := args.Get(0).(model.AttendanceAlerts)
+	return alerts, args.Error(1)
+}
+
+func newTestAlertService(repo *mockAlertRepository, students *mockStudentRepository, notified *[]model.AttendanceAlert) *alertService {
+	svc := newAlertService(repo, students)
+	svc.now = func() time.Time { return time.Date(2023, 10, 30, 8, 0, 0, 0, time.UTC) }
+	svc.notify = func(alert model.AttendanceAlert, _ model.Student) {
+		*notified = append(*notified, alert)
+	}
+	return svc
+}
+
+func TestAlertServiceOpensAlertBelowThreshold(t *testing.T) {
+	repo := &mockAlertRepository{}
+	students := &mockStudentRepository{}
+	var notified []model.AttendanceAlert
+	svc := newTestAlertService(repo, students, &notified)
+
+	repo.On("GetStudentAttendanceCounts", int64(1), "2023-10-01", "2023-10-30").Return(7, 3, nil)
+	repo.On("GetOpenAlert", int64(1)).Return(model.AttendanceAlert{}, repository.ErrAlertNotFound)
+	repo.On("CreateAlert", mock.MatchedBy(func(a model.AttendanceAlert) bool {
+		return a.Percentage == 70 && a.Threshold == 75 && a.WindowStart == "2023-10-01"
+	})).Return(int64(5), nil)
+	students.On("GetStudentByID", int64(1)).Return(model.Student{ID: 1, Name: "Asha"}, nil)
+
+	err := svc.EvaluateStudent(1)
+
+	assert.NoError(t, err)
+	assert.Len(t, notified, 1)
+	assert.Equal(t, int64(5), notified[0].ID)
+	repo.AssertExpectations(t)
+}
+
+func TestAlertServiceRefreshesOpenAlert(t *testing.T) {
+	repo := &mockAlertRepository{}
+	var notified []model.AttendanceAlert
+	svc := newTestAlertService(repo, &mockStudentRepository{}, &notified)
+
+	repo.On("GetStudentAttendanceCounts", int64(1), mock.Anything, mock.Anything).Return(6, 4, nil)
+	repo.On("GetOpenAlert", int64(1)).Return(model.AttendanceAlert{ID: 5}, nil)
+	repo.On("RefreshAlert", int64(5), mock.AnythingOfType("model.AttendanceAlert")).Return(nil)
+
+	assert.NoError(t, svc.EvaluateStudent(1))
+	assert.Empty(t, notified)
+	repo.AssertExpectations(t)
+}
+
+func TestAlertServiceResolvesRecoveredStudent(t *testing.T) {
+	repo := &mockAlertRepository{}
+	var notified []model.AttendanceAlert
+	svc := newTestAlertService(repo, &mockStudentRepository{}, &notified)
+
+	repo.On("GetStudentAttendanceCounts", int64(1), mock.Anything, mock.Anything).Return(9, 1, nil)
+	repo.On("GetOpenAlert", int64(1)).Return(model.AttendanceAlert{ID: 5}, nil)
+	repo.On("ResolveAlert", int64(5)).Return(nil)
+
+	assert.NoError(t, svc.EvaluateStudent(1))
+	repo.AssertExpectations(t)
+}
+
+func TestAlertServiceEvaluateAllSkipsStudentsWithoutRecords(t *testing.T) {
+	repo := &mockAlertRepository{}
+	var notified []model.AttendanceAlert
+	svc := newTestAlertService(repo, &mockStudentRepository{}, &notified)
+	svc.reports = func(startDate, endDate string) (model.AttendanceReports, error) {
+		return model.AttendanceReports{
+			{StudentID: 1, StudentName: "Asha", PresentCount: 1, AbsentCount: 3},
+			{StudentID: 2, StudentName: "Ben"},
+		}, nil
+	}
+
+	repo.On("GetOpenAlert", int64(1)).Return(model.AttendanceAlert{}, repository.ErrAlertNotFound)
+	repo.On("CreateAlert", mock.AnythingOfType("model.AttendanceAlert")).Return(int64(6), nil)
+
+	assert.NoError(t, svc.EvaluateAll())
+	assert.Len(t, notified, 1)
+	repo.AssertNotCalled(t, "GetOpenAlert", int64(2))
+}
+
+func TestAlertServiceGetAlertsFollowsScope(t *testing.T) {
+	repo := &mockAlertRepository{}
+	var notified []model.AttendanceAlert
+	svc := newTestAlertService(repo, &mockStudentRepository{}, &notified)
+
+	repo.On("GetAlerts", model.AlertFilter{StudentID: 1, DepartmentIDs: []int64{3, 4}}, 10, 0).Return(model.AttendanceAlerts{}, nil).Once()
+
+	_, err := svc.GetAlerts(model.AlertFilter{StudentID: 1}, departmentScope{restricted: true, departments: []int64{3, 4}}, 10, 0)
+	assert.NoError(t, err)
+
+	// a teacher without departments sees nothing
+	alerts, err := svc.GetAlerts(model.AlertFilter{}, departmentScope{restricted: true}, 10, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, alerts)
+	repo.AssertExpectations(t)
+}
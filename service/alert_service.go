@@ -0,0 +1,68 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutesAlert registers the attendance alert routes
+func RoutesAlert(rg *gin.RouterGroup) {
+	alert := rg.Group("/alerts")
+
+	alert.GET("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAlertsRead), getAlerts)
+}
+
+// getAlerts godoc
+// @Summary List low-attendance alerts
+// @Description Get a page of alerts raised for students below the ALERTS.THRESHOLD_PERCENT attendance over the trailing ALERTS.WINDOW_DAYS, newest first. Teachers only see the alerts of students of their own departments.
+// @Tags Alerts
+// @Accept  json
+// @Produce  json
+// @Param student_id query int false "Student ID"
+// @Param open query bool false "Only open (true) or resolved (false) alerts"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size, at most 100" default(10) maximum(100)
+// @Success 200 {array} model.AttendanceAlert
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /alerts/ [get]
+func getAlerts(c *gin.Context) {
+	var filter model.AlertFilter
+	if studentIDStr := c.Query("student_id"); studentIDStr != "" {
+		studentID, err := strconv.ParseInt(studentIDStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Student ID"})
+			return
+		}
+		filter.StudentID = studentID
+	}
+	if openStr := c.Query("open"); openStr != "" {
+		open, err := strconv.ParseBool(openStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "open must be true or false"})
+			return
+		}
+		filter.Open = &open
+	}
+
+	scope, ok := callerScope(c)
+	if !ok {
+		return
+	}
+	_, limit, offset := pagination(c)
+
+	alerts, err := alertSvc.GetAlerts(filter, scope, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch alerts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, alerts)
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"errors"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+)
+
+// Conflict modes accepted by markAttendance through ?on_conflict=
+const (
+	conflictReject = "reject"
+	conflictIgnore = "ignore"
+	conflictUpdate = "update"
+)
+
+// ErrInvalidConflictMode is returned for an unknown on_conflict value.
+var ErrInvalidConflictMode = errors.New("on_conflict must be one of update, ignore, reject")
+
+func validateConflictMode(mode string) error {
+	switch mode {
+	case conflictReject, conflictIgnore, conflictUpdate:
+		return nil
+	}
+	return ErrInvalidConflictMode
+}
+
+// recordAttendance stores a mark according to the conflict mode when the
+// student already has attendance for that date and session: reject fails with
+// repository.ErrDuplicateAttendance, ignore returns the stored record
+// untouched and update overwrites its status. A non-nil flag is written with
+// the mark, so a mark is never stored as Flagged without its review. The
+// returned bool reports whether a new record was created.
+func recordAttendance(attendance model.Attendance, flag *model.AttendanceFlag, mode string, changedBy int64) (model.Attendance, bool, error) {
+	if err := validateConflictMode(mode); err != nil {
+		return model.Attendance{}, false, err
+	}
+	overridden, err := lockSvc.Authorize(attendance.Date, changedBy)
+	if err != nil {
+		return model.Attendance{}, false, err
+	}
+	if err := calendarSvc.CheckWorkingDay(attendance.Date); err != nil {
+		return model.Attendance{}, false, err
+	}
+	session, err := validateAttendanceSession(attendance.SessionID, attendance.Date)
+	if err != nil {
+		return model.Attendance{}, false, err
+	}
+	if session.SectionID != 0 {
+		if err := courseSvc.CheckEnrolled(session.SectionID, attendance.StudentID); err != nil {
+			return model.Attendance{}, false, err
+		}
+	}
+	if err := checkNotOnLeave(attendance.StudentID, attendance.Date); err != nil {
+		return model.Attendance{}, false, err
+	}
+
+	if mode == conflictUpdate {
+		id, created, err := repository.UpsertAttendance(attendance, flag, changedBy)
+		if err != nil {
+			return model.Attendance{}, false, err
+		}
+		attendance.ID = id
+		if overridden {
+			lockSvc.RecordOverride(model.LockActionMark, attendance, changedBy)
+		}
+		return attendance, created, nil
+	}
+
+	id, err := repository.MarkAttendance(attendance, flag)
+	if errors.Is(err, repository.ErrDuplicateAttendance) && mode == conflictIgnore {
+		existing, err := repository.GetAttendanceByStudentAndDate(attendance.StudentID, attendance.Date, attendance.SessionID)
+		return existing, false, err
+	}
+	if err != nil {
+		return model.Attendance{}, false, err
+	}
+
+	attendance.ID = id
+	if overridden {
+		lockSvc.RecordOverride(model.LockActionMark, attendance, changedBy)
+	}
+	return attendance, true, nil
+}
+
+// markAttendanceBulk records a bulk request, reporting entries that
+// contradict approved leave as on_leave, and entries for students outside the
+// session's section (sectionID, 0 for none) as not_enrolled, instead of
+// storing them. Results keep the order of the request entries.
+func markAttendanceBulk(req model.BulkAttendanceRequest, sectionID int64, changedBy int64) ([]model.BulkAttendanceResult, error) {
+	overridden, err := lockSvc.Authorize(req.Date, changedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	studentIDs := make([]int64, 0, len(req.Entries))
+	for _, e := range req.Entries {
+		studentIDs = append(studentIDs, e.StudentID)
+	}
+	onLeave, err := leaveSvc.StudentsOnLeave(req.Date, studentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	enrolled := make(map[int64]bool)
+	if sectionID != 0 {
+		enrolled, err = courseSvc.EnrolledStudents(sectionID, studentIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rejection := func(e model.BulkAttendanceEntry) string {
+		switch {
+		case sectionID != 0 && !enrolled[e.StudentID]:
+			return model.BulkResultNotEnrolled
+		case onLeave[e.StudentID]:
+			return model.BulkResultOnLeave
+		}
+		return ""
+	}
+
+	var toMark []model.BulkAttendanceEntry
+	for _, e := range req.Entries {
+		if rejection(e) == "" {
+			toMark = append(toMark, e)
+		}
+	}
+
+	var marked []model.BulkAttendanceResult
+	if len(toMark) > 0 {
+		marked, err = repository.MarkAttendanceBulk(req.Date, req.SessionID, toMark)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]model.BulkAttendanceResult, 0, len(req.Entries))
+	for _, e := range req.Entries {
+		if result := rejection(e); result != "" {
+			results = append(results, model.BulkAttendanceResult{StudentID: e.StudentID, Status: e.Status, Result: result})
+			continue
+		}
+		results = append(results, marked[0])
+		marked = marked[1:]
+	}
+
+	if overridden {
+		for _, r := range results {
+			if r.Result == model.BulkResultCreated {
+				lockSvc.RecordOverride(model.LockActionMark, model.Attendance{
+					ID: r.ID, StudentID: r.StudentID, Date: req.Date, SessionID: req.SessionID, Status: r.Status,
+				}, changedBy)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// correctAttendance changes the status of a record, subject to the
+// attendance lock.
+func correctAttendance(id int64, status string, changedBy int64) (model.Attendance, error) {
+	current, err := repository.GetAttendanceByID(id)
+	if err != nil {
+		return model.Attendance{}, err
+	}
+	overridden, err := lockSvc.Authorize(recordDay(current), changedBy)
+	if err != nil {
+		return model.Attendance{}, err
+	}
+
+	updated, err := repository.UpdateAttendanceStatus(id, status, changedBy)
+	if err != nil {
+		return model.Attendance{}, err
+	}
+
+	if overridden {
+		lockSvc.RecordOverride(model.LockActionUpdate, updated, changedBy)
+	}
+	return updated, nil
+}
+
+// removeAttendance deletes a record, subject to the attendance lock.
+func removeAttendance(id int64, changedBy int64) (model.Attendance, error) {
+	current, err := repository.GetAttendanceByID(id)
+	if err != nil {
+		return model.Attendance{}, err
+	}
+	overridden, err := lockSvc.Authorize(recordDay(current), changedBy)
+	if err != nil {
+		return model.Attendance{}, err
+	}
+
+	deleted, err := repository.DeleteAttendance(id, changedBy)
+	if err != nil {
+		return model.Attendance{}, err
+	}
+
+	if overridden {
+		lockSvc.RecordOverride(model.LockActionDelete, deleted, changedBy)
+	}
+	return deleted, nil
+}
+
+// validateAttendanceFilter checks the optional date bounds and status of an
+// attendance listing.
+func validateAttendanceFilter(filter model.AttendanceFilter) error {
+	issues := make(map[string]string)
+
+	if filter.From != "" {
+		if err := validateISODate(filter.From); err != nil {
+			issues["from"] = err.Error()
+		}
+	}
+	if filter.To != "" {
+		if err := validateISODate(filter.To); err != nil {
+			issues["to"] = err.Error()
+		}
+	}
+	if len(issues) == 0 && filter.From != "" && filter.To != "" && filter.From > filter.To {
+		issues["to"] = "to must not be before from"
+	}
+	if filter.Status != "" && !catalogue.has(filter.Status) {
+		issues["status"] = "status is not a known attendance status"
+	}
+
+	if len(issues) > 0 {
+		return &ValidationError{Fields: issues}
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
 
@@ -15,71 +17,536 @@ import (
 func RoutesAttendance(rg *gin.RouterGroup) {
 	attendance := rg.Group("/attendance")
 
-	attendance.POST("/mark", util.TokenAuthMiddleware(), markAttendance)
-	attendance.GET("/:student_id", util.TokenAuthMiddleware(), getAttendance)
+	attendance.POST("/mark", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceMark), markAttendance)
+	attendance.POST("/bulk", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceMark), bulkMarkAttendance)
+	attendance.POST("/import", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceImport), importAttendance)
+	attendance.GET("/export", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceExport), exportAttendanceHandler)
+	attendance.GET("/lock-overrides", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceAudit), getLockOverrides)
+
+	// A logged in student checks themselves in: the token names the session,
+	// and the student is the one whose email matches the account's.
+	attendance.POST("/checkin", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceCheckin), checkInAttendance)
+
+	// gin requires wildcards at the same position to share a name, so the
+	// student listing and the record routes both use :id.
+	attendance.GET("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceRead), getAttendance)
+	attendance.PUT("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceCorrect), updateAttendance)
+	attendance.DELETE("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceDelete), deleteAttendance)
+	attendance.GET("/:id/history", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceRead), getAttendanceHistory)
 }
 
 // markAttendance godoc
 // @Summary Mark attendance
-// @Description Mark attendance for a student
+// @Description Mark attendance for a student, optionally for one session of the day. on_conflict decides what happens when the student is already marked for that date and session: reject (409), ignore (200 with the stored record) or update (200 with the new status). Marks on weekends, holidays or days outside every term, and marks for a section's session by a student not enrolled in the section, are rejected with 422; marks dated before the ATTENDANCE.LOCK_AFTER_DAYS window with 403 unless the caller has the attendance:override_lock permission. Teachers can only mark students of their own departments (403 otherwise). When latitude and longitude are sent, a mark counted as present from outside the geofence of location_id (or of every location when location_id is omitted) is stored with status Flagged and queued for review. OnLeave and Flagged are only set by leave approval and the geofence check and are rejected with 400.
 // @Tags Attendance
 // @Accept  json
 // @Produce  json
-// @Param attendance body model.Attendance true "Attendance"
+// @Param attendance body model.AttendanceMark true "Attendance"
+// @Param on_conflict query string false "Behaviour for an existing mark" Enums(reject, ignore, update) default(reject)
+// @Success 200 {object} model.Attendance
 // @Success 201 {object} model.Attendance
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security bearerAuth
 // @Router /attendance/mark [post]
 func markAttendance(c *gin.Context) {
-	var attendance model.Attendance
-	if err := c.ShouldBindJSON(&attendance); err != nil {
+	mode := c.DefaultQuery("on_conflict", conflictReject)
+	if err := validateConflictMode(mode); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var mark model.AttendanceMark
+	if err := c.ShouldBindJSON(&mark); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := validateISODate(attendance.Date); err != nil {
+	if err := validateISODate(mark.Date); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if !authorizeStudentID(c, mark.StudentID) {
+		return
+	}
+
+	stored, created, err := locationSvc.MarkAttendance(mark, mode, currentUserID(c))
+	if err != nil {
+		handleAttendanceError(c, err)
+		return
+	}
+	evaluateAlertsAfterWrite(stored.StudentID)
 
-	// Validate date format if needed, but binding should handle basic string presence.
-	// Ideally we parse the date string to ensure it's valid YYYY-MM-DD.
+	if created {
+		c.JSON(http.StatusCreated, stored)
+		return
+	}
+	c.JSON(http.StatusOK, stored)
+}
 
-	id, err := repository.MarkAttendance(attendance)
+// bulkMarkAttendance godoc
+// @Summary Mark attendance in bulk
+// @Description Mark attendance for a whole class on one date, optionally for one session, in a single transaction. Each entry is reported as created, duplicate, unknown_student, on_leave or not_enrolled; students on approved leave are not marked, entries with the workflow statuses OnLeave or Flagged fail validation with 400, and a session of a section only accepts its enrolled students. Teachers can only mark students of their own departments; a batch naming any other student is rejected with 403. Dates before the lock window are rejected with 403 unless the caller has the attendance:override_lock permission.
+// @Tags Attendance
+// @Accept  json
+// @Produce  json
+// @Param attendance body model.BulkAttendanceRequest true "Bulk attendance"
+// @Success 200 {object} model.BulkAttendanceResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /attendance/bulk [post]
+func bulkMarkAttendance(c *gin.Context) {
+	var req model.BulkAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateISODate(req.Date); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	studentIDs := make([]int64, 0, len(req.Entries))
+	for _, entry := range req.Entries {
+		studentIDs = append(studentIDs, entry.StudentID)
+	}
+	if !authorizeStudentIDs(c, studentIDs) {
+		return
+	}
+
+	if err := calendarSvc.CheckWorkingDay(req.Date); err != nil {
+		handleAttendanceError(c, err)
+		return
+	}
+	session, err := validateAttendanceSession(req.SessionID, req.Date)
+	if err != nil {
+		handleAttendanceError(c, err)
+		return
+	}
+
+	results, err := markAttendanceBulk(req, session.SectionID, currentUserID(c))
+	if errors.Is(err, ErrAttendanceLocked) {
+		handleAttendanceError(c, err)
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark attendance: " + err.Error()})
 		return
 	}
 
-	attendance.ID = id
+	resp := model.BulkAttendanceResponse{Date: req.Date, Results: results}
+	var createdFor []int64
+	for _, r := range results {
+		switch r.Result {
+		case model.BulkResultCreated:
+			resp.Created++
+			createdFor = append(createdFor, r.StudentID)
+		case model.BulkResultDuplicate:
+			resp.Duplicates++
+		case model.BulkResultUnknownStudent:
+			resp.Unknown++
+		case model.BulkResultOnLeave:
+			resp.OnLeave++
+		case model.BulkResultNotEnrolled:
+			resp.NotEnrolled++
+		}
+	}
+	evaluateAlertsAfterWrite(createdFor...)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// importAttendance godoc
+// @Summary Import attendance from CSV
+// @Description Import historical whole-day attendance from a CSV of student (email or ID), date and status. The file is sent as the multipart field "file" or as a raw text/csv body, with an optional header row naming the columns. Valid lines are inserted in batches and records that already exist are skipped as duplicates; invalid lines, including those with the workflow statuses OnLeave or Flagged, are reported with their line number. Lines dated inside the attendance lock are rejected unless the caller has attendance:override_lock, and every record imported under the override is written to the lock override log. Imports are not checked against the academic calendar or approved leave. With dry_run nothing is written. With report=csv the rejected lines are returned as a CSV file instead of JSON.
+// @Tags Attendance
+// @Accept  mpfd
+// @Accept  text/csv
+// @Produce  json
+// @Produce  text/csv
+// @Param file formData file false "CSV file"
+// @Param dry_run query bool false "Validate without writing" default(false)
+// @Param report query string false "Response format" Enums(json, csv) default(json)
+// @Success 200 {object} model.ImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /attendance/import [post]
+func importAttendance(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run must be true or false"})
+		return
+	}
+	report := c.DefaultQuery("report", "json")
+	if report != "json" && report != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "report must be json or csv"})
+		return
+	}
+
+	body := c.Request.Body
+	if c.ContentType() == "multipart/form-data" {
+		header, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+		file, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	importer := newAttendanceImporter(dryRun, currentUserID(c))
+	result, err := importer.Import(body)
+	evaluateAlertsAfterWrite(importer.ImportedStudents()...)
+	if errors.Is(err, ErrImportHeader) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import attendance: " + err.Error(), "result": result})
+		return
+	}
+
+	if report == "csv" {
+		c.Header("Content-Disposition", `attachment; filename="attendance-import-errors.csv"`)
+		c.Header("X-Import-Total", strconv.Itoa(result.Total))
+		c.Header("X-Import-Imported", strconv.Itoa(result.Imported))
+		c.Header("X-Import-Duplicates", strconv.Itoa(result.Duplicates))
+		c.Header("X-Import-Rejected", strconv.Itoa(result.Rejected))
+		c.Header("Content-Type", "text/csv")
+		c.Status(http.StatusOK)
+		if err := writeImportErrorReport(c.Writer, result.Errors); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// checkInAttendance godoc
+// @Summary Self check-in
+// @Description Mark the calling student Present for the session of a check-in token scanned from the class QR code. The caller is the student whose email matches their account email; other accounts are refused with 403. A student can use each token once, and only while the session is under way: dated today, not closed and between its start and end times. Marks on non-working days, during approved leave or for a session the student is already marked for are rejected as in markAttendance.
+// @Tags Attendance
+// @Accept  json
+// @Produce  json
+// @Param checkin body model.CheckinRequest true "Check-in"
+// @Success 201 {object} model.Attendance
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /attendance/checkin [post]
+func checkInAttendance(c *gin.Context) {
+	var req model.CheckinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attendance, err := checkinSvc.CheckIn(req.Token, currentUserID(c))
+	if err != nil {
+		handleAttendanceError(c, err)
+		return
+	}
+	evaluateAlertsAfterWrite(attendance.StudentID)
+
 	c.JSON(http.StatusCreated, attendance)
 }
 
+// exportAttendanceHandler godoc
+// @Summary Export attendance
+// @Description Stream every attendance record in a date range, joined with the student's name, email and department, as CSV or JSON Lines. CSV starts with a UTF-8 byte order mark and uses CRLF line endings so spreadsheet applications open it directly.
+// @Tags Attendance
+// @Accept  json
+// @Produce  text/csv
+// @Produce  application/x-ndjson
+// @Param from query string true "Earliest date (YYYY-MM-DD)"
+// @Param to query string true "Latest date (YYYY-MM-DD)"
+// @Param department_id query int false "Only students of this department"
+// @Param format query string false "Export format" Enums(csv, jsonl) default(csv)
+// @Success 200 {array} model.AttendanceExportRow
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /attendance/export [get]
+func exportAttendanceHandler(c *gin.Context) {
+	format := c.DefaultQuery("format", exportFormatCSV)
+	filter := model.AttendanceExportFilter{From: c.Query("from"), To: c.Query("to")}
+	if raw := c.Query("department_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid department_id"})
+			return
+		}
+		filter.DepartmentID = id
+	}
+	if err := validateExportFilter(filter, format); err != nil {
+		handleAttendanceError(c, err)
+		return
+	}
+
+	started := false
+	start := func() {
+		started = true
+		filename := "attendance-" + filter.From + "-" + filter.To + "." + format
+		c.Header("Content-Type", exportContentTypes[format])
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		c.Status(http.StatusOK)
+	}
+
+	err := exportAttendance(c.Request.Context(), filter, format, c.Writer, start)
+	if err != nil && !started {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export attendance: " + err.Error()})
+		return
+	}
+	if err != nil {
+		// The status line is already sent, so the client sees a truncated body.
+		log.Println("Error streaming attendance export: " + err.Error())
+		c.Error(err)
+	}
+}
+
+// getLockOverrides godoc
+// @Summary Lock override audit log
+// @Description List changes made to attendance older than the ATTENDANCE.LOCK_AFTER_DAYS window by users with the attendance:override_lock permission, newest first
+// @Tags Attendance
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size, at most 100" default(10) maximum(100)
+// @Success 200 {array} model.LockOverride
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /attendance/lock-overrides [get]
+func getLockOverrides(c *gin.Context) {
+	_, limit, offset := pagination(c)
+
+	overrides, err := lockSvc.GetOverrides(limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch lock overrides"})
+		return
+	}
+
+	c.JSON(http.StatusOK, overrides)
+}
+
 // getAttendance godoc
 // @Summary Get attendance
-// @Description Get attendance records for a student
+// @Description Get a page of attendance records for a student, newest first, optionally limited to a date range and status. Teachers can only view students of their own departments.
 // @Tags Attendance
 // @Accept  json
 // @Produce  json
-// @Param student_id path int true "Student ID"
-// @Success 200 {array} model.Attendance
+// @Param id path int true "Student ID"
+// @Param from query string false "Earliest date (YYYY-MM-DD)"
+// @Param to query string false "Latest date (YYYY-MM-DD)"
+// @Param status query string false "Attendance status code"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size, at most 100" default(10) maximum(100)
+// @Success 200 {object} model.AttendancePage
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security bearerAuth
-// @Router /attendance/{student_id} [get]
+// @Router /attendance/{id} [get]
 func getAttendance(c *gin.Context) {
-	studentIDStr := c.Param("student_id")
+	studentIDStr := c.Param("id")
 	studentID, err := strconv.ParseInt(studentIDStr, 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Student ID"})
 		return
 	}
 
-	attendances, err := repository.GetAttendanceByStudentID(studentID)
+	filter := model.AttendanceFilter{
+		StudentID: studentID,
+		From:      c.Query("from"),
+		To:        c.Query("to"),
+		Status:    c.Query("status"),
+	}
+	if err := validateAttendanceFilter(filter); err != nil {
+		handleAttendanceError(c, err)
+		return
+	}
+
+	student, err := studentSvc.GetStudentByID(studentID)
+	if err != nil {
+		handleAttendanceError(c, err)
+		return
+	}
+	if !authorizeStudent(c, student) {
+		return
+	}
+
+	page, limit, offset := pagination(c)
+
+	attendances, total, err := repository.GetAttendancePage(filter, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attendance"})
 		return
 	}
 
-	c.JSON(http.StatusOK, attendances)
+	c.JSON(http.StatusOK, model.AttendancePage{Data: attendances, Total: total, Page: page, Limit: limit})
+}
+
+// updateAttendance godoc
+// @Summary Correct attendance
+// @Description Change the status of an attendance record. The previous status is kept in the audit trail. Records dated before the lock window can only be changed by users with the attendance:override_lock permission. The workflow statuses OnLeave and Flagged cannot be set here.
+// @Tags Attendance
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Attendance ID"
+// @Param attendance body model.AttendanceUpdate true "New status"
+// @Success 200 {object} model.Attendance
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /attendance/{id} [put]
+func updateAttendance(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var update model.AttendanceUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attendance, err := correctAttendance(id, update.Status, currentUserID(c))
+	if err != nil {
+		handleAttendanceError(c, err)
+		return
+	}
+	evaluateAlertsAfterWrite(attendance.StudentID)
+
+	c.JSON(http.StatusOK, attendance)
+}
+
+// deleteAttendance godoc
+// @Summary Delete attendance
+// @Description Delete an attendance record. Its last state is kept in the audit trail. Records dated before the lock window can only be deleted by users with the attendance:override_lock permission.
+// @Tags Attendance
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Attendance ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /attendance/{id} [delete]
+func deleteAttendance(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	deleted, err := removeAttendance(id, currentUserID(c))
+	if err != nil {
+		handleAttendanceError(c, err)
+		return
+	}
+	evaluateAlertsAfterWrite(deleted.StudentID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attendance deleted successfully"})
+}
+
+// getAttendanceHistory godoc
+// @Summary Attendance history
+// @Description Get the audit trail of an attendance record, including records that have since been deleted. Teachers can only view the history of students of their own departments.
+// @Tags Attendance
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Attendance ID"
+// @Success 200 {array} model.AttendanceAudit
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /attendance/{id}/history [get]
+func getAttendanceHistory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	audits, err := repository.GetAttendanceAudit(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attendance history"})
+		return
+	}
+
+	// A record that was never changed has no history but still exists.
+	var studentID int64
+	if len(audits) == 0 {
+		attendance, err := repository.GetAttendanceByID(id)
+		if err != nil {
+			handleAttendanceError(c, err)
+			return
+		}
+		studentID = attendance.StudentID
+		audits = model.AttendanceAudits{}
+	} else {
+		studentID = audits[0].StudentID
+	}
+
+	if !authorizeStudentID(c, studentID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, audits)
+}
+
+func handleAttendanceError(c *gin.Context, err error) {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error(), "details": validationErr.Fields})
+	case errors.Is(err, ErrNonWorkingDay), errors.Is(err, ErrNoGeofence), errors.Is(err, ErrNotEnrolled):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrInvalidConflictMode), errors.Is(err, ErrInvalidExportFormat):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrSessionDateMismatch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrAttendanceNotFound), errors.Is(err, repository.ErrStudentNotFound),
+		errors.Is(err, repository.ErrSessionNotFound), errors.Is(err, repository.ErrLocationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrAttendanceLocked), errors.Is(err, ErrOutsideDepartments), errors.Is(err, ErrNotAStudent):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, util.ErrInvalidCheckinToken):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrDuplicateAttendance), errors.Is(err, ErrStudentOnLeave),
+		errors.Is(err, ErrCheckinAlreadyUsed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
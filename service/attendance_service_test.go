@@ -1,9 +1,17 @@
 package service
 
 import (
+	"bytes"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -23,3 +31,155 @@ func TestGetAttendanceRejectsID(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 }
+
+func TestGetAttendanceRejectsInvalidFilter(t *testing.T) {
+	params := gin.Params{{Key: "id", Value: "1"}}
+
+	rr, resp := performJSONRequestWithParams(getAttendance, http.MethodGet, "/attendance/1?from=27-10-2023", params, nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp["details"], "from")
+
+	rr, resp = performJSONRequestWithParams(getAttendance, http.MethodGet, "/attendance/1?from=2023-10-31&to=2023-10-01", params, nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp["details"], "to")
+
+	rr, resp = performJSONRequestWithParams(getAttendance, http.MethodGet, "/attendance/1?status=Sleeping", params, nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp["details"], "status")
+}
+
+func TestGetAttendanceUnknownStudent(t *testing.T) {
+	mockSvc := &studentServiceMock{}
+	mockSvc.On("GetStudentByID", int64(99)).Return(model.Student{}, repository.ErrStudentNotFound)
+	withMockStudentService(t, mockSvc)
+
+	rr, _ := performJSONRequestWithParams(getAttendance, http.MethodGet, "/attendance/99", gin.Params{{Key: "id", Value: "99"}}, nil)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestBulkMarkAttendanceRejectsInvalidPayload(t *testing.T) {
+	rr, resp := performJSONRequest(bulkMarkAttendance, http.MethodPost, "/attendance/bulk", map[string]any{
+		"date":    "27-10-2023",
+		"entries": []map[string]any{{"student_id": 1, "status": "Present"}},
+	})
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "date must be in YYYY-MM-DD format", resp["error"])
+
+	rr, _ = performJSONRequest(bulkMarkAttendance, http.MethodPost, "/attendance/bulk", map[string]any{
+		"date":    "2023-10-27",
+		"entries": []map[string]any{},
+	})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr, _ = performJSONRequest(bulkMarkAttendance, http.MethodPost, "/attendance/bulk", map[string]any{
+		"date":    "2023-10-27",
+		"entries": []map[string]any{{"student_id": 1, "status": "Sleeping"}},
+	})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestUpdateAttendanceRejectsInvalidPayload(t *testing.T) {
+	rr, _ := performJSONRequest(updateAttendance, http.MethodPut, "/attendance/abc", map[string]any{"status": "Absent"})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr, _ = performJSONRequestWithParams(updateAttendance, http.MethodPut, "/attendance/1", gin.Params{{Key: "id", Value: "1"}}, map[string]any{"status": "Unknown"})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestDeleteAttendanceRejectsID(t *testing.T) {
+	rr, _ := performJSONRequest(deleteAttendance, http.MethodDelete, "/attendance/abc", nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr, _ = performJSONRequest(getAttendanceHistory, http.MethodGet, "/attendance/abc/history", nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestMarkAttendanceRejectsConflictMode(t *testing.T) {
+	rr, resp := performJSONRequest(markAttendance, http.MethodPost, "/attendance/mark?on_conflict=overwrite", map[string]any{
+		"student_id": 1,
+		"date":       "2023-10-27",
+		"status":     "Present",
+	})
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, ErrInvalidConflictMode.Error(), resp["error"])
+}
+
+func TestMarkAttendanceValidatesStatusAgainstCatalogue(t *testing.T) {
+	original := catalogue.statuses
+	t.Cleanup(func() { catalogue.set(original) })
+
+	payload := map[string]any{
+		"student_id": 1,
+		"date":       "2023-13-40",
+		"status":     "FieldTrip",
+	}
+
+	rr, resp := performJSONRequest(markAttendance, http.MethodPost, "/attendance/mark", payload)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp["error"], "attendance_status")
+
+	catalogue.set(append(model.AttendanceStatuses{{Code: "FieldTrip", Label: "Field trip", CountsAsPresent: true}}, original...))
+
+	// the status now passes binding, so the request fails on the date instead
+	rr, resp = performJSONRequest(markAttendance, http.MethodPost, "/attendance/mark", payload)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "date must be in YYYY-MM-DD format", resp["error"])
+}
+
+func TestAttendanceWritesRejectWorkflowStatuses(t *testing.T) {
+	for _, status := range []string{model.StatusOnLeave, model.StatusFlagged} {
+		rr, resp := performJSONRequest(markAttendance, http.MethodPost, "/attendance/mark", map[string]any{
+			"student_id": 1,
+			"date":       "2023-10-27",
+			"status":     status,
+		})
+		assert.Equal(t, http.StatusBadRequest, rr.Code, status)
+		assert.Contains(t, resp["error"], "attendance_status", status)
+
+		rr, _ = performJSONRequest(bulkMarkAttendance, http.MethodPost, "/attendance/bulk", map[string]any{
+			"date":    "2023-10-27",
+			"entries": []map[string]any{{"student_id": 1, "status": "Present"}, {"student_id": 2, "status": status}},
+		})
+		assert.Equal(t, http.StatusBadRequest, rr.Code, status)
+
+		rr, _ = performJSONRequestWithParams(updateAttendance, http.MethodPut, "/attendance/1", gin.Params{{Key: "id", Value: "1"}}, map[string]any{"status": status})
+		assert.Equal(t, http.StatusBadRequest, rr.Code, status)
+	}
+}
+
+func TestBulkMarkAttendanceOutsideTeacherDepartments(t *testing.T) {
+	withTeacher(t)
+	mockSvc := &studentServiceMock{}
+	// student 11 does not exist and is left for the bulk mark to report
+	mockSvc.On("GetStudentDepartments", []int64{1, 9, 11}).Return(map[int64]int64{1: 3, 9: 5}, nil).Once()
+	withMockStudentService(t, mockSvc)
+
+	body, _ := json.Marshal(map[string]any{
+		"date": "2023-10-27",
+		"entries": []map[string]any{
+			{"student_id": 1, "status": "Present"},
+			{"student_id": 9, "status": "Present"},
+			{"student_id": 11, "status": "Absent"},
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/attendance/bulk", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("userId", "2")
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	c.Request = req
+	c.Set(util.ContextRoles, []string{model.RoleTeacher})
+
+	bulkMarkAttendance(c)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	var resp map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+	assert.Equal(t, ErrOutsideDepartments.Error(), resp["error"])
+	assert.Equal(t, []any{float64(9)}, resp["student_ids"])
+	mockSvc.AssertExpectations(t)
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"sync"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrStatusReserved is returned when deleting a status the leave, check-in
+// or geofence review workflow depends on.
+var ErrStatusReserved = errors.New("attendance status is required by the leave, check-in or geofence workflow")
+
+// reservedStatuses are set by the application itself and cannot be deleted
+var reservedStatuses = map[string]bool{
+	model.StatusPresent: true,
+	model.StatusAbsent:  true,
+	model.StatusOnLeave: true,
+	model.StatusFlagged: true,
+}
+
+// workflowStatuses are only written by the application: Flagged by the
+// geofence check and OnLeave by leave approval. Clients cannot mark, correct
+// or import them, so every Flagged record has a flag to review and every
+// OnLeave record an approved leave request behind it.
+var workflowStatuses = map[string]bool{
+	model.StatusOnLeave: true,
+	model.StatusFlagged: true,
+}
+
+// statusCatalogue caches the attendance status catalogue so the
+// attendance_status binding rule does not hit the database on every request.
+type statusCatalogue struct {
+	mu       sync.RWMutex
+	statuses model.AttendanceStatuses
+}
+
+// catalogue starts with the seeded statuses and is replaced by
+// LoadAttendanceStatuses and after every change made through the API.
+var catalogue = &statusCatalogue{statuses: model.DefaultAttendanceStatuses}
+
+func (c *statusCatalogue) has(code string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, s := range c.statuses {
+		if s.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsFromClient reports whether clients may submit code: it must be in
+// the catalogue and not a workflow status
+func (c *statusCatalogue) acceptsFromClient(code string) bool {
+	return c.has(code) && !workflowStatuses[code]
+}
+
+// countsAsPresent reports whether code is a known status counted as present
+func (c *statusCatalogue) countsAsPresent(code string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, s := range c.statuses {
+		if s.Code == code {
+			return s.CountsAsPresent
+		}
+	}
+	return false
+}
+
+// all returns a copy of the cached statuses in catalogue order.
+func (c *statusCatalogue) all() model.AttendanceStatuses {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append(model.AttendanceStatuses(nil), c.statuses...)
+}
+
+func (c *statusCatalogue) set(statuses model.AttendanceStatuses) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.statuses = statuses
+}
+
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		_ = v.RegisterValidation("attendance_status", func(fl validator.FieldLevel) bool {
+			return catalogue.acceptsFromClient(fl.Field().String())
+		})
+	}
+}
+
+// LoadAttendanceStatuses refreshes the cached status catalogue from the
+// database. It is called on startup once the database is configured.
+func LoadAttendanceStatuses() error {
+	statuses, err := statusSvc.GetStatuses()
+	if err != nil {
+		return err
+	}
+	catalogue.set(statuses)
+	return nil
+}
+
+// AttendanceStatusService describes the status catalogue operations the HTTP
+// layer relies on.
+type AttendanceStatusService interface {
+	GetStatuses() (model.AttendanceStatuses, error)
+	CreateStatus(status model.AttendanceStatus) (model.AttendanceStatus, error)
+	UpdateStatus(code string, update model.AttendanceStatusUpdate) (model.AttendanceStatus, error)
+	DeleteStatus(code string) error
+}
+
+type attendanceStatusService struct {
+	repo repository.AttendanceStatusRepository
+}
+
+var statusSvc AttendanceStatusService = newAttendanceStatusService(repository.AttendanceStatusRepo)
+
+func newAttendanceStatusService(repo repository.AttendanceStatusRepository) AttendanceStatusService {
+	return &attendanceStatusService{repo: repo}
+}
+
+func (s *attendanceStatusService) GetStatuses() (model.AttendanceStatuses, error) {
+	return s.repo.GetStatuses()
+}
+
+func (s *attendanceStatusService) CreateStatus(status model.AttendanceStatus) (model.AttendanceStatus, error) {
+	if err := validateStatusInput(status); err != nil {
+		return model.AttendanceStatus{}, err
+	}
+	if err := s.repo.CreateStatus(status); err != nil {
+		return model.AttendanceStatus{}, err
+	}
+	s.refresh()
+	return status, nil
+}
+
+func (s *attendanceStatusService) UpdateStatus(code string, update model.AttendanceStatusUpdate) (model.AttendanceStatus, error) {
+	status := model.AttendanceStatus{
+		Code:            code,
+		Label:           update.Label,
+		CountsAsPresent: update.CountsAsPresent,
+		Excused:         update.Excused,
+		SortOrder:       update.SortOrder,
+	}
+	if err := validateStatusInput(status); err != nil {
+		return model.AttendanceStatus{}, err
+	}
+	if err := s.repo.UpdateStatus(code, status); err != nil {
+		return model.AttendanceStatus{}, err
+	}
+	s.refresh()
+	return status, nil
+}
+
+func (s *attendanceStatusService) DeleteStatus(code string) error {
+	if reservedStatuses[code] {
+		return ErrStatusReserved
+	}
+	if err := s.repo.DeleteStatus(code); err != nil {
+		return err
+	}
+	s.refresh()
+	return nil
+}
+
+// validateStatusInput rejects a status that would count as present and be
+// excused at once.
+func validateStatusInput(status model.AttendanceStatus) error {
+	if status.CountsAsPresent && status.Excused {
+		return &ValidationError{Fields: map[string]string{"excused": "an excused status cannot count as present"}}
+	}
+	return nil
+}
+
+// refresh reloads the cached catalogue after a change. A failure only leaves
+// the cache stale, so it is logged rather than returned.
+func (s *attendanceStatusService) refresh() {
+	statuses, err := s.repo.GetStatuses()
+	if err != nil {
+		log.Println("Error refreshing attendance status catalogue: " + err.Error())
+		return
+	}
+	catalogue.set(statuses)
+}
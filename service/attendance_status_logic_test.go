@@ -0,0 +1,21 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateStatusInput(t *testing.T) {
+	assert.NoError(t, validateStatusInput(model.AttendanceStatus{Code: "Medical", Label: "Medical", Excused: true}))
+	assert.NoError(t, validateStatusInput(model.AttendanceStatus{Code: "Late", Label: "Late", CountsAsPresent: true}))
+
+	err := validateStatusInput(model.AttendanceStatus{Code: "Odd", Label: "Odd", CountsAsPresent: true, Excused: true})
+	var validationErr *ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) {
+		assert.Contains(t, validationErr.Fields, "excused")
+	}
+}
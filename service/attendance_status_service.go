@@ -0,0 +1,142 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutesAttendanceStatus registers the attendance status catalogue routes
+func RoutesAttendanceStatus(rg *gin.RouterGroup) {
+	statuses := rg.Group("/attendance/statuses")
+
+	statuses.GET("", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceRead), getAttendanceStatuses)
+	statuses.POST("", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceStatusesWrite), createAttendanceStatus)
+	statuses.PUT("/:code", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceStatusesWrite), updateAttendanceStatus)
+	statuses.DELETE("/:code", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceStatusesWrite), deleteAttendanceStatus)
+}
+
+// getAttendanceStatuses godoc
+// @Summary List attendance statuses
+// @Description Get the status catalogue used to validate attendance marks and aggregate reports
+// @Tags Attendance Statuses
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} model.AttendanceStatus
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /attendance/statuses [get]
+func getAttendanceStatuses(c *gin.Context) {
+	statuses, err := statusSvc.GetStatuses()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attendance statuses"})
+		return
+	}
+
+	c.JSON(http.StatusOK, statuses)
+}
+
+// createAttendanceStatus godoc
+// @Summary Create an attendance status
+// @Description Add a status to the catalogue. counts_as_present decides whether it is counted as present in reports, summaries and alerts; excused statuses count neither as present nor as absent. A status cannot be both.
+// @Tags Attendance Statuses
+// @Accept  json
+// @Produce  json
+// @Param status body model.AttendanceStatus true "Status"
+// @Success 201 {object} model.AttendanceStatus
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /attendance/statuses [post]
+func createAttendanceStatus(c *gin.Context) {
+	var status model.AttendanceStatus
+	if err := c.ShouldBindJSON(&status); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := statusSvc.CreateStatus(status)
+	if err != nil {
+		handleAttendanceStatusError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// updateAttendanceStatus godoc
+// @Summary Update an attendance status
+// @Description Change the label, present and excused flags or order of a status. The code cannot be changed.
+// @Tags Attendance Statuses
+// @Accept  json
+// @Produce  json
+// @Param code path string true "Status code"
+// @Param status body model.AttendanceStatusUpdate true "Status"
+// @Success 200 {object} model.AttendanceStatus
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /attendance/statuses/{code} [put]
+func updateAttendanceStatus(c *gin.Context) {
+	var update model.AttendanceStatusUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := statusSvc.UpdateStatus(c.Param("code"), update)
+	if err != nil {
+		handleAttendanceStatusError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// deleteAttendanceStatus godoc
+// @Summary Delete an attendance status
+// @Description Remove a status from the catalogue. Present, Absent, OnLeave, Flagged and statuses used by attendance records cannot be deleted.
+// @Tags Attendance Statuses
+// @Accept  json
+// @Produce  json
+// @Param code path string true "Status code"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /attendance/statuses/{code} [delete]
+func deleteAttendanceStatus(c *gin.Context) {
+	if err := statusSvc.DeleteStatus(c.Param("code")); err != nil {
+		handleAttendanceStatusError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attendance status deleted successfully"})
+}
+
+func handleAttendanceStatusError(c *gin.Context, err error) {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error(), "details": validationErr.Fields})
+	case errors.Is(err, repository.ErrStatusNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrDuplicateStatus), errors.Is(err, repository.ErrStatusInUse),
+		errors.Is(err, ErrStatusReserved):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
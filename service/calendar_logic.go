@@ -0,0 +1,307 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+)
+
+// ErrNonWorkingDay is returned when attendance is marked on a weekend,
+// holiday or a day outside every term.
+var ErrNonWorkingDay = errors.New("date is not a working day")
+
+// CalendarService describes the academic calendar operations the HTTP layer
+// and attendance marking rely on.
+type CalendarService interface {
+	CreateTerm(term model.Term) (model.Term, error)
+	GetTerms() (model.Terms, error)
+	GetTermByID(id int64) (model.Term, error)
+	UpdateTerm(id int64, term model.Term) (model.Term, error)
+	DeleteTerm(id int64) error
+	CreateHoliday(holiday model.Holiday) (model.Holiday, error)
+	GetHolidays(from, to string) (model.Holidays, error)
+	UpdateHoliday(id int64, holiday model.Holiday) (model.Holiday, error)
+	DeleteHoliday(id int64) error
+	GetWeekendRule() (model.WeekendRule, error)
+	SetWeekendRule(rule model.WeekendRule) (model.WeekendRule, error)
+	WorkingDays(from, to string) ([]string, error)
+	CheckWorkingDay(date string) error
+}
+
+type calendarService struct {
+	repo repository.CalendarRepository
+}
+
+var calendarSvc CalendarService = newCalendarService(repository.CalendarRepo)
+
+func newCalendarService(repo repository.CalendarRepository) CalendarService {
+	return &calendarService{repo: repo}
+}
+
+func (s *calendarService) CreateTerm(term model.Term) (model.Term, error) {
+	if err := validateTermInput(term); err != nil {
+		return model.Term{}, err
+	}
+
+	id, err := s.repo.CreateTerm(term)
+	if err != nil {
+		return model.Term{}, err
+	}
+
+	term.ID = id
+	return term, nil
+}
+
+func (s *calendarService) GetTerms() (model.Terms, error) {
+	return s.repo.GetTerms()
+}
+
+func (s *calendarService) GetTermByID(id int64) (model.Term, error) {
+	return s.repo.GetTermByID(id)
+}
+
+func (s *calendarService) UpdateTerm(id int64, term model.Term) (model.Term, error) {
+	if err := validateTermInput(term); err != nil {
+		return model.Term{}, err
+	}
+
+	if err := s.repo.UpdateTerm(id, term); err != nil {
+		return model.Term{}, err
+	}
+
+	term.ID = id
+	return term, nil
+}
+
+func (s *calendarService) DeleteTerm(id int64) error {
+	return s.repo.DeleteTerm(id)
+}
+
+func (s *calendarService) CreateHoliday(holiday model.Holiday) (model.Holiday, error) {
+	if err := validateHolidayInput(holiday); err != nil {
+		return model.Holiday{}, err
+	}
+
+	id, err := s.repo.CreateHoliday(holiday)
+	if err != nil {
+		return model.Holiday{}, err
+	}
+
+	holiday.ID = id
+	return holiday, nil
+}
+
+func (s *calendarService) GetHolidays(from, to string) (model.Holidays, error) {
+	return s.repo.GetHolidays(from, to)
+}
+
+func (s *calendarService) UpdateHoliday(id int64, holiday model.Holiday) (model.Holiday, error) {
+	if err := validateHolidayInput(holiday); err != nil {
+		return model.Holiday{}, err
+	}
+
+	if err := s.repo.UpdateHoliday(id, holiday); err != nil {
+		return model.Holiday{}, err
+	}
+
+	holiday.ID = id
+	return holiday, nil
+}
+
+func (s *calendarService) DeleteHoliday(id int64) error {
+	return s.repo.DeleteHoliday(id)
+}
+
+func (s *calendarService) GetWeekendRule() (model.WeekendRule, error) {
+	days, err := s.repo.GetWeekendDays()
+	if err != nil {
+		return model.WeekendRule{}, err
+	}
+
+	rule := model.WeekendRule{Weekdays: make([]string, 0, len(days))}
+	for _, d := range days {
+		rule.Weekdays = append(rule.Weekdays, d.String())
+	}
+	return rule, nil
+}
+
+func (s *calendarService) SetWeekendRule(rule model.WeekendRule) (model.WeekendRule, error) {
+	seen := make(map[time.Weekday]bool)
+	var days []time.Weekday
+	for _, name := range rule.Weekdays {
+		d, ok := parseWeekday(name)
+		if !ok {
+			return model.WeekendRule{}, &ValidationError{Fields: map[string]string{"weekdays": fmt.Sprintf("%q is not a weekday", name)}}
+		}
+		if !seen[d] {
+			seen[d] = true
+			days = append(days, d)
+		}
+	}
+
+	if err := s.repo.SetWeekendDays(days); err != nil {
+		return model.WeekendRule{}, err
+	}
+
+	return s.GetWeekendRule()
+}
+
+func (s *calendarService) WorkingDays(from, to string) ([]string, error) {
+	cal, err := s.load(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	start, _ := time.Parse(isoDateLayout, from)
+	end, _ := time.Parse(isoDateLayout, to)
+
+	days := []string{}
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if cal.nonWorkingReason(d) == "" {
+			days = append(days, d.Format(isoDateLayout))
+		}
+	}
+	return days, nil
+}
+
+func (s *calendarService) CheckWorkingDay(date string) error {
+	day, err := time.Parse(isoDateLayout, date)
+	if err != nil {
+		return err
+	}
+
+	cal, err := s.load(date, date)
+	if err != nil {
+		return err
+	}
+
+	if reason := cal.nonWorkingReason(day); reason != "" {
+		return fmt.Errorf("%w: %s", ErrNonWorkingDay, reason)
+	}
+	return nil
+}
+
+// academicCalendar is the part of the calendar needed to classify days of a
+// date range.
+type academicCalendar struct {
+	terms    model.Terms
+	holidays map[string]string
+	weekend  map[time.Weekday]bool
+}
+
+func (s *calendarService) load(from, to string) (academicCalendar, error) {
+	cal := academicCalendar{
+		holidays: make(map[string]string),
+		weekend:  make(map[time.Weekday]bool),
+	}
+
+	terms, err := s.repo.GetTerms()
+	if err != nil {
+		return cal, err
+	}
+	cal.terms = terms
+
+	holidays, err := s.repo.GetHolidays(from, to)
+	if err != nil {
+		return cal, err
+	}
+	for _, h := range holidays {
+		cal.holidays[h.Date] = h.Name
+	}
+
+	weekend, err := s.repo.GetWeekendDays()
+	if err != nil {
+		return cal, err
+	}
+	for _, d := range weekend {
+		cal.weekend[d] = true
+	}
+
+	return cal, nil
+}
+
+// nonWorkingReason explains why day is not a working day, or returns "" when
+// it is. Without any terms every non-weekend, non-holiday day is working.
+func (cal academicCalendar) nonWorkingReason(day time.Time) string {
+	date := day.Format(isoDateLayout)
+
+	if cal.weekend[day.Weekday()] {
+		return day.Weekday().String() + " is a weekend day"
+	}
+	if name, ok := cal.holidays[date]; ok {
+		return "holiday (" + name + ")"
+	}
+	if len(cal.terms) == 0 {
+		return ""
+	}
+	for _, t := range cal.terms {
+		if date >= t.StartDate && date <= t.EndDate {
+			return ""
+		}
+	}
+	return "outside every term"
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), strings.TrimSpace(name)) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+func validateTermInput(term model.Term) error {
+	issues := make(map[string]string)
+
+	if strings.TrimSpace(term.Name) == "" {
+		issues["name"] = "name is required"
+	}
+	if err := validateISODate(term.StartDate); err != nil {
+		issues["start_date"] = err.Error()
+	}
+	if err := validateISODate(term.EndDate); err != nil {
+		issues["end_date"] = err.Error()
+	}
+	if len(issues) == 0 && term.EndDate < term.StartDate {
+		issues["end_date"] = "end_date must not be before start_date"
+	}
+
+	if len(issues) > 0 {
+		return &ValidationError{Fields: issues}
+	}
+
+	return nil
+}
+
+func validateHolidayInput(holiday model.Holiday) error {
+	issues := make(map[string]string)
+
+	if strings.TrimSpace(holiday.Name) == "" {
+		issues["name"] = "name is required"
+	}
+	if err := validateISODate(holiday.Date); err != nil {
+		issues["date"] = err.Error()
+	}
+
+	if len(issues) > 0 {
+		return &ValidationError{Fields: issues}
+	}
+
+	return nil
+}
+
+// countUnmarkedWorkingDays counts the working days without a mark.
+func countUnmarkedWorkingDays(workingDays []string, marked map[string]bool) int {
+	unmarked := 0
+	for _, d := range workingDays {
+		if !marked[d] {
+			unmarked++
+		}
+	}
+	return unmarked
+}
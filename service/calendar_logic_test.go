@@ -0,0 +1,157 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockCalendarRepository struct {
+	mock.Mock
+}
+
+func (m *mockCalendarRepository) CreateTerm(term model.Term) (int64, error) {
+	args := m.Called(term)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockCalendarRepository) GetTerms() (model.Terms, error) {
+	args := m.Called()
+	terms, _ := args.Get(0).(model.Terms)
+	return terms, args.Error(1)
+}
+
+func (m *mockCalendarRepository) GetTermByID(id int64) (model.Term, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Term), args.Error(1)
+}
+
+func (m *mockCalendarRepository) UpdateTerm(id int64, term model.Term) error {
+	return m.Called(id, term).Error(0)
+}
+
+func (m *mockCalendarRepository) DeleteTerm(id int64) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *mockCalendarRepository) CreateHoliday(holiday model.Holiday) (int64, error) {
+	args := m.Called(holiday)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockCalendarRepository) GetHolidays(from, to string) (model.Holidays, error) {
+	args := m.Called(from, to)
+	holidays, _ := args.Get(0).(model.Holidays)
+	return holidays, args.Error(1)
+}
+
+func (m *mockCalendarRepository) GetHolidayByID(id int64) (model.Holiday, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Holiday), args.Error(1)
+}
+
+func (m *mockCalendarRepository) UpdateHoliday(id int64, holiday model.Holiday) error {
+	return m.Called(id, holiday).Error(0)
+}
+
+func (m *mockCalendarRepository) DeleteHoliday(id int64) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *mockCalendarRepository) GetWeekendDays() ([]time.Weekday, error) {
+	args := m.Called()
+	days, _ := args.Get(0).([]time.Weekday)
+	return days, args.Error(1)
+}
+
+func (m *mockCalendarRepository) SetWeekendDays(days []time.Weekday) error {
+	return m.Called(days).Error(0)
+}
+
+func withMockCalendarRepository(t *testing.T, repo *mockCalendarRepository) {
+	original := calendarSvc
+	calendarSvc = newCalendarService(repo)
+	t.Cleanup(func() {
+		calendarSvc = original
+	})
+}
+
+func TestCalendarServiceWorkingDays(t *testing.T) {
+	repo := &mockCalendarRepository{}
+	svc := newCalendarService(repo)
+
+	repo.On("GetTerms").Return(model.Terms{{StartDate: "2023-10-01", EndDate: "2023-10-26"}}, nil)
+	repo.On("GetHolidays", "2023-10-20", "2023-10-29").Return(model.Holidays{{Date: "2023-10-24", Name: "Dussehra"}}, nil)
+	repo.On("GetWeekendDays").Return([]time.Weekday{time.Saturday, time.Sunday}, nil)
+
+	days, err := svc.WorkingDays("2023-10-20", "2023-10-29")
+
+	assert.NoError(t, err)
+	// the 21st and 22nd are a weekend, the 24th a holiday, the 27th onwards outside the term
+	assert.Equal(t, []string{"2023-10-20", "2023-10-23", "2023-10-25", "2023-10-26"}, days)
+	repo.AssertExpectations(t)
+}
+
+func TestCalendarServiceCheckWorkingDay(t *testing.T) {
+	repo := &mockCalendarRepository{}
+	svc := newCalendarService(repo)
+
+	repo.On("GetTerms").Return(model.Terms{}, nil)
+	repo.On("GetHolidays", mock.Anything, mock.Anything).Return(model.Holidays{{Date: "2023-11-14", Name: "Diwali"}}, nil)
+	repo.On("GetWeekendDays").Return([]time.Weekday{time.Sunday}, nil)
+
+	assert.NoError(t, svc.CheckWorkingDay("2023-10-28"))
+	assert.ErrorIs(t, svc.CheckWorkingDay("2023-10-29"), ErrNonWorkingDay)
+
+	err := svc.CheckWorkingDay("2023-11-13")
+	assert.NoError(t, err)
+
+	err = svc.CheckWorkingDay("2023-11-14")
+	assert.ErrorIs(t, err, ErrNonWorkingDay)
+	assert.Contains(t, err.Error(), "Diwali")
+}
+
+func TestCalendarServiceSetWeekendRuleRejectsUnknownDay(t *testing.T) {
+	repo := &mockCalendarRepository{}
+	svc := newCalendarService(repo)
+
+	_, err := svc.SetWeekendRule(model.WeekendRule{Weekdays: []string{"Saturday", "Caturday"}})
+
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	repo.AssertNotCalled(t, "SetWeekendDays", mock.Anything)
+}
+
+func TestCalendarServiceCreateTermValidatesDates(t *testing.T) {
+	repo := &mockCalendarRepository{}
+	svc := newCalendarService(repo)
+
+	_, err := svc.CreateTerm(model.Term{Name: "Autumn", StartDate: "2023-12-15", EndDate: "2023-08-01"})
+
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Contains(t, validationErr.Fields, "end_date")
+	repo.AssertNotCalled(t, "CreateTerm", mock.Anything)
+}
+
+func TestMarkAttendanceRejectsNonWorkingDay(t *testing.T) {
+	repo := &mockCalendarRepository{}
+	repo.On("GetTerms").Return(model.Terms{}, nil)
+	repo.On("GetHolidays", "2023-10-29", "2023-10-29").Return(model.Holidays{}, nil)
+	repo.On("GetWeekendDays").Return([]time.Weekday{time.Saturday, time.Sunday}, nil)
+	withMockCalendarRepository(t, repo)
+
+	rr, resp := performJSONRequest(markAttendance, http.MethodPost, "/attendance/mark", map[string]any{
+		"student_id": 1,
+		"date":       "2023-10-29",
+		"status":     "Present",
+	})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Contains(t, resp["error"], "Sunday")
+}
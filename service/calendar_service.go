@@ -0,0 +1,411 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutesCalendar registers the academic calendar routes
+func RoutesCalendar(rg *gin.RouterGroup) {
+	calendar := rg.Group("/calendar")
+
+	calendar.POST("/terms", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCalendarWrite), createTerm)
+	calendar.GET("/terms", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCalendarRead), getTerms)
+	calendar.GET("/terms/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCalendarRead), getTermByID)
+	calendar.PUT("/terms/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCalendarWrite), updateTerm)
+	calendar.DELETE("/terms/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCalendarWrite), deleteTerm)
+
+	calendar.POST("/holidays", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCalendarWrite), createHoliday)
+	calendar.GET("/holidays", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCalendarRead), getHolidays)
+	calendar.PUT("/holidays/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCalendarWrite), updateHoliday)
+	calendar.DELETE("/holidays/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCalendarWrite), deleteHoliday)
+
+	calendar.GET("/weekend", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCalendarRead), getWeekendRule)
+	calendar.PUT("/weekend", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCalendarWrite), setWeekendRule)
+
+	calendar.GET("/working-days", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCalendarRead), getWorkingDays)
+}
+
+// createTerm godoc
+// @Summary Create a term
+// @Description Create a teaching term. Once terms exist, days outside every term are not working days.
+// @Tags Calendar
+// @Accept  json
+// @Produce  json
+// @Param term body model.Term true "Term"
+// @Success 201 {object} model.Term
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /calendar/terms [post]
+func createTerm(c *gin.Context) {
+	var term model.Term
+	if err := c.ShouldBindJSON(&term); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := calendarSvc.CreateTerm(term)
+	if err != nil {
+		handleCalendarError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// getTerms godoc
+// @Summary List terms
+// @Description Get all terms in calendar order
+// @Tags Calendar
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} model.Term
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /calendar/terms [get]
+func getTerms(c *gin.Context) {
+	terms, err := calendarSvc.GetTerms()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch terms"})
+		return
+	}
+	if terms == nil {
+		terms = model.Terms{}
+	}
+
+	c.JSON(http.StatusOK, terms)
+}
+
+// getTermByID godoc
+// @Summary Get a term by ID
+// @Description Get details of a specific term by ID
+// @Tags Calendar
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Term ID"
+// @Success 200 {object} model.Term
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Security bearerAuth
+// @Router /calendar/terms/{id} [get]
+func getTermByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	term, err := calendarSvc.GetTermByID(id)
+	if err != nil {
+		handleCalendarError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, term)
+}
+
+// updateTerm godoc
+// @Summary Update a term
+// @Description Update the name and dates of a term
+// @Tags Calendar
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Term ID"
+// @Param term body model.Term true "Term"
+// @Success 200 {object} model.Term
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /calendar/terms/{id} [put]
+func updateTerm(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var term model.Term
+	if err := c.ShouldBindJSON(&term); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := calendarSvc.UpdateTerm(id, term)
+	if err != nil {
+		handleCalendarError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// deleteTerm godoc
+// @Summary Delete a term
+// @Description Delete a term by ID
+// @Tags Calendar
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Term ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /calendar/terms/{id} [delete]
+func deleteTerm(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := calendarSvc.DeleteTerm(id); err != nil {
+		handleCalendarError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Term deleted successfully"})
+}
+
+// createHoliday godoc
+// @Summary Create a holiday
+// @Description Declare a date a holiday so it is not a working day
+// @Tags Calendar
+// @Accept  json
+// @Produce  json
+// @Param holiday body model.Holiday true "Holiday"
+// @Success 201 {object} model.Holiday
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /calendar/holidays [post]
+func createHoliday(c *gin.Context) {
+	var holiday model.Holiday
+	if err := c.ShouldBindJSON(&holiday); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := calendarSvc.CreateHoliday(holiday)
+	if err != nil {
+		handleCalendarError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// getHolidays godoc
+// @Summary List holidays
+// @Description Get the holidays, optionally limited to a date range
+// @Tags Calendar
+// @Accept  json
+// @Produce  json
+// @Param from query string false "Earliest date (YYYY-MM-DD)"
+// @Param to query string false "Latest date (YYYY-MM-DD)"
+// @Success 200 {array} model.Holiday
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /calendar/holidays [get]
+func getHolidays(c *gin.Context) {
+	from, to := c.Query("from"), c.Query("to")
+	for _, date := range []string{from, to} {
+		if date == "" {
+			continue
+		}
+		if err := validateISODate(date); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	holidays, err := calendarSvc.GetHolidays(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch holidays"})
+		return
+	}
+	if holidays == nil {
+		holidays = model.Holidays{}
+	}
+
+	c.JSON(http.StatusOK, holidays)
+}
+
+// updateHoliday godoc
+// @Summary Update a holiday
+// @Description Update the date and name of a holiday
+// @Tags Calendar
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Holiday ID"
+// @Param holiday body model.Holiday true "Holiday"
+// @Success 200 {object} model.Holiday
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /calendar/holidays/{id} [put]
+func updateHoliday(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var holiday model.Holiday
+	if err := c.ShouldBindJSON(&holiday); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := calendarSvc.UpdateHoliday(id, holiday)
+	if err != nil {
+		handleCalendarError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// deleteHoliday godoc
+// @Summary Delete a holiday
+// @Description Delete a holiday by ID
+// @Tags Calendar
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Holiday ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /calendar/holidays/{id} [delete]
+func deleteHoliday(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := calendarSvc.DeleteHoliday(id); err != nil {
+		handleCalendarError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Holiday deleted successfully"})
+}
+
+// getWeekendRule godoc
+// @Summary Get the weekend rule
+// @Description Get the weekdays that are never working days
+// @Tags Calendar
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} model.WeekendRule
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /calendar/weekend [get]
+func getWeekendRule(c *gin.Context) {
+	rule, err := calendarSvc.GetWeekendRule()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch weekend rule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// setWeekendRule godoc
+// @Summary Replace the weekend rule
+// @Description Replace the weekdays that are never working days. An empty list makes every weekday a working day.
+// @Tags Calendar
+// @Accept  json
+// @Produce  json
+// @Param rule body model.WeekendRule true "Weekend rule"
+// @Success 200 {object} model.WeekendRule
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /calendar/weekend [put]
+func setWeekendRule(c *gin.Context) {
+	var rule model.WeekendRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := calendarSvc.SetWeekendRule(rule)
+	if err != nil {
+		handleCalendarError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// getWorkingDays godoc
+// @Summary List working days
+// @Description Get the working days of a date range according to the terms, holidays and weekend rule
+// @Tags Calendar
+// @Accept  json
+// @Produce  json
+// @Param from query string true "Start date (YYYY-MM-DD)"
+// @Param to query string true "End date (YYYY-MM-DD)"
+// @Success 200 {object} model.WorkingDays
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /calendar/working-days [get]
+func getWorkingDays(c *gin.Context) {
+	from, to := c.Query("from"), c.Query("to")
+	if err := validateDateRange(from, to); err != nil {
+		handleCalendarError(c, err)
+		return
+	}
+
+	days, err := calendarSvc.WorkingDays(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute working days"})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.WorkingDays{From: from, To: to, Count: len(days), Days: days})
+}
+
+func handleCalendarError(c *gin.Context, err error) {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error(), "details": validationErr.Fields})
+	case errors.Is(err, repository.ErrTermNotFound), errors.Is(err, repository.ErrHolidayNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrDuplicateHoliday):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
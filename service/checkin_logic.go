@@ -0,0 +1,178 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/spf13/viper"
+)
+
+// defaultCheckinTTL is used when CHECKIN.TOKEN_TTL_SECONDS is unset
+const defaultCheckinTTL = 2 * time.Minute
+
+// ErrCheckinAlreadyUsed is returned when a student checks in twice with the
+// same token.
+var ErrCheckinAlreadyUsed = errors.New("student has already checked in with this token")
+
+// ErrNotAStudent is returned when the caller checking in has no student
+// record with their account email.
+var ErrNotAStudent = errors.New("your account is not linked to a student")
+
+// accountStudent finds the student record of a user account: the student
+// whose email is the account's email. It returns ErrNotAStudent when there is
+// none.
+func accountStudent(userID int64, users func(id int64) (model.MUser, error), students repository.StudentRepository) (model.Student, error) {
+	user, err := users(userID)
+	if err != nil {
+		return model.Student{}, err
+	}
+	if user.Email == "" {
+		return model.Student{}, ErrNotAStudent
+	}
+	student, err := students.GetStudentByEmail(user.Email)
+	if err != nil {
+		return model.Student{}, err
+	}
+	if student.ID == 0 {
+		return model.Student{}, ErrNotAStudent
+	}
+	return student, nil
+}
+
+// CheckinService describes the QR self check-in operations the HTTP layer
+// relies on.
+type CheckinService interface {
+	IssueToken(sessionID int64) (util.CheckinToken, error)
+	CheckIn(token string, userID int64) (model.Attendance, error)
+}
+
+type checkinService struct {
+	sessions repository.SessionRepository
+	students repository.StudentRepository
+	users    func(id int64) (model.MUser, error)
+	issue    func(sessionID int64, ttl time.Duration) (util.CheckinToken, error)
+	verify   func(token string) (util.CheckinToken, error)
+	claim    func(ct util.CheckinToken, studentID int64) (bool, error)
+	release  func(ct util.CheckinToken, studentID int64) error
+	record   func(attendance model.Attendance) (model.Attendance, error)
+	now      func() time.Time
+}
+
+var checkinSvc CheckinService = newCheckinService(repository.SessionRepo, repository.StudentRepo)
+
+func newCheckinService(sessions repository.SessionRepository, students repository.StudentRepository) *checkinService {
+	return &checkinService{
+		sessions: sessions,
+		students: students,
+		users:    repository.GetUserByID,
+		issue:    util.CreateCheckinToken,
+		verify:   util.VerifyCheckinToken,
+		claim:    util.ClaimCheckin,
+		release:  util.ReleaseCheckin,
+		record: func(attendance model.Attendance) (model.Attendance, error) {
+			stored, _, err := recordAttendance(attendance, nil, conflictReject, 0)
+			return stored, err
+		},
+		now: time.Now,
+	}
+}
+
+func checkinTTL() time.Duration {
+	if viper.IsSet("CHECKIN.TOKEN_TTL_SECONDS") && viper.GetInt("CHECKIN.TOKEN_TTL_SECONDS") > 0 {
+		return time.Duration(viper.GetInt("CHECKIN.TOKEN_TTL_SECONDS")) * time.Second
+	}
+	return defaultCheckinTTL
+}
+
+// checkinQRContent is what the QR code encodes: a link to CHECKIN.URL
+// carrying the token when that is configured, otherwise the bare token.
+func checkinQRContent(token string) string {
+	base := viper.GetString("CHECKIN.URL")
+	if base == "" {
+		return token
+	}
+	return base + "?token=" + url.QueryEscape(token)
+}
+
+// checkSessionOpen rejects check-ins to a session that is not under way at
+// now: one on another day, one already closed or one outside its start and
+// end times.
+func checkSessionOpen(session model.Session, now time.Time) error {
+	switch {
+	case session.Date != now.Format(isoDateLayout):
+		return &ValidationError{Fields: map[string]string{"session": "the session is not today"}}
+	case session.ClosedAt != nil:
+		return &ValidationError{Fields: map[string]string{"session": "the session has been closed"}}
+	}
+
+	clock := now.Format(clockTimeLayout)
+	if clock < session.StartTime || clock > session.EndTime {
+		return &ValidationError{Fields: map[string]string{"session": "the session is not in progress"}}
+	}
+	return nil
+}
+
+// IssueToken signs a short-lived check-in token for a session that is under
+// way
+func (s *checkinService) IssueToken(sessionID int64) (util.CheckinToken, error) {
+	session, err := s.sessions.GetSessionByID(sessionID)
+	if err != nil {
+		return util.CheckinToken{}, err
+	}
+	if err := checkSessionOpen(session, s.now()); err != nil {
+		return util.CheckinToken{}, err
+	}
+	return s.issue(sessionID, checkinTTL())
+}
+
+// CheckIn marks the calling user's student record Present for the token's
+// session, which must still be under way. The student is the one whose email
+// is the caller's account email. Each student can use a token once; the usual
+// calendar, leave and duplicate checks of markAttendance still apply, and a
+// check-in they refuse does not use up the token.
+func (s *checkinService) CheckIn(token string, userID int64) (model.Attendance, error) {
+	ct, err := s.verify(token)
+	if err != nil {
+		return model.Attendance{}, err
+	}
+
+	session, err := s.sessions.GetSessionByID(ct.SessionID)
+	if err != nil {
+		return model.Attendance{}, err
+	}
+	if err := checkSessionOpen(session, s.now()); err != nil {
+		return model.Attendance{}, err
+	}
+
+	student, err := accountStudent(userID, s.users, s.students)
+	if err != nil {
+		return model.Attendance{}, err
+	}
+
+	claimed, err := s.claim(ct, student.ID)
+	if err != nil {
+		return model.Attendance{}, err
+	}
+	if !claimed {
+		return model.Attendance{}, ErrCheckinAlreadyUsed
+	}
+
+	stored, err := s.record(model.Attendance{
+		StudentID: student.ID,
+		Date:      session.Date,
+		SessionID: session.ID,
+		Status:    model.StatusPresent,
+	})
+	if err != nil {
+		if releaseErr := s.release(ct, student.ID); releaseErr != nil {
+			return model.Attendance{}, releaseErr
+		}
+		return model.Attendance{}, err
+	}
+	return stored, nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *mockSessionRepository) CreateSession(session model.Session) (int64, error) {
+	args := m.Called(session)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockSessionRepository) GetSessionsByDate(date string) (model.Sessions, error) {
+	args := m.Called(date)
+	return args.Get(0).(model.Sessions), args.Error(1)
+}
+
+func (m *mockSessionRepository) GetSessionByID(id int64) (model.Session, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Session), args.Error(1)
+}
+
+func (m *mockSessionRepository) DeleteSession(id, deletedBy int64) (model.Attendances, error) {
+	args := m.Called(id, deletedBy)
+	removed, _ := args.Get(0).(model.Attendances)
+	return removed, args.Error(1)
+}
+
+func (m *mockSessionRepository) CreateSessions(sessions model.Sessions) (int, error) {
+	args := m.Called(sessions)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockSessionRepository) GetSessionsToClose(endedBefore time.Time) (model.Sessions, error) {
+	args := m.Called(endedBefore)
+	return args.Get(0).(model.Sessions), args.Error(1)
+}
+
+// checkinSession is under way at the clock of newTestCheckinService
+var checkinSession = model.Session{ID: 3, Date: "2023-10-27", StartTime: "09:00", EndTime: "09:50"}
+
+// newTestCheckinService accepts any token naming session 3 and records marks
+// in memory; claimed tracks which students hold a claim on the token. Users 5
+// and 6 have the emails of ada and of nobody, user 7 has no email. Its clock
+// reads 09:30 on 2023-10-27.
+func newTestCheckinService(sessions *mockSessionRepository, students *mockStudentRepository) (*checkinService, *model.Attendances, map[int64]bool) {
+	svc := newCheckinService(sessions, students)
+	marked := &model.Attendances{}
+	claimed := map[int64]bool{}
+	emails := map[int64]string{5: "ada@example.com", 6: "ghost@example.com"}
+
+	svc.users = func(id int64) (model.MUser, error) {
+		return model.MUser{ID: id, Email: emails[id]}, nil
+	}
+	svc.verify = func(token string) (util.CheckinToken, error) {
+		if token != "valid" {
+			return util.CheckinToken{}, util.ErrInvalidCheckinToken
+		}
+		return util.CheckinToken{Token: token, TokenID: "jti", SessionID: 3}, nil
+	}
+	svc.claim = func(_ util.CheckinToken, studentID int64) (bool, error) {
+		if claimed[studentID] {
+			return false, nil
+		}
+		claimed[studentID] = true
+		return true, nil
+	}
+	svc.release = func(_ util.CheckinToken, studentID int64) error {
+		delete(claimed, studentID)
+		return nil
+	}
+	svc.record = func(a model.Attendance) (model.Attendance, error) {
+		a.ID = int64(len(*marked) + 1)
+		*marked = append(*marked, a)
+		return a, nil
+	}
+	svc.now = func() time.Time {
+		return time.Date(2023, 10, 27, 9, 30, 0, 0, time.Local)
+	}
+	return svc, marked, claimed
+}
+
+func TestCheckInMarksCallerPresentOnce(t *testing.T) {
+	sessions := &mockSessionRepository{}
+	sessions.On("GetSessionByID", int64(3)).Return(checkinSession, nil)
+	students := &mockStudentRepository{}
+	students.On("GetStudentByEmail", "ada@example.com").Return(model.Student{ID: 1}, nil)
+
+	svc, marked, _ := newTestCheckinService(sessions, students)
+
+	attendance, err := svc.CheckIn("valid", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, model.Attendance{ID: 1, StudentID: 1, Date: "2023-10-27", SessionID: 3, Status: model.StatusPresent}, attendance)
+
+	_, err = svc.CheckIn("valid", 5)
+	assert.ErrorIs(t, err, ErrCheckinAlreadyUsed)
+	assert.Len(t, *marked, 1)
+}
+
+func TestCheckInRejectsInvalidTokenAndNonStudents(t *testing.T) {
+	sessions := &mockSessionRepository{}
+	sessions.On("GetSessionByID", int64(3)).Return(checkinSession, nil)
+	students := &mockStudentRepository{}
+	students.On("GetStudentByEmail", "ghost@example.com").Return(model.Student{}, nil)
+
+	svc, marked, _ := newTestCheckinService(sessions, students)
+
+	_, err := svc.CheckIn("forged", 6)
+	assert.ErrorIs(t, err, util.ErrInvalidCheckinToken)
+
+	_, err = svc.CheckIn("valid", 6)
+	assert.ErrorIs(t, err, ErrNotAStudent)
+
+	_, err = svc.CheckIn("valid", 7)
+	assert.ErrorIs(t, err, ErrNotAStudent)
+	assert.Empty(t, *marked)
+}
+
+func TestCheckInFailureKeepsTokenUsable(t *testing.T) {
+	sessions := &mockSessionRepository{}
+	sessions.On("GetSessionByID", int64(3)).Return(checkinSession, nil)
+	students := &mockStudentRepository{}
+	students.On("GetStudentByEmail", "ada@example.com").Return(model.Student{ID: 1}, nil)
+
+	svc, marked, claimed := newTestCheckinService(sessions, students)
+	record := svc.record
+	svc.record = func(model.Attendance) (model.Attendance, error) {
+		return model.Attendance{}, ErrNonWorkingDay
+	}
+
+	_, err := svc.CheckIn("valid", 5)
+	assert.ErrorIs(t, err, ErrNonWorkingDay)
+	assert.Empty(t, claimed)
+
+	svc.record = record
+	_, err = svc.CheckIn("valid", 5)
+	assert.NoError(t, err)
+	assert.Len(t, *marked, 1)
+}
+
+func TestIssueTokenRequiresSession(t *testing.T) {
+	sessions := &mockSessionRepository{}
+	sessions.On("GetSessionByID", int64(9)).Return(model.Session{}, repository.ErrSessionNotFound)
+
+	svc, _, _ := newTestCheckinService(sessions, &mockStudentRepository{})
+	svc.issue = func(int64, time.Duration) (util.CheckinToken, error) {
+		t.Fatal("token issued for an unknown session")
+		return util.CheckinToken{}, nil
+	}
+
+	_, err := svc.IssueToken(9)
+	assert.ErrorIs(t, err, repository.ErrSessionNotFound)
+}
+
+func TestCheckInRequiresSessionUnderWay(t *testing.T) {
+	closedAt := time.Date(2023, 10, 27, 9, 20, 0, 0, time.Local)
+	tests := []struct {
+		name    string
+		session model.Session
+	}{
+		{"another day", model.Session{ID: 3, Date: "2023-10-26", StartTime: "09:00", EndTime: "09:50"}},
+		{"closed", model.Session{ID: 3, Date: "2023-10-27", StartTime: "09:00", EndTime: "09:50", ClosedAt: &closedAt}},
+		{"not started", model.Session{ID: 3, Date: "2023-10-27", StartTime: "10:00", EndTime: "10:50"}},
+		{"ended", model.Session{ID: 3, Date: "2023-10-27", StartTime: "08:00", EndTime: "08:50"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions := &mockSessionRepository{}
+			sessions.On("GetSessionByID", int64(3)).Return(tt.session, nil)
+			students := &mockStudentRepository{}
+			students.On("GetStudentByEmail", "ada@example.com").Return(model.Student{ID: 1}, nil)
+
+			svc, marked, claimed := newTestCheckinService(sessions, students)
+			svc.issue = func(int64, time.Duration) (util.CheckinToken, error) {
+				t.Fatal("token issued for a session that is not under way")
+				return util.CheckinToken{}, nil
+			}
+
+			var validationErr *ValidationError
+			_, err := svc.IssueToken(3)
+			assert.ErrorAs(t, err, &validationErr)
+
+			_, err = svc.CheckIn("valid", 5)
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Empty(t, *marked)
+			assert.Empty(t, claimed)
+		})
+	}
+}
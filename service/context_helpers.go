@@ -0,0 +1,65 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentUserID returns the ID of the authenticated caller as stored in the
+// userId header by util.TokenAuthMiddleware, or 0 when it is absent.
+func currentUserID(c *gin.Context) int64 {
+	id, err := strconv.ParseInt(c.GetHeader("userId"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// clientInfo describes the client of the request for the session index
+func clientInfo(c *gin.Context) util.ClientInfo {
+	return util.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// callerScope looks up the departments the authenticated caller may work
+// with; callers without the teacher role are not restricted. When the lookup
+// fails it answers 500 itself and returns false.
+func callerScope(c *gin.Context) (departmentScope, bool) {
+	if !util.HasRole(c, model.RoleTeacher) {
+		return departmentScope{}, true
+	}
+
+	scope, err := teacherSvc.Scope(currentUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load the caller's departments"})
+		return departmentScope{}, false
+	}
+	return scope, true
+}
+
+// maxPageLimit caps the page size a client can ask for
+const maxPageLimit = 100
+
+// pagination reads the page and limit query parameters, falling back to the
+// first page of 10 items for missing or invalid values. Larger limits are cut
+// to maxPageLimit.
+func pagination(c *gin.Context) (page, limit, offset int) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err = strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+
+	return page, limit, (page - 1) * limit
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagination(t *testing.T) {
+	tests := []struct {
+		query               string
+		page, limit, offset int
+	}{
+		{"", 1, 10, 0},
+		{"?page=3&limit=20", 3, 20, 40},
+		{"?page=0&limit=-5", 1, 10, 0},
+		{"?page=x&limit=y", 1, 10, 0},
+		{"?page=2&limit=100", 2, 100, 100},
+		{"?page=2&limit=100000", 2, maxPageLimit, maxPageLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/students"+tt.query, nil)
+
+			page, limit, offset := pagination(c)
+
+			assert.Equal(t, tt.page, page)
+			assert.Equal(t, tt.limit, limit)
+			assert.Equal(t, tt.offset, offset)
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+)
+
+// ErrNotEnrolled is returned when attendance is marked for a section's
+// session for a student who is not enrolled in that section.
+var ErrNotEnrolled = errors.New("student is not enrolled in the session's section")
+
+// CourseService describes the course, section and enrollment operations the
+// HTTP layer and attendance marking rely on.
+type CourseService interface {
+	CreateCourse(course model.Course) (model.Course, error)
+	GetCourses() (model.Courses, error)
+	GetCourseByID(id int64) (model.Course, error)
+	UpdateCourse(id int64, course model.Course) (model.Course, error)
+	DeleteCourse(id int64) error
+
+	CreateSection(section model.Section) (model.Section, error)
+	GetSections(courseID int64) (model.Sections, error)
+	GetSectionByID(id int64) (model.Section, error)
+	UpdateSection(id int64, section model.Section) (model.Section, error)
+	DeleteSection(id int64) error
+
+	EnrollStudents(sectionID int64, studentIDs []int64) (model.EnrollmentResult, error)
+	GetEnrollments(sectionID int64) (model.Enrollments, error)
+	UnenrollStudent(sectionID, studentID int64) error
+	GetStudentSections(studentID int64) (model.Sections, error)
+
+	CheckEnrolled(sectionID, studentID int64) error
+	EnrolledStudents(sectionID int64, studentIDs []int64) (map[int64]bool, error)
+	SectionReport(sectionID int64, from, to string) (model.AttendanceReports, error)
+}
+
+type courseService struct {
+	repo     repository.CourseRepository
+	students repository.StudentRepository
+	report   func(scope model.ReportScope, from, to string) (model.AttendanceReports, error)
+}
+
+var courseSvc CourseService = newCourseService(repository.CourseRepo, repository.StudentRepo)
+
+func newCourseService(repo repository.CourseRepository, students repository.StudentRepository) *courseService {
+	return &courseService{repo: repo, students: students, report: fetchAttendanceReport}
+}
+
+func (s *courseService) CreateCourse(course model.Course) (model.Course, error) {
+	course, err := normalizeCourseInput(course)
+	if err != nil {
+		return model.Course{}, err
+	}
+
+	id, err := s.repo.CreateCourse(course)
+	if err != nil {
+		return model.Course{}, err
+	}
+
+	return s.repo.GetCourseByID(id)
+}
+
+func (s *courseService) GetCourses() (model.Courses, error) {
+	return s.repo.GetCourses()
+}
+
+func (s *courseService) GetCourseByID(id int64) (model.Course, error) {
+	return s.repo.GetCourseByID(id)
+}
+
+func (s *courseService) UpdateCourse(id int64, course model.Course) (model.Course, error) {
+	course, err := normalizeCourseInput(course)
+	if err != nil {
+		return model.Course{}, err
+	}
+
+	if err := s.repo.UpdateCourse(id, course); err != nil {
+		return model.Course{}, err
+	}
+
+	return s.repo.GetCourseByID(id)
+}
+
+func (s *courseService) DeleteCourse(id int64) error {
+	return s.repo.DeleteCourse(id)
+}
+
+func (s *courseService) CreateSection(section model.Section) (model.Section, error) {
+	section, err := normalizeSectionInput(section)
+	if err != nil {
+		return model.Section{}, err
+	}
+
+	id, err := s.repo.CreateSection(section)
+	if err != nil {
+		return model.Section{}, err
+	}
+
+	return s.repo.GetSectionByID(id)
+}
+
+func (s *courseService) GetSections(courseID int64) (model.Sections, error) {
+	if courseID != 0 {
+		if _, err := s.repo.GetCourseByID(courseID); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.GetSections(courseID)
+}
+
+func (s *courseService) GetSectionByID(id int64) (model.Section, error) {
+	return s.repo.GetSectionByID(id)
+}
+
+func (s *courseService) UpdateSection(id int64, section model.Section) (model.Section, error) {
+	section, err := normalizeSectionInput(section)
+	if err != nil {
+		return model.Section{}, err
+	}
+
+	if err := s.repo.UpdateSection(id, section); err != nil {
+		return model.Section{}, err
+	}
+
+	return s.repo.GetSectionByID(id)
+}
+
+func (s *courseService) DeleteSection(id int64) error {
+	return s.repo.DeleteSection(id)
+}
+
+func (s *courseService) EnrollStudents(sectionID int64, studentIDs []int64) (model.EnrollmentResult, error) {
+	return s.repo.EnrollStudents(sectionID, studentIDs)
+}
+
+func (s *courseService) GetEnrollments(sectionID int64) (model.Enrollments, error) {
+	if _, err := s.repo.GetSectionByID(sectionID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetEnrollments(sectionID)
+}
+
+func (s *courseService) UnenrollStudent(sectionID, studentID int64) error {
+	return s.repo.UnenrollStudent(sectionID, studentID)
+}
+
+func (s *courseService) GetStudentSections(studentID int64) (model.Sections, error) {
+	if _, err := s.students.GetStudentByID(studentID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetStudentSections(studentID)
+}
+
+// CheckEnrolled fails with ErrNotEnrolled unless the student is enrolled in
+// the section
+func (s *courseService) CheckEnrolled(sectionID, studentID int64) error {
+	enrolled, err := s.repo.EnrolledStudents(sectionID, []int64{studentID})
+	if err != nil {
+		return err
+	}
+	if !enrolled[studentID] {
+		return ErrNotEnrolled
+	}
+	return nil
+}
+
+func (s *courseService) EnrolledStudents(sectionID int64, studentIDs []int64) (map[int64]bool, error) {
+	return s.repo.EnrolledStudents(sectionID, studentIDs)
+}
+
+// SectionReport aggregates the attendance of a section's students over its
+// own sessions, following the REPORT.ROLLUP setting
+func (s *courseService) SectionReport(sectionID int64, from, to string) (model.AttendanceReports, error) {
+	if err := validateDateRange(from, to); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetSectionByID(sectionID); err != nil {
+		return nil, err
+	}
+
+	reports, err := s.report(model.ReportScope{SectionID: sectionID}, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if reports == nil {
+		reports = model.AttendanceReports{}
+	}
+	return reports, nil
+}
+
+func normalizeCourseInput(course model.Course) (model.Course, error) {
+	course.Code = strings.ToUpper(strings.TrimSpace(course.Code))
+	course.Name = strings.TrimSpace(course.Name)
+
+	issues := make(map[string]string)
+	if course.Code == "" {
+		issues["code"] = "code is required"
+	}
+	if course.Name == "" {
+		issues["name"] = "name is required"
+	}
+
+	if len(issues) > 0 {
+		return course, &ValidationError{Fields: issues}
+	}
+
+	return course, nil
+}
+
+func normalizeSectionInput(section model.Section) (model.Section, error) {
+	section.Name = strings.TrimSpace(section.Name)
+
+	issues := make(map[string]string)
+	if section.CourseID <= 0 {
+		issues["course_id"] = "course_id is required"
+	}
+	if section.Name == "" {
+		issues["name"] = "name is required"
+	}
+
+	if len(issues) > 0 {
+		return section, &ValidationError{Fields: issues}
+	}
+
+	return section, nil
+}
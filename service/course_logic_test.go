@@ -0,0 +1,209 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockCourseRepository struct {
+	mock.Mock
+}
+
+func (m *mockCourseRepository) CreateCourse(course model.Course) (int64, error) {
+	args := m.Called(course)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockCourseRepository) GetCourses() (model.Courses, error) {
+	args := m.Called()
+	return args.Get(0).(model.Courses), args.Error(1)
+}
+
+func (m *mockCourseRepository) GetCourseByID(id int64) (model.Course, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Course), args.Error(1)
+}
+
+func (m *mockCourseRepository) UpdateCourse(id int64, course model.Course) error {
+	args := m.Called(id, course)
+	return args.Error(0)
+}
+
+func (m *mockCourseRepository) DeleteCourse(id int64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *mockCourseRepository) CreateSection(section model.Section) (int64, error) {
+	args := m.Called(section)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockCourseRepository) GetSections(courseID int64) (model.Sections, error) {
+	args := m.Called(courseID)
+	return args.Get(0).(model.Sections), args.Error(1)
+}
+
+func (m *mockCourseRepository) GetSectionByID(id int64) (model.Section, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Section), args.Error(1)
+}
+
+func (m *mockCourseRepository) UpdateSection(id int64, section model.Section) error {
+	args := m.Called(id, section)
+	return args.Error(0)
+}
+
+func (m *mockCourseRepository) DeleteSection(id int64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *mockCourseRepository) EnrollStudents(sectionID int64, studentIDs []int64) (model.EnrollmentResult, error) {
+	args := m.Called(sectionID, studentIDs)
+	return args.Get(0).(model.EnrollmentResult), args.Error(1)
+}
+
+func (m *mockCourseRepository) GetEnrollments(sectionID int64) (model.Enrollments, error) {
+	args := m.Called(sectionID)
+	return args.Get(0).(model.Enrollments), args.Error(1)
+}
+
+func (m *mockCourseRepository) UnenrollStudent(sectionID, studentID int64) error {
+	args := m.Called(sectionID, studentID)
+	return args.Error(0)
+}
+
+func (m *mockCourseRepository) EnrolledStudents(sectionID int64, studentIDs []int64) (map[int64]bool, error) {
+	args := m.Called(sectionID, studentIDs)
+	return args.Get(0).(map[int64]bool), args.Error(1)
+}
+
+func (m *mockCourseRepository) GetStudentSections(studentID int64) (model.Sections, error) {
+	args := m.Called(studentID)
+	return args.Get(0).(model.Sections), args.Error(1)
+}
+
+func TestCreateCourseNormalizesInput(t *testing.T) {
+	repo := &mockCourseRepository{}
+	svc := newCourseService(repo, &mockStudentRepository{})
+
+	normalized := model.Course{Code: "CS101", Name: "Data Structures", DepartmentID: 4}
+	repo.On("CreateCourse", normalized).Return(int64(3), nil)
+	repo.On("GetCourseByID", int64(3)).Return(model.Course{ID: 3, Code: "CS101"}, nil)
+
+	course, err := svc.CreateCourse(model.Course{Code: " cs101 ", Name: "Data Structures ", DepartmentID: 4})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), course.ID)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateSectionValidation(t *testing.T) {
+	repo := &mockCourseRepository{}
+	svc := newCourseService(repo, &mockStudentRepository{})
+
+	_, err := svc.CreateSection(model.Section{Name: "  "})
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Contains(t, validationErr.Fields, "course_id")
+	assert.Contains(t, validationErr.Fields, "name")
+	repo.AssertNotCalled(t, "CreateSection", mock.Anything)
+}
+
+func TestCheckEnrolled(t *testing.T) {
+	repo := &mockCourseRepository{}
+	svc := newCourseService(repo, &mockStudentRepository{})
+
+	repo.On("EnrolledStudents", int64(2), []int64{7}).Return(map[int64]bool{7: true}, nil)
+	repo.On("EnrolledStudents", int64(2), []int64{8}).Return(map[int64]bool{}, nil)
+
+	assert.NoError(t, svc.CheckEnrolled(2, 7))
+	assert.ErrorIs(t, svc.CheckEnrolled(2, 8), ErrNotEnrolled)
+}
+
+func TestSectionReportUnknownSection(t *testing.T) {
+	repo := &mockCourseRepository{}
+	svc := newCourseService(repo, &mockStudentRepository{})
+	svc.report = func(model.ReportScope, string, string) (model.AttendanceReports, error) {
+		t.Fatal("report must not run for an unknown section")
+		return nil, nil
+	}
+
+	repo.On("GetSectionByID", int64(9)).Return(model.Section{}, repository.ErrSectionNotFound)
+
+	_, err := svc.SectionReport(9, "2023-10-01", "2023-10-31")
+
+	assert.ErrorIs(t, err, repository.ErrSectionNotFound)
+}
+
+func TestSectionReportValidatesRange(t *testing.T) {
+	svc := newCourseService(&mockCourseRepository{}, &mockStudentRepository{})
+
+	_, err := svc.SectionReport(1, "2023-10-31", "2023-10-01")
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Contains(t, validationErr.Fields, "to")
+}
+
+func TestMarkAttendanceBulkRejectsStudentsOutsideSection(t *testing.T) {
+	courses := &mockCourseRepository{}
+	originalCourses := courseSvc
+	courseSvc = newCourseService(courses, &mockStudentRepository{})
+	t.Cleanup(func() { courseSvc = originalCourses })
+
+	leaves := &mockLeaveRepository{}
+	originalLeave := leaveSvc
+	leaveSvc = newTestLeaveService(leaves, &mockStudentRepository{})
+	t.Cleanup(func() { leaveSvc = originalLeave })
+
+	courses.On("EnrolledStudents", int64(2), []int64{1, 3}).Return(map[int64]bool{3: true}, nil)
+	leaves.On("GetStudentsOnLeave", "2023-10-30", []int64{1, 3}).Return(map[int64]bool{3: true}, nil)
+
+	results, err := markAttendanceBulk(model.BulkAttendanceRequest{
+		Date:      "2023-10-30",
+		SessionID: 5,
+		Entries: []model.BulkAttendanceEntry{
+			{StudentID: 1, Status: "Present"},
+			{StudentID: 3, Status: "Present"},
+		},
+	}, 2, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.BulkAttendanceResult{
+		{StudentID: 1, Status: "Present", Result: model.BulkResultNotEnrolled},
+		{StudentID: 3, Status: "Present", Result: model.BulkResultOnLeave},
+	}, results)
+}
+
+func TestSectionReportOutsideTeacherDepartments(t *testing.T) {
+	withTeacher(t)
+	courses := &mockCourseRepository{}
+	svc := newCourseService(courses, &mockStudentRepository{})
+	svc.report = func(model.ReportScope, string, string) (model.AttendanceReports, error) { return nil, nil }
+	original := courseSvc
+	courseSvc = svc
+	t.Cleanup(func() { courseSvc = original })
+
+	courses.On("GetSectionByID", int64(7)).Return(model.Section{ID: 7, CourseID: 10}, nil)
+	courses.On("GetCourseByID", int64(10)).Return(model.Course{ID: 10, DepartmentID: 5}, nil)
+	courses.On("GetSectionByID", int64(8)).Return(model.Section{ID: 8, CourseID: 11}, nil)
+	courses.On("GetCourseByID", int64(11)).Return(model.Course{ID: 11, DepartmentID: 3}, nil)
+
+	rr, resp := performTeacherRequest(getSectionReport, http.MethodGet, "/sections/7/report?from=2024-01-01&to=2024-01-31", gin.Params{{Key: "id", Value: "7"}}, nil)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, ErrOutsideDepartments.Error(), resp["error"])
+
+	rr, _ = performTeacherRequest(getSectionReport, http.MethodGet, "/sections/8/report?from=2024-01-01&to=2024-01-31", gin.Params{{Key: "id", Value: "8"}}, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
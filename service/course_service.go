@@ -0,0 +1,563 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutesCourse registers the course, section and enrollment routes
+func RoutesCourse(rg *gin.RouterGroup) {
+	course := rg.Group("/courses")
+
+	course.POST("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCoursesWrite), createCourse)
+	course.GET("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCoursesRead), getCourses)
+	course.GET("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCoursesRead), getCourseByID)
+	course.PUT("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCoursesWrite), updateCourse)
+	course.DELETE("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCoursesWrite), deleteCourse)
+
+	section := rg.Group("/sections")
+
+	section.POST("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCoursesWrite), createSection)
+	section.GET("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCoursesRead), getSections)
+	section.GET("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCoursesRead), getSectionByID)
+	section.PUT("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCoursesWrite), updateSection)
+	section.DELETE("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCoursesWrite), deleteSection)
+	section.GET("/:id/enrollments", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCoursesRead), getEnrollments)
+	section.POST("/:id/enrollments", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCoursesWrite), enrollStudents)
+	section.DELETE("/:id/enrollments/:student_id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCoursesWrite), unenrollStudent)
+	section.GET("/:id/report", util.TokenAuthMiddleware(), util.RequirePermission(model.PermReportsRead), getSectionReport)
+
+	rg.GET("/students/:id/sections", util.TokenAuthMiddleware(), util.RequirePermission(model.PermCoursesRead), getStudentSections)
+}
+
+// createCourse godoc
+// @Summary Create a course
+// @Description Create a course. Codes are stored upper-case and must be unique.
+// @Tags Courses
+// @Accept  json
+// @Produce  json
+// @Param course body model.Course true "Course"
+// @Success 201 {object} model.Course
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /courses/ [post]
+func createCourse(c *gin.Context) {
+	var course model.Course
+	if err := c.ShouldBindJSON(&course); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := courseSvc.CreateCourse(course)
+	if err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// getCourses godoc
+// @Summary List courses
+// @Description Get every course ordered by code
+// @Tags Courses
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} model.Course
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /courses/ [get]
+func getCourses(c *gin.Context) {
+	courses, err := courseSvc.GetCourses()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch courses"})
+		return
+	}
+
+	c.JSON(http.StatusOK, courses)
+}
+
+// getCourseByID godoc
+// @Summary Get a course
+// @Description Get a course by ID
+// @Tags Courses
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Course ID"
+// @Success 200 {object} model.Course
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /courses/{id} [get]
+func getCourseByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	course, err := courseSvc.GetCourseByID(id)
+	if err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, course)
+}
+
+// updateCourse godoc
+// @Summary Update a course
+// @Description Change the code, name or department of a course
+// @Tags Courses
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Course ID"
+// @Param course body model.Course true "Course"
+// @Success 200 {object} model.Course
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /courses/{id} [put]
+func updateCourse(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var course model.Course
+	if err := c.ShouldBindJSON(&course); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := courseSvc.UpdateCourse(id, course)
+	if err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// deleteCourse godoc
+// @Summary Delete a course
+// @Description Delete a course. Courses that still have sections cannot be deleted.
+// @Tags Courses
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Course ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /courses/{id} [delete]
+func deleteCourse(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := courseSvc.DeleteCourse(id); err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Course deleted successfully"})
+}
+
+// createSection godoc
+// @Summary Create a section
+// @Description Create a section of a course. Section names are unique within a course.
+// @Tags Courses
+// @Accept  json
+// @Produce  json
+// @Param section body model.Section true "Section"
+// @Success 201 {object} model.Section
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /sections/ [post]
+func createSection(c *gin.Context) {
+	var section model.Section
+	if err := c.ShouldBindJSON(&section); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := courseSvc.CreateSection(section)
+	if err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// getSections godoc
+// @Summary List sections
+// @Description Get the sections of every course, or of one course with course_id
+// @Tags Courses
+// @Accept  json
+// @Produce  json
+// @Param course_id query int false "Course ID"
+// @Success 200 {array} model.Section
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /sections/ [get]
+func getSections(c *gin.Context) {
+	var courseID int64
+	if raw := c.Query("course_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course_id"})
+			return
+		}
+		courseID = id
+	}
+
+	sections, err := courseSvc.GetSections(courseID)
+	if err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sections)
+}
+
+// getSectionByID godoc
+// @Summary Get a section
+// @Description Get a section by ID
+// @Tags Courses
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Section ID"
+// @Success 200 {object} model.Section
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /sections/{id} [get]
+func getSectionByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	section, err := courseSvc.GetSectionByID(id)
+	if err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, section)
+}
+
+// updateSection godoc
+// @Summary Update a section
+// @Description Rename a section or move it to another course
+// @Tags Courses
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Section ID"
+// @Param section body model.Section true "Section"
+// @Success 200 {object} model.Section
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /sections/{id} [put]
+func updateSection(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var section model.Section
+	if err := c.ShouldBindJSON(&section); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := courseSvc.UpdateSection(id, section)
+	if err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// deleteSection godoc
+// @Summary Delete a section
+// @Description Delete a section together with its enrollments. Sections that sessions are scheduled for cannot be deleted.
+// @Tags Courses
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Section ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /sections/{id} [delete]
+func deleteSection(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := courseSvc.DeleteSection(id); err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Section deleted successfully"})
+}
+
+// getEnrollments godoc
+// @Summary List section enrollments
+// @Description Get the students enrolled in a section ordered by name
+// @Tags Courses
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Section ID"
+// @Success 200 {array} model.Enrollment
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /sections/{id}/enrollments [get]
+func getEnrollments(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	enrollments, err := courseSvc.GetEnrollments(id)
+	if err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollments)
+}
+
+// enrollStudents godoc
+// @Summary Enroll students in a section
+// @Description Enroll students in a section. Students already enrolled are counted, and unknown students are reported instead of failing the request. A student can be enrolled in any number of sections.
+// @Tags Courses
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Section ID"
+// @Param enrollment body model.EnrollmentRequest true "Students to enroll"
+// @Success 200 {object} model.EnrollmentResult
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /sections/{id}/enrollments [post]
+func enrollStudents(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req model.EnrollmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := courseSvc.EnrollStudents(id, req.StudentIDs)
+	if err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// unenrollStudent godoc
+// @Summary Remove a student from a section
+// @Description Unenroll a student from a section. Attendance already marked for the section's sessions is kept.
+// @Tags Courses
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Section ID"
+// @Param student_id path int true "Student ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /sections/{id}/enrollments/{student_id} [delete]
+func unenrollStudent(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	studentID, err := strconv.ParseInt(c.Param("student_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+
+	if err := courseSvc.UnenrollStudent(id, studentID); err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Student unenrolled successfully"})
+}
+
+// getSectionReport godoc
+// @Summary Section attendance report
+// @Description Aggregate the attendance of a section's enrolled students over the section's own sessions in a date range, following REPORT.ROLLUP like the scheduled reports. Teachers can only report on sections of courses in their own departments.
+// @Tags Courses
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Section ID"
+// @Param from query string true "Start date (YYYY-MM-DD)"
+// @Param to query string true "End date (YYYY-MM-DD)"
+// @Success 200 {array} model.AttendanceReport
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /sections/{id}/report [get]
+func getSectionReport(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if !authorizeSection(c, id) {
+		return
+	}
+
+	reports, err := courseSvc.SectionReport(id, c.Query("from"), c.Query("to"))
+	if err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+// getStudentSections godoc
+// @Summary List a student's sections
+// @Description Get the sections a student is enrolled in
+// @Tags Students
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Student ID"
+// @Success 200 {array} model.Section
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /students/{id}/sections [get]
+func getStudentSections(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	sections, err := courseSvc.GetStudentSections(id)
+	if err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sections)
+}
+
+// authorizeSection answers 403 and returns false when a restricted caller
+// asks for a section whose course belongs to none of their departments
+func authorizeSection(c *gin.Context, sectionID int64) bool {
+	scope, ok := callerScope(c)
+	if !ok {
+		return false
+	}
+	if !scope.restricted {
+		return true
+	}
+
+	section, err := courseSvc.GetSectionByID(sectionID)
+	if err != nil {
+		handleCourseError(c, err)
+		return false
+	}
+	course, err := courseSvc.GetCourseByID(section.CourseID)
+	if err != nil {
+		handleCourseError(c, err)
+		return false
+	}
+	if !scope.allows(course.DepartmentID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": ErrOutsideDepartments.Error()})
+		return false
+	}
+	return true
+}
+
+func handleCourseError(c *gin.Context, err error) {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error(), "details": validationErr.Fields})
+	case errors.Is(err, repository.ErrCourseNotFound), errors.Is(err, repository.ErrSectionNotFound),
+		errors.Is(err, repository.ErrEnrollmentNotFound), errors.Is(err, repository.ErrStudentNotFound),
+		errors.Is(err, repository.ErrDepartmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrDuplicateCourse), errors.Is(err, repository.ErrDuplicateSection),
+		errors.Is(err, repository.ErrCourseInUse), errors.Is(err, repository.ErrSectionInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"strings"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+)
+
+// DepartmentService describes the department operations the HTTP layer
+// relies on.
+type DepartmentService interface {
+	CreateDepartment(department model.Department) (model.Department, error)
+	GetDepartments() (model.Departments, error)
+	GetDepartmentByID(id int64) (model.Department, error)
+	UpdateDepartment(id int64, department model.Department) (model.Department, error)
+	DeleteDepartment(id int64) error
+	DepartmentReport(departmentID int64, from, to string) (model.AttendanceReports, error)
+}
+
+type departmentService struct {
+	repo   repository.DepartmentRepository
+	report func(scope model.ReportScope, from, to string) (model.AttendanceReports, error)
+}
+
+var departmentSvc DepartmentService = newDepartmentService(repository.DepartmentRepo)
+
+func newDepartmentService(repo repository.DepartmentRepository) *departmentService {
+	return &departmentService{repo: repo, report: fetchAttendanceReport}
+}
+
+func (s *departmentService) CreateDepartment(department model.Department) (model.Department, error) {
+	department, err := normalizeDepartmentInput(department)
+	if err != nil {
+		return model.Department{}, err
+	}
+
+	id, err := s.repo.CreateDepartment(department)
+	if err != nil {
+		return model.Department{}, err
+	}
+
+	return s.repo.GetDepartmentByID(id)
+}
+
+func (s *departmentService) GetDepartments() (model.Departments, error) {
+	return s.repo.GetDepartments()
+}
+
+func (s *departmentService) GetDepartmentByID(id int64) (model.Department, error) {
+	return s.repo.GetDepartmentByID(id)
+}
+
+func (s *departmentService) UpdateDepartment(id int64, department model.Department) (model.Department, error) {
+	department, err := normalizeDepartmentInput(department)
+	if err != nil {
+		return model.Department{}, err
+	}
+
+	if err := s.repo.UpdateDepartment(id, department); err != nil {
+		return model.Department{}, err
+	}
+
+	return s.repo.GetDepartmentByID(id)
+}
+
+func (s *departmentService) DeleteDepartment(id int64) error {
+	return s.repo.DeleteDepartment(id)
+}
+
+// DepartmentReport aggregates the attendance of a department's students over
+// a date range
+func (s *departmentService) DepartmentReport(departmentID int64, from, to string) (model.AttendanceReports, error) {
+	if err := validateDateRange(from, to); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetDepartmentByID(departmentID); err != nil {
+		return nil, err
+	}
+
+	reports, err := s.report(model.ReportScope{DepartmentID: departmentID}, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if reports == nil {
+		reports = model.AttendanceReports{}
+	}
+	return reports, nil
+}
+
+// normalizeDepartmentInput trims the fields, upper-cases the code and drops
+// blank or repeated aliases and aliases that merely repeat the name or code
+func normalizeDepartmentInput(department model.Department) (model.Department, error) {
+	department.Code = strings.ToUpper(strings.TrimSpace(department.Code))
+	department.Name = strings.TrimSpace(department.Name)
+
+	seen := map[string]bool{
+		strings.ToLower(department.Code): true,
+		strings.ToLower(department.Name): true,
+	}
+	aliases := make([]string, 0, len(department.Aliases))
+	for _, alias := range department.Aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" || seen[strings.ToLower(alias)] {
+			continue
+		}
+		seen[strings.ToLower(alias)] = true
+		aliases = append(aliases, alias)
+	}
+	department.Aliases = aliases
+
+	issues := make(map[string]string)
+	if department.Code == "" {
+		issues["code"] = "code is required"
+	}
+	if department.Name == "" {
+		issues["name"] = "name is required"
+	}
+
+	if len(issues) > 0 {
+		return department, &ValidationError{Fields: issues}
+	}
+
+	return department, nil
+}
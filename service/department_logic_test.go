@@ -0,0 +1,126 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockDepartmentRepository struct {
+	mock.Mock
+}
+
+func (m *mockDepartmentRepository) CreateDepartment(department model.Department) (int64, error) {
+	args := m.Called(department)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockDepartmentRepository) GetDepartments() (model.Departments, error) {
+	args := m.Called()
+	return args.Get(0).(model.Departments), args.Error(1)
+}
+
+func (m *mockDepartmentRepository) GetDepartmentByID(id int64) (model.Department, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Department), args.Error(1)
+}
+
+func (m *mockDepartmentRepository) UpdateDepartment(id int64, department model.Department) error {
+	args := m.Called(id, department)
+	return args.Error(0)
+}
+
+func (m *mockDepartmentRepository) DeleteDepartment(id int64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *mockDepartmentRepository) ResolveDepartment(name string) (model.Department, error) {
+	args := m.Called(name)
+	return args.Get(0).(model.Department), args.Error(1)
+}
+
+func TestDepartmentServiceCreateNormalizesInput(t *testing.T) {
+	repo := &mockDepartmentRepository{}
+	svc := newDepartmentService(repo)
+
+	stored := model.Department{Code: "CS", Name: "Computer Science", Aliases: []string{"Comp Sci"}}
+	repo.On("CreateDepartment", stored).Return(int64(5), nil).Once()
+	repo.On("GetDepartmentByID", int64(5)).Return(model.Department{ID: 5, Code: "CS", Name: "Computer Science", Aliases: []string{"Comp Sci"}}, nil).Once()
+
+	created, err := svc.CreateDepartment(model.Department{
+		Code:    " cs ",
+		Name:    "Computer Science ",
+		Aliases: []string{"Comp Sci", " comp sci", "", "CS", "computer science"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), created.ID)
+	repo.AssertExpectations(t)
+}
+
+func TestDepartmentServiceCreateRequiresCodeAndName(t *testing.T) {
+	repo := &mockDepartmentRepository{}
+	svc := newDepartmentService(repo)
+
+	_, err := svc.CreateDepartment(model.Department{Code: " ", Name: ""})
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Contains(t, validationErr.Fields, "code")
+	assert.Contains(t, validationErr.Fields, "name")
+	repo.AssertNotCalled(t, "CreateDepartment", mock.Anything)
+}
+
+func TestDepartmentServiceReport(t *testing.T) {
+	repo := &mockDepartmentRepository{}
+	svc := newDepartmentService(repo)
+
+	var gotScope model.ReportScope
+	svc.report = func(scope model.ReportScope, from, to string) (model.AttendanceReports, error) {
+		gotScope = scope
+		return nil, nil
+	}
+	repo.On("GetDepartmentByID", int64(2)).Return(model.Department{ID: 2}, nil).Once()
+	repo.On("GetDepartmentByID", int64(9)).Return(model.Department{}, repository.ErrDepartmentNotFound).Once()
+
+	reports, err := svc.DepartmentReport(2, "2024-01-01", "2024-01-31")
+	assert.NoError(t, err)
+	assert.Equal(t, model.AttendanceReports{}, reports)
+	assert.Equal(t, model.ReportScope{DepartmentID: 2}, gotScope)
+
+	_, err = svc.DepartmentReport(9, "2024-01-01", "2024-01-31")
+	assert.ErrorIs(t, err, repository.ErrDepartmentNotFound)
+
+	_, err = svc.DepartmentReport(2, "2024-02-01", "2024-01-31")
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	repo.AssertExpectations(t)
+}
+
+func TestDepartmentReportOutsideTeacherDepartments(t *testing.T) {
+	withTeacher(t)
+	repo := &mockDepartmentRepository{}
+	svc := newDepartmentService(repo)
+	svc.report = func(model.ReportScope, string, string) (model.AttendanceReports, error) { return nil, nil }
+	original := departmentSvc
+	departmentSvc = svc
+	t.Cleanup(func() { departmentSvc = original })
+
+	repo.On("GetDepartmentByID", int64(3)).Return(model.Department{ID: 3}, nil).Once()
+
+	rr, resp := performTeacherRequest(getDepartmentReport, http.MethodGet, "/departments/5/report?from=2024-01-01&to=2024-01-31", gin.Params{{Key: "id", Value: "5"}}, nil)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, ErrOutsideDepartments.Error(), resp["error"])
+
+	rr, _ = performTeacherRequest(getDepartmentReport, http.MethodGet, "/departments/3/report?from=2024-01-01&to=2024-01-31", gin.Params{{Key: "id", Value: "3"}}, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutesDepartment registers the department routes
+func RoutesDepartment(rg *gin.RouterGroup) {
+	department := rg.Group("/departments")
+
+	department.POST("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermDepartmentsWrite), createDepartment)
+	department.GET("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermDepartmentsRead), getDepartments)
+	department.GET("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermDepartmentsRead), getDepartmentByID)
+	department.PUT("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermDepartmentsWrite), updateDepartment)
+	department.DELETE("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermDepartmentsWrite), deleteDepartment)
+	department.GET("/:id/report", util.TokenAuthMiddleware(), util.RequirePermission(model.PermReportsRead), getDepartmentReport)
+}
+
+// createDepartment godoc
+// @Summary Create a department
+// @Description Create a department. Codes are stored upper-case; the code, name and aliases must all be unique.
+// @Tags Departments
+// @Accept  json
+// @Produce  json
+// @Param department body model.Department true "Department"
+// @Success 201 {object} model.Department
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /departments/ [post]
+func createDepartment(c *gin.Context) {
+	var department model.Department
+	if err := c.ShouldBindJSON(&department); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := departmentSvc.CreateDepartment(department)
+	if err != nil {
+		handleDepartmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// getDepartments godoc
+// @Summary List departments
+// @Description Get every department with its aliases, ordered by code
+// @Tags Departments
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} model.Department
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /departments/ [get]
+func getDepartments(c *gin.Context) {
+	departments, err := departmentSvc.GetDepartments()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch departments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, departments)
+}
+
+// getDepartmentByID godoc
+// @Summary Get a department
+// @Description Get a department by ID
+// @Tags Departments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Department ID"
+// @Success 200 {object} model.Department
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /departments/{id} [get]
+func getDepartmentByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	department, err := departmentSvc.GetDepartmentByID(id)
+	if err != nil {
+		handleDepartmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, department)
+}
+
+// updateDepartment godoc
+// @Summary Update a department
+// @Description Change the code, name or aliases of a department. The alias list replaces the stored one.
+// @Tags Departments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Department ID"
+// @Param department body model.Department true "Department"
+// @Success 200 {object} model.Department
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /departments/{id} [put]
+func updateDepartment(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var department model.Department
+	if err := c.ShouldBindJSON(&department); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := departmentSvc.UpdateDepartment(id, department)
+	if err != nil {
+		handleDepartmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// deleteDepartment godoc
+// @Summary Delete a department
+// @Description Delete a department that has no students or courses left
+// @Tags Departments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Department ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /departments/{id} [delete]
+func deleteDepartment(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := departmentSvc.DeleteDepartment(id); err != nil {
+		handleDepartmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Department deleted successfully"})
+}
+
+// getDepartmentReport godoc
+// @Summary Department attendance report
+// @Description Aggregate the attendance of a department's students in a date range, following REPORT.ROLLUP like the scheduled reports. Teachers can only report on their own departments.
+// @Tags Departments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Department ID"
+// @Param from query string true "Start date (YYYY-MM-DD)"
+// @Param to query string true "End date (YYYY-MM-DD)"
+// @Success 200 {array} model.AttendanceReport
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /departments/{id}/report [get]
+func getDepartmentReport(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	scope, ok := callerScope(c)
+	if !ok {
+		return
+	}
+	if !scope.allows(id) {
+		c.JSON(http.StatusForbidden, gin.H{"error": ErrOutsideDepartments.Error()})
+		return
+	}
+
+	reports, err := departmentSvc.DepartmentReport(id, c.Query("from"), c.Query("to"))
+	if err != nil {
+		handleDepartmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+func handleDepartmentError(c *gin.Context, err error) {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error(), "details": validationErr.Fields})
+	case errors.Is(err, repository.ErrDepartmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrDuplicateDepartment), errors.Is(err, repository.ErrDepartmentInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
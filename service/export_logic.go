@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+)
+
+// Supported export formats
+const (
+	exportFormatCSV   = "csv"
+	exportFormatJSONL = "jsonl"
+)
+
+// ErrInvalidExportFormat is returned for an unsupported export format.
+var ErrInvalidExportFormat = errors.New("format must be csv or jsonl")
+
+// exportContentTypes maps each export format to its response content type
+var exportContentTypes = map[string]string{
+	exportFormatCSV:   "text/csv; charset=utf-8",
+	exportFormatJSONL: "application/x-ndjson",
+}
+
+// utf8BOM tells spreadsheet applications the CSV is UTF-8
+const utf8BOM = "\uFEFF"
+
+var exportColumns = []string{"attendance_id", "student_id", "student_name", "student_email", "department", "date", "session_id", "status"}
+
+// exportAttendanceRows streams the joined attendance rows; tests replace it
+var exportAttendanceRows = repository.ExportAttendance
+
+// exportEncoder writes an export one row at a time
+type exportEncoder interface {
+	header() error
+	row(r model.AttendanceExportRow) error
+	flush() error
+}
+
+func newExportEncoder(format string, w io.Writer) (exportEncoder, error) {
+	switch format {
+	case exportFormatCSV:
+		writer := csv.NewWriter(w)
+		writer.UseCRLF = true
+		return &csvExportEncoder{w: w, csv: writer}, nil
+	case exportFormatJSONL:
+		return &jsonlExportEncoder{enc: json.NewEncoder(w)}, nil
+	}
+	return nil, ErrInvalidExportFormat
+}
+
+func validateExportFilter(filter model.AttendanceExportFilter, format string) error {
+	if _, ok := exportContentTypes[format]; !ok {
+		return ErrInvalidExportFormat
+	}
+	return validateDateRange(filter.From, filter.To)
+}
+
+// exportAttendance writes the export to w. start is called just before the
+// first byte is written, so a query that fails up front can still be
+// answered with an error status.
+func exportAttendance(ctx context.Context, filter model.AttendanceExportFilter, format string, w io.Writer, start func()) error {
+	enc, err := newExportEncoder(format, w)
+	if err != nil {
+		return err
+	}
+
+	begun := false
+	begin := func() error {
+		begun = true
+		start()
+		return enc.header()
+	}
+
+	err = exportAttendanceRows(ctx, filter, func(r model.AttendanceExportRow) error {
+		if !begun {
+			if err := begin(); err != nil {
+				return err
+			}
+		}
+		return enc.row(r)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !begun {
+		if err := begin(); err != nil {
+			return err
+		}
+	}
+	return enc.flush()
+}
+
+// csvExportEncoder writes CSV that spreadsheet applications open directly:
+// a UTF-8 byte order mark, CRLF line endings, and text cells that would be
+// read as formulas prefixed with an apostrophe.
+type csvExportEncoder struct {
+	w   io.Writer
+	csv *csv.Writer
+}
+
+func (e *csvExportEncoder) header() error {
+	if _, err := io.WriteString(e.w, utf8BOM); err != nil {
+		return err
+	}
+	return e.csv.Write(exportColumns)
+}
+
+func (e *csvExportEncoder) row(r model.AttendanceExportRow) error {
+	return e.csv.Write([]string{
+		strconv.FormatInt(r.AttendanceID, 10),
+		strconv.FormatInt(r.StudentID, 10),
+		spreadsheetSafe(r.StudentName),
+		spreadsheetSafe(r.StudentEmail),
+		spreadsheetSafe(r.Department),
+		r.Date,
+		strconv.FormatInt(r.SessionID, 10),
+		r.Status,
+	})
+}
+
+func (e *csvExportEncoder) flush() error {
+	e.csv.Flush()
+	return e.csv.Error()
+}
+
+// spreadsheetSafe keeps free text from being evaluated as a formula
+func spreadsheetSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+type jsonlExportEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *jsonlExportEncoder) header() error { return nil }
+
+func (e *jsonlExportEncoder) row(r model.AttendanceExportRow) error { return e.enc.Encode(r) }
+
+func (e *jsonlExportEncoder) flush() error { return nil }
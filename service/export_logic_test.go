@@ -0,0 +1,82 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withExportRows(t *testing.T, rows []model.AttendanceExportRow, err error) {
+	original := exportAttendanceRows
+	t.Cleanup(func() { exportAttendanceRows = original })
+
+	exportAttendanceRows = func(_ context.Context, _ model.AttendanceExportFilter, emit func(model.AttendanceExportRow) error) error {
+		if err != nil {
+			return err
+		}
+		for _, r := range rows {
+			if err := emit(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+var exportFilter = model.AttendanceExportFilter{From: "2023-10-01", To: "2023-10-31"}
+
+func TestExportAttendanceCSV(t *testing.T) {
+	withExportRows(t, []model.AttendanceExportRow{
+		{AttendanceID: 7, StudentID: 1, StudentName: "=HYPERLINK(\"x\")", StudentEmail: "ada@example.com", Department: "Maths", Date: "2023-10-02", Status: "Present"},
+	}, nil)
+
+	var buf bytes.Buffer
+	started := false
+	err := exportAttendance(context.Background(), exportFilter, exportFormatCSV, &buf, func() { started = true })
+
+	assert.NoError(t, err)
+	assert.True(t, started)
+	assert.Equal(t, utf8BOM+
+		"attendance_id,student_id,student_name,student_email,department,date,session_id,status\r\n"+
+		"7,1,\"'=HYPERLINK(\"\"x\"\")\",ada@example.com,Maths,2023-10-02,0,Present\r\n", buf.String())
+}
+
+func TestExportAttendanceJSONL(t *testing.T) {
+	withExportRows(t, []model.AttendanceExportRow{
+		{AttendanceID: 7, StudentID: 1, Date: "2023-10-02", Status: "Present"},
+		{AttendanceID: 8, StudentID: 2, Date: "2023-10-02", Status: "Absent"},
+	}, nil)
+
+	var buf bytes.Buffer
+	err := exportAttendance(context.Background(), exportFilter, exportFormatJSONL, &buf, func() {})
+
+	assert.NoError(t, err)
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+	assert.Contains(t, string(lines[1]), `"attendance_id":8`)
+}
+
+func TestExportAttendanceQueryErrorBeforeStart(t *testing.T) {
+	withExportRows(t, nil, errors.New("connection refused"))
+
+	var buf bytes.Buffer
+	started := false
+	err := exportAttendance(context.Background(), exportFilter, exportFormatCSV, &buf, func() { started = true })
+
+	assert.Error(t, err)
+	assert.False(t, started)
+	assert.Empty(t, buf.String())
+}
+
+func TestValidateExportFilter(t *testing.T) {
+	assert.ErrorIs(t, validateExportFilter(exportFilter, "xlsx"), ErrInvalidExportFormat)
+
+	var validationErr *ValidationError
+	assert.ErrorAs(t, validateExportFilter(model.AttendanceExportFilter{From: "2023-10-31", To: "2023-10-01"}, exportFormatCSV), &validationErr)
+	assert.NoError(t, validateExportFilter(exportFilter, exportFormatJSONL))
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+
+	"github.com/spf13/viper"
+)
+
+// defaultImportBatchSize is used when IMPORT.BATCH_SIZE is unset
+const defaultImportBatchSize = 500
+
+// ErrImportHeader is returned when a CSV header row lacks a required column.
+var ErrImportHeader = errors.New("CSV header must name the student, date and status columns")
+
+// importColumns maps accepted header names to the column they identify
+var importColumns = map[string]string{
+	"student":       "student",
+	"student_id":    "student",
+	"student_email": "student",
+	"email":         "student",
+	"date":          "date",
+	"status":        "status",
+}
+
+// attendanceImporter streams whole-day attendance from CSV lines of student
+// (email or numeric id), date and status. Valid lines are inserted in batches
+// unless dryRun is set; rejected lines are reported with their line number.
+// Lines dated inside the attendance lock are rejected unless userID may
+// override it; those are then inserted one by one so that every new record
+// is written to the override log.
+type attendanceImporter struct {
+	students  repository.StudentRepository
+	insert    func(records model.Attendances) (model.Attendances, error)
+	insertOne func(record model.Attendance) (int64, error)
+	locks     LockService
+	userID    int64
+	batchSize int
+	dryRun    bool
+
+	byEmail    map[string]int64
+	byID       map[int64]bool
+	overridden map[string]bool
+	imported   map[int64]bool
+}
+
+func newAttendanceImporter(dryRun bool, userID int64) *attendanceImporter {
+	batchSize := defaultImportBatchSize
+	if viper.IsSet("IMPORT.BATCH_SIZE") && viper.GetInt("IMPORT.BATCH_SIZE") > 0 {
+		batchSize = viper.GetInt("IMPORT.BATCH_SIZE")
+	}
+
+	return &attendanceImporter{
+		students:   repository.StudentRepo,
+		insert:     repository.ImportAttendanceBatch,
+		insertOne:  repository.ImportAttendanceRecord,
+		locks:      lockSvc,
+		userID:     userID,
+		batchSize:  batchSize,
+		dryRun:     dryRun,
+		byEmail:    make(map[string]int64),
+		byID:       make(map[int64]bool),
+		overridden: make(map[string]bool),
+		imported:   make(map[int64]bool),
+	}
+}
+
+// Import reads the CSV to the end. A header row is optional; without one the
+// columns are taken as student, date, status. On a database error the batches
+// already inserted stay committed and the partial result is returned.
+func (im *attendanceImporter) Import(r io.Reader) (model.ImportResult, error) {
+	result := model.ImportResult{DryRun: im.dryRun, Errors: []model.ImportError{}}
+
+	// spreadsheet applications, and our own export, start the file with a
+	// byte order mark that would otherwise stick to the first column name
+	br := bufio.NewReader(r)
+	if bom, _ := br.Peek(len(utf8BOM)); string(bom) == utf8BOM {
+		br.Discard(len(utf8BOM))
+	}
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns := map[string]int{"student": 0, "date": 1, "status": 2}
+	first := true
+	batch := make(model.Attendances, 0, im.batchSize)
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			result.Total++
+			im.reject(&result, parseErr.StartLine, record, parseErr.Err.Error())
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		if first {
+			first = false
+			if header, ok, err := parseImportHeader(record); ok {
+				if err != nil {
+					return result, err
+				}
+				columns = header
+				continue
+			}
+		}
+
+		result.Total++
+		attendance, reason := im.parseLine(record, columns)
+		if reason != "" {
+			im.reject(&result, line, record, reason)
+			continue
+		}
+		if err := im.authorize(attendance.Date); errors.Is(err, ErrAttendanceLocked) {
+			im.reject(&result, line, record, err.Error())
+			continue
+		} else if err != nil {
+			return result, err
+		}
+
+		result.Valid++
+		batch = append(batch, attendance)
+		if len(batch) == im.batchSize {
+			if err := im.flush(&result, batch); err != nil {
+				return result, err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	if err := im.flush(&result, batch); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// ImportedStudents lists the students that received new records
+func (im *attendanceImporter) ImportedStudents() []int64 {
+	ids := make([]int64, 0, len(im.imported))
+	for id := range im.imported {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (im *attendanceImporter) parseLine(record []string, columns map[string]int) (model.Attendance, string) {
+	field := func(name string) string {
+		if i := columns[name]; i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	student, date, status := field("student"), field("date"), field("status")
+	if student == "" {
+		return model.Attendance{}, "student is required"
+	}
+	if err := validateISODate(date); err != nil {
+		return model.Attendance{}, err.Error()
+	}
+	if !catalogue.has(status) {
+		return model.Attendance{}, fmt.Sprintf("status %q is not a known attendance status", status)
+	}
+	if workflowStatuses[status] {
+		return model.Attendance{}, fmt.Sprintf("status %q is only set by the leave or geofence workflow", status)
+	}
+
+	studentID, err := im.resolveStudent(student)
+	if err != nil {
+		return model.Attendance{}, err.Error()
+	}
+
+	return model.Attendance{StudentID: studentID, Date: date, Status: status}, ""
+}
+
+// resolveStudent looks a student up by email, or by ID when the value is
+// numeric, caching the answer for later lines.
+func (im *attendanceImporter) resolveStudent(value string) (int64, error) {
+	if id, err := strconv.ParseInt(value, 10, 64); err == nil {
+		known, cached := im.byID[id]
+		if !cached {
+			_, err := im.students.GetStudentByID(id)
+			if err != nil && !errors.Is(err, repository.ErrStudentNotFound) {
+				return 0, err
+			}
+			known = err == nil
+			im.byID[id] = known
+		}
+		if !known {
+			return 0, repository.ErrStudentNotFound
+		}
+		return id, nil
+	}
+
+	email := strings.ToLower(value)
+	id, cached := im.byEmail[email]
+	if !cached {
+		student, err := im.students.GetStudentByEmail(value)
+		if err != nil {
+			return 0, err
+		}
+		id = student.ID
+		im.byEmail[email] = id
+	}
+	if id == 0 {
+		return 0, repository.ErrStudentNotFound
+	}
+	return id, nil
+}
+
+// authorize checks the lock for a date once per import, remembering whether
+// its lines are written under an override.
+func (im *attendanceImporter) authorize(date string) error {
+	if _, known := im.overridden[date]; known {
+		return nil
+	}
+
+	overridden, err := im.locks.Authorize(date, im.userID)
+	if err != nil {
+		return err
+	}
+	im.overridden[date] = overridden
+	return nil
+}
+
+func (im *attendanceImporter) flush(result *model.ImportResult, batch model.Attendances) error {
+	if im.dryRun || len(batch) == 0 {
+		return nil
+	}
+
+	var unlocked model.Attendances
+	for _, a := range batch {
+		if !im.overridden[a.Date] {
+			unlocked = append(unlocked, a)
+			continue
+		}
+
+		id, err := im.insertOne(a)
+		if err != nil {
+			return err
+		}
+		if id == 0 {
+			result.Duplicates++
+			continue
+		}
+		a.ID = id
+		im.locks.RecordOverride(model.LockActionMark, a, im.userID)
+		result.Imported++
+		im.imported[a.StudentID] = true
+	}
+
+	if len(unlocked) == 0 {
+		return nil
+	}
+
+	inserted, err := im.insert(unlocked)
+	if err != nil {
+		return err
+	}
+
+	result.Imported += len(inserted)
+	result.Duplicates += len(unlocked) - len(inserted)
+	for _, a := range inserted {
+		im.imported[a.StudentID] = true
+	}
+	return nil
+}
+
+func (im *attendanceImporter) reject(result *model.ImportResult, line int, record []string, reason string) {
+	result.Rejected++
+	result.Errors = append(result.Errors, model.ImportError{Line: line, Record: record, Error: reason})
+}
+
+// parseImportHeader recognises a header row by its column names. ok is false
+// when the row looks like data.
+func parseImportHeader(record []string) (columns map[string]int, ok bool, err error) {
+	columns = make(map[string]int)
+	for i, name := range record {
+		if column, known := importColumns[strings.ToLower(strings.TrimSpace(name))]; known {
+			columns[column] = i
+		}
+	}
+	if len(columns) == 0 {
+		return nil, false, nil
+	}
+	if len(columns) < 3 {
+		return nil, true, ErrImportHeader
+	}
+	return columns, true, nil
+}
+
+// writeImportErrorReport renders rejected lines as CSV: the line number, the
+// reason and the original fields.
+func writeImportErrorReport(w io.Writer, errs []model.ImportError) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"line", "error", "record"}); err != nil {
+		return err
+	}
+	for _, e := range errs {
+		row := append([]string{strconv.Itoa(e.Line), e.Error}, e.Record...)
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
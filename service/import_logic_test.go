@@ -0,0 +1,188 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestImporter(students repository.StudentRepository, dryRun bool, batches *[]model.Attendances) *attendanceImporter {
+	im := newAttendanceImporter(dryRun, 0)
+	im.students = students
+	im.batchSize = 2
+	im.insert = func(records model.Attendances) (model.Attendances, error) {
+		*batches = append(*batches, append(model.Attendances{}, records...))
+		// Pretend the first record of every batch already existed
+		return records[1:], nil
+	}
+	return im
+}
+
+func TestAttendanceImporterWithHeader(t *testing.T) {
+	students := &mockStudentRepository{}
+	students.On("GetStudentByEmail", "ada@example.com").Return(model.Student{ID: 1}, nil).Once()
+	students.On("GetStudentByEmail", "ghost@example.com").Return(model.Student{}, nil).Once()
+	students.On("GetStudentByID", int64(2)).Return(model.Student{ID: 2}, nil).Once()
+
+	csv := strings.Join([]string{
+		"date,status,email",
+		"2023-10-02,Present,ada@example.com",
+		"2023-10-03,Absent,ada@example.com",
+		"2023-10-02,Present,2",
+		"2023-10-32,Present,2",
+		"2023-10-04,Sleeping,2",
+		"2023-10-04,Present,ghost@example.com",
+		"2023-10-05,Present,",
+	}, "\n")
+
+	var batches []model.Attendances
+	im := newTestImporter(students, false, &batches)
+	result, err := im.Import(strings.NewReader(csv))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, result.Total)
+	assert.Equal(t, 3, result.Valid)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, 2, result.Duplicates)
+	assert.Equal(t, 4, result.Rejected)
+	assert.Len(t, batches, 2)
+	assert.Equal(t, model.Attendance{StudentID: 1, Date: "2023-10-02", Status: "Present"}, batches[0][0])
+
+	lines := make([]int, 0, len(result.Errors))
+	for _, e := range result.Errors {
+		lines = append(lines, e.Line)
+	}
+	assert.Equal(t, []int{5, 6, 7, 8}, lines)
+	assert.Equal(t, repository.ErrStudentNotFound.Error(), result.Errors[2].Error)
+	// student 2's only valid line was a duplicate, so it received nothing
+	assert.ElementsMatch(t, []int64{1}, im.ImportedStudents())
+	students.AssertExpectations(t)
+}
+
+func TestAttendanceImporterDryRunWithoutHeader(t *testing.T) {
+	students := &mockStudentRepository{}
+	students.On("GetStudentByID", int64(3)).Return(model.Student{}, repository.ErrStudentNotFound).Once()
+	students.On("GetStudentByID", int64(4)).Return(model.Student{ID: 4}, nil).Once()
+
+	csv := "3,2023-10-02,Present\n3,2023-10-03,Present\n4,2023-10-02,Late\n"
+
+	var batches []model.Attendances
+	result, err := newTestImporter(students, true, &batches).Import(strings.NewReader(csv))
+
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 3, result.Total)
+	assert.Equal(t, 1, result.Valid)
+	assert.Equal(t, 0, result.Imported)
+	assert.Equal(t, 2, result.Rejected)
+	assert.Empty(t, batches)
+	students.AssertExpectations(t)
+}
+
+func TestAttendanceImporterRejectsWorkflowStatuses(t *testing.T) {
+	csv := "1,2023-10-02,OnLeave\n1,2023-10-03,Flagged\n"
+
+	var batches []model.Attendances
+	result, err := newTestImporter(&mockStudentRepository{}, false, &batches).Import(strings.NewReader(csv))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Rejected)
+	assert.Empty(t, batches)
+	if assert.Len(t, result.Errors, 2) {
+		assert.Equal(t, `status "OnLeave" is only set by the leave or geofence workflow`, result.Errors[0].Error)
+		assert.Equal(t, 2, result.Errors[1].Line)
+	}
+}
+
+func TestAttendanceImporterLockedDates(t *testing.T) {
+	withLockPolicy(t, 7)
+	csv := "1,2023-10-02,Present\n2,2023-10-02,Absent\n1,2023-10-16,Present\n"
+
+	students := &mockStudentRepository{}
+	students.On("GetStudentByID", int64(1)).Return(model.Student{ID: 1}, nil)
+	students.On("GetStudentByID", int64(2)).Return(model.Student{ID: 2}, nil)
+
+	t.Run("rejected without override", func(t *testing.T) {
+		var batches []model.Attendances
+		im := newTestImporter(students, false, &batches)
+		im.locks = newTestLockService(&mockLockOverrideRepository{})
+		im.userID = 2
+
+		result, err := im.Import(strings.NewReader(csv))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Rejected)
+		assert.Equal(t, ErrAttendanceLocked.Error(), result.Errors[0].Error)
+		assert.Equal(t, []model.Attendances{{{StudentID: 1, Date: "2023-10-16", Status: "Present"}}}, batches)
+	})
+
+	t.Run("recorded with override", func(t *testing.T) {
+		repo := &mockLockOverrideRepository{}
+		repo.On("CreateOverride", model.LockOverride{
+			Action: model.LockActionMark, AttendanceID: 20, StudentID: 1, Date: "2023-10-02", Status: "Present", UserID: 1,
+		}).Return(nil).Once()
+
+		var batches []model.Attendances
+		var single model.Attendances
+		im := newTestImporter(students, false, &batches)
+		im.locks = newTestLockService(repo)
+		im.userID = 1
+		im.insertOne = func(record model.Attendance) (int64, error) {
+			single = append(single, record)
+			// Student 2 already had a record for the day
+			if record.StudentID == 2 {
+				return 0, nil
+			}
+			return 20, nil
+		}
+
+		result, err := im.Import(strings.NewReader(csv))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Rejected)
+		assert.Equal(t, 1, result.Imported)
+		assert.Equal(t, 2, result.Duplicates)
+		assert.Len(t, single, 2)
+		assert.Len(t, batches, 1)
+		assert.ElementsMatch(t, []int64{1}, im.ImportedStudents())
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestAttendanceImporterStripsByteOrderMark(t *testing.T) {
+	students := &mockStudentRepository{}
+	students.On("GetStudentByEmail", "ada@example.com").Return(model.Student{ID: 1}, nil).Once()
+
+	csv := utf8BOM + "email,date,status\nada@example.com,2023-10-02,Present\nada@example.com,2023-10-03,Late\n"
+
+	var batches []model.Attendances
+	result, err := newTestImporter(students, false, &batches).Import(strings.NewReader(csv))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Total)
+	assert.Equal(t, 0, result.Rejected)
+	assert.Equal(t, 1, result.Imported)
+	students.AssertExpectations(t)
+}
+
+func TestAttendanceImporterRejectsIncompleteHeader(t *testing.T) {
+	var batches []model.Attendances
+	_, err := newTestImporter(&mockStudentRepository{}, false, &batches).Import(strings.NewReader("email,date\n"))
+
+	assert.ErrorIs(t, err, ErrImportHeader)
+}
+
+func TestWriteImportErrorReport(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeImportErrorReport(&buf, []model.ImportError{
+		{Line: 4, Record: []string{"2", "2023-10-32", "Present"}, Error: "invalid date"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "line,error,record\n4,invalid date,2,2023-10-32,Present\n", buf.String())
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+)
+
+// ErrInvalidLeaveTransition is returned when a leave request cannot move to
+// the requested state from its current one.
+var ErrInvalidLeaveTransition = errors.New("leave request cannot change from its current state")
+
+// ErrOverlappingLeave is returned when a student already has pending or
+// approved leave overlapping the requested dates.
+var ErrOverlappingLeave = errors.New("student already has leave requested for these dates")
+
+// ErrStudentOnLeave is returned when attendance is marked for a student on
+// approved leave.
+var ErrStudentOnLeave = errors.New("student is on approved leave on this date")
+
+// ErrNotOwnLeave is returned when a user who may not decide leave requests
+// leave for, or cancels the leave of, a student other than their own record.
+var ErrNotOwnLeave = errors.New("you can only request or cancel leave for your own student record")
+
+// ErrNotLeaveRequester is returned when a user who neither submitted a leave
+// request nor may decide leave tries to cancel it.
+var ErrNotLeaveRequester = errors.New("only the requester or an approver can cancel this leave request")
+
+// maxLeaveDays caps the length of one leave request, as approval marks each
+// of its days
+const maxLeaveDays = 366
+
+// leaveTransitions lists the states each leave state may move to
+var leaveTransitions = map[string][]string{
+	model.LeaveStatePending:  {model.LeaveStateApproved, model.LeaveStateRejected, model.LeaveStateCancelled},
+	model.LeaveStateApproved: {model.LeaveStateCancelled},
+}
+
+func canTransitionLeave(from, to string) bool {
+	for _, next := range leaveTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// LeaveService describes the leave request workflow the HTTP layer and
+// attendance marking rely on.
+type LeaveService interface {
+	CreateLeaveRequest(leave model.LeaveRequest, requestedBy int64) (model.LeaveRequest, error)
+	GetLeaveRequests(filter model.LeaveRequestFilter, scope departmentScope, limit, offset int) (model.LeaveRequests, error)
+	GetLeaveRequestByID(id int64) (model.LeaveRequest, error)
+	ApproveLeaveRequest(id, decidedBy int64, note string) (model.LeaveRequest, error)
+	RejectLeaveRequest(id, decidedBy int64, note string) (model.LeaveRequest, error)
+	CancelLeaveRequest(id, cancelledBy int64, note string) (model.LeaveRequest, error)
+	StudentsOnLeave(date string, studentIDs []int64) (map[int64]bool, error)
+}
+
+type leaveService struct {
+	repo       repository.LeaveRepository
+	students   repository.StudentRepository
+	grants     func(userID int64) (model.Grants, error)
+	users      func(id int64) (model.MUser, error)
+	attendance func(studentID int64, startDate, endDate string) (model.Attendances, error)
+	notify     func(leave model.LeaveRequest, student model.Student)
+}
+
+var leaveSvc LeaveService = newLeaveService(repository.LeaveRepo, repository.StudentRepo)
+
+func newLeaveService(repo repository.LeaveRepository, students repository.StudentRepository) *leaveService {
+	return &leaveService{
+		repo:       repo,
+		students:   students,
+		grants:     repository.RoleRepo.GetUserGrants,
+		users:      repository.GetUserByID,
+		attendance: repository.GetAttendanceByDateRange,
+		notify: func(leave model.LeaveRequest, student model.Student) {
+			go util.SendLeaveRequestEmail(leave, student)
+		},
+	}
+}
+
+// CreateLeaveRequest files a pending request. Users who may not decide leave
+// can only request it for the student record of their own account.
+func (s *leaveService) CreateLeaveRequest(leave model.LeaveRequest, requestedBy int64) (model.LeaveRequest, error) {
+	if err := validateLeaveInput(leave); err != nil {
+		return model.LeaveRequest{}, err
+	}
+
+	canDecide, err := s.canDecide(requestedBy)
+	if err != nil {
+		return model.LeaveRequest{}, err
+	}
+	if !canDecide {
+		if err := s.checkOwnStudent(requestedBy, leave.StudentID); err != nil {
+			return model.LeaveRequest{}, err
+		}
+	}
+
+	student, err := s.students.GetStudentByID(leave.StudentID)
+	if err != nil {
+		return model.LeaveRequest{}, err
+	}
+
+	overlapping, err := s.repo.HasOverlappingLeave(leave.StudentID, leave.FromDate, leave.ToDate)
+	if err != nil {
+		return model.LeaveRequest{}, err
+	}
+	if overlapping {
+		return model.LeaveRequest{}, ErrOverlappingLeave
+	}
+
+	leave.RequestedBy = requestedBy
+	id, err := s.repo.CreateLeaveRequest(leave)
+	if err != nil {
+		return model.LeaveRequest{}, err
+	}
+
+	created, err := s.repo.GetLeaveRequestByID(id)
+	if err != nil {
+		return model.LeaveRequest{}, err
+	}
+
+	s.notify(created, student)
+	return created, nil
+}
+
+// GetLeaveRequests lists the leave requests of the students in scope
+func (s *leaveService) GetLeaveRequests(filter model.LeaveRequestFilter, scope departmentScope, limit, offset int) (model.LeaveRequests, error) {
+	if scope.restricted && len(scope.departments) == 0 {
+		return model.LeaveRequests{}, nil
+	}
+	filter.DepartmentIDs = scope.departments
+	return s.repo.GetLeaveRequests(filter, limit, offset)
+}
+
+func (s *leaveService) GetLeaveRequestByID(id int64) (model.LeaveRequest, error) {
+	return s.repo.GetLeaveRequestByID(id)
+}
+
+// ApproveLeaveRequest approves a pending request and marks the student
+// OnLeave on every working day of its range. Days before the lock window
+// need the attendance:override_lock permission, like any other mark.
+func (s *leaveService) ApproveLeaveRequest(id, decidedBy int64, note string) (model.LeaveRequest, error) {
+	return s.transition(id, model.LeaveStateApproved, func(leave model.LeaveRequest) error {
+		days, err := calendarSvc.WorkingDays(leave.FromDate, leave.ToDate)
+		if err != nil {
+			return err
+		}
+		overridden, err := authorizeLeaveDays(days, decidedBy)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.ApproveLeaveRequest(leave, decidedBy, note, days); err != nil {
+			return err
+		}
+
+		if len(overridden) > 0 {
+			marks, err := s.leaveMarks(leave)
+			if err != nil {
+				log.Println("Error loading leave attendance for the lock override log: " + err.Error())
+				return nil
+			}
+			recordLeaveOverrides(model.LockActionMark, marks, overridden, decidedBy)
+		}
+		return nil
+	})
+}
+
+func (s *leaveService) RejectLeaveRequest(id, decidedBy int64, note string) (model.LeaveRequest, error) {
+	return s.transition(id, model.LeaveStateRejected, func(leave model.LeaveRequest) error {
+		return s.repo.SetLeaveState(id, leave.State, model.LeaveStateRejected, decidedBy, note)
+	})
+}
+
+// CancelLeaveRequest withdraws a pending request, or an approved one together
+// with the OnLeave marks its approval created. Users who may not decide leave
+// can only cancel requests they submitted for their own student record, and
+// removing marks before the lock window needs the attendance:override_lock
+// permission.
+func (s *leaveService) CancelLeaveRequest(id, cancelledBy int64, note string) (model.LeaveRequest, error) {
+	return s.transition(id, model.LeaveStateCancelled, func(leave model.LeaveRequest) error {
+		canDecide, err := s.canDecide(cancelledBy)
+		if err != nil {
+			return err
+		}
+		if !canDecide {
+			if leave.RequestedBy != cancelledBy {
+				return ErrNotLeaveRequester
+			}
+			if err := s.checkOwnStudent(cancelledBy, leave.StudentID); err != nil {
+				return err
+			}
+		}
+
+		if leave.State != model.LeaveStateApproved {
+			return s.repo.SetLeaveState(id, leave.State, model.LeaveStateCancelled, cancelledBy, note)
+		}
+
+		marks, err := s.leaveMarks(leave)
+		if err != nil {
+			return err
+		}
+		days := make([]string, 0, len(marks))
+		for _, m := range marks {
+			days = append(days, recordDay(m))
+		}
+		overridden, err := authorizeLeaveDays(days, cancelledBy)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.CancelApprovedLeaveRequest(leave, cancelledBy, note); err != nil {
+			return err
+		}
+
+		recordLeaveOverrides(model.LockActionDelete, marks, overridden, cancelledBy)
+		return nil
+	})
+}
+
+// canDecide reports whether the user's roles grant leave:decide
+func (s *leaveService) canDecide(userID int64) (bool, error) {
+	grants, err := s.grants(userID)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(grants.Permissions, model.PermLeaveDecide), nil
+}
+
+// checkOwnStudent fails unless studentID is the student record of the user's
+// account, matched by email like QR check-in
+func (s *leaveService) checkOwnStudent(userID, studentID int64) error {
+	student, err := accountStudent(userID, s.users, s.students)
+	if err != nil {
+		return err
+	}
+	if student.ID != studentID {
+		return ErrNotOwnLeave
+	}
+	return nil
+}
+
+// leaveMarks returns the whole-day OnLeave marks within the range of a leave
+// request
+func (s *leaveService) leaveMarks(leave model.LeaveRequest) (model.Attendances, error) {
+	records, err := s.attendance(leave.StudentID, leave.FromDate, leave.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	var marks model.Attendances
+	for _, r := range records {
+		if r.SessionID == 0 && r.Status == model.StatusOnLeave {
+			marks = append(marks, r)
+		}
+	}
+	return marks, nil
+}
+
+// authorizeLeaveDays applies the attendance lock to every day a leave
+// decision writes, returning the days that were only allowed by a lock
+// override
+func authorizeLeaveDays(days []string, userID int64) (map[string]bool, error) {
+	overridden := make(map[string]bool)
+	for _, day := range days {
+		ok, err := lockSvc.Authorize(day, userID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			overridden[day] = true
+		}
+	}
+	return overridden, nil
+}
+
+// recordLeaveOverrides writes the marks on overridden days to the lock
+// override log
+func recordLeaveOverrides(action string, marks model.Attendances, overridden map[string]bool, userID int64) {
+	for _, m := range marks {
+		if overridden[recordDay(m)] {
+			lockSvc.RecordOverride(action, m, userID)
+		}
+	}
+}
+
+func (s *leaveService) StudentsOnLeave(date string, studentIDs []int64) (map[int64]bool, error) {
+	return s.repo.GetStudentsOnLeave(date, studentIDs)
+}
+
+// transition checks that the request may move to the target state, applies
+// the change and notifies about the new state.
+func (s *leaveService) transition(id int64, to string, apply func(leave model.LeaveRequest) error) (model.LeaveRequest, error) {
+	leave, err := s.repo.GetLeaveRequestByID(id)
+	if err != nil {
+		return model.LeaveRequest{}, err
+	}
+	if !canTransitionLeave(leave.State, to) {
+		return model.LeaveRequest{}, ErrInvalidLeaveTransition
+	}
+
+	if err := apply(leave); err != nil {
+		return model.LeaveRequest{}, err
+	}
+
+	updated, err := s.repo.GetLeaveRequestByID(id)
+	if err != nil {
+		return model.LeaveRequest{}, err
+	}
+
+	if student, err := s.students.GetStudentByID(updated.StudentID); err == nil {
+		s.notify(updated, student)
+	}
+	return updated, nil
+}
+
+func validateLeaveInput(leave model.LeaveRequest) error {
+	issues := make(map[string]string)
+
+	if strings.TrimSpace(leave.Reason) == "" {
+		issues["reason"] = "reason is required"
+	}
+	if err := validateISODate(leave.FromDate); err != nil {
+		issues["from_date"] = err.Error()
+	}
+	if err := validateISODate(leave.ToDate); err != nil {
+		issues["to_date"] = err.Error()
+	}
+	if len(issues) == 0 && leave.ToDate < leave.FromDate {
+		issues["to_date"] = "to_date must not be before from_date"
+	}
+	if len(issues) == 0 {
+		from, _ := time.Parse(isoDateLayout, leave.FromDate)
+		to, _ := time.Parse(isoDateLayout, leave.ToDate)
+		if to.Sub(from) >= maxLeaveDays*24*time.Hour {
+			issues["to_date"] = fmt.Sprintf("leave must not exceed %d days", maxLeaveDays)
+		}
+	}
+
+	if len(issues) > 0 {
+		return &ValidationError{Fields: issues}
+	}
+
+	return nil
+}
+
+// checkNotOnLeave rejects marks for a student on approved leave. OnLeave is
+// a workflow status clients cannot submit, so leave approval is the only way
+// to mark it.
+func checkNotOnLeave(studentID int64, date string) error {
+	onLeave, err := leaveSvc.StudentsOnLeave(date, []int64{studentID})
+	if err != nil {
+		return err
+	}
+	if onLeave[studentID] {
+		return ErrStudentOnLeave
+	}
+	return nil
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockLeaveRepository struct {
+	mock.Mock
+}
+
+func (m *mockLeaveRepository) CreateLeaveRequest(leave model.LeaveRequest) (int64, error) {
+	args := m.Called(leave)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockLeaveRepository) GetLeaveRequestByID(id int64) (model.LeaveRequest, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.LeaveRequest), args.Error(1)
+}
+
+func (m *mockLeaveRepository) GetLeaveRequests(filter model.LeaveRequestFilter, limit, offset int) (model.LeaveRequests, error) {
+	args := m.Called(filter, limit, offset)
+	leaves, _ := args.Get(0).(model.LeaveRequests)
+	return leaves, args.Error(1)
+}
+
+func (m *mockLeaveRepository) HasOverlappingLeave(studentID int64, from, to string) (bool, error) {
+	args := m.Called(studentID, from, to)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockLeaveRepository) SetLeaveState(id int64, from, to string, decidedBy int64, note string) error {
+	return m.Called(id, from, to, decidedBy, note).Error(0)
+}
+
+func (m *mockLeaveRepository) ApproveLeaveRequest(leave model.LeaveRequest, decidedBy int64, note string, days []string) error {
+	return m.Called(leave, decidedBy, note, days).Error(0)
+}
+
+func (m *mockLeaveRepository) CancelApprovedLeaveRequest(leave model.LeaveRequest, cancelledBy int64, note string) error {
+	return m.Called(leave, cancelledBy, note).Error(0)
+}
+
+func (m *mockLeaveRepository) GetStudentsOnLeave(date string, studentIDs []int64) (map[int64]bool, error) {
+	args := m.Called(date, studentIDs)
+	onLeave, _ := args.Get(0).(map[int64]bool)
+	return onLeave, args.Error(1)
+}
+
+func newTestLeaveService(repo *mockLeaveRepository, students *mockStudentRepository) *leaveService {
+	svc := newLeaveService(repo, students)
+	svc.notify = func(model.LeaveRequest, model.Student) {}
+	svc.grants = func(userID int64) (model.Grants, error) {
+		if userID == 9 {
+			return model.Grants{Roles: []string{model.RoleAdmin}, Permissions: []string{model.PermLeaveDecide}}, nil
+		}
+		return model.Grants{Roles: []string{model.RoleStudent}, Permissions: []string{model.PermLeaveRequest}}, nil
+	}
+	// every account's email is user<id>@scopex.local
+	svc.users = func(id int64) (model.MUser, error) {
+		return model.MUser{ID: id, Email: fmt.Sprintf("user%d@scopex.local", id)}, nil
+	}
+	svc.attendance = func(int64, string, string) (model.Attendances, error) { return nil, nil }
+	return svc
+}
+
+func withTestLockService(t *testing.T, svc LockService) {
+	original := lockSvc
+	lockSvc = svc
+	t.Cleanup(func() { lockSvc = original })
+}
+
+func TestLeaveServiceCreateRejectsOverlap(t *testing.T) {
+	repo := &mockLeaveRepository{}
+	students := &mockStudentRepository{}
+	svc := newTestLeaveService(repo, students)
+
+	input := model.LeaveRequest{StudentID: 1, FromDate: "2023-10-30", ToDate: "2023-11-01", Reason: "Wedding"}
+	students.On("GetStudentByEmail", "user7@scopex.local").Return(model.Student{ID: 1}, nil)
+	students.On("GetStudentByID", int64(1)).Return(model.Student{ID: 1}, nil)
+	repo.On("HasOverlappingLeave", int64(1), "2023-10-30", "2023-11-01").Return(true, nil)
+
+	_, err := svc.CreateLeaveRequest(input, 7)
+
+	assert.ErrorIs(t, err, ErrOverlappingLeave)
+	repo.AssertNotCalled(t, "CreateLeaveRequest", mock.Anything)
+}
+
+func TestLeaveServiceCreateOnlyForOwnStudent(t *testing.T) {
+	repo := &mockLeaveRepository{}
+	students := &mockStudentRepository{}
+	svc := newTestLeaveService(repo, students)
+
+	input := model.LeaveRequest{StudentID: 2, FromDate: "2023-10-30", ToDate: "2023-11-01", Reason: "Wedding"}
+	students.On("GetStudentByEmail", "user7@scopex.local").Return(model.Student{ID: 1}, nil)
+	students.On("GetStudentByEmail", "user8@scopex.local").Return(model.Student{}, nil)
+
+	_, err := svc.CreateLeaveRequest(input, 7)
+	assert.ErrorIs(t, err, ErrNotOwnLeave)
+
+	_, err = svc.CreateLeaveRequest(input, 8)
+	assert.ErrorIs(t, err, ErrNotAStudent)
+	repo.AssertNotCalled(t, "CreateLeaveRequest", mock.Anything)
+
+	// an approver files leave for any student
+	created := input
+	created.ID, created.RequestedBy, created.State = 3, 9, model.LeaveStatePending
+	students.On("GetStudentByID", int64(2)).Return(model.Student{ID: 2}, nil)
+	repo.On("HasOverlappingLeave", int64(2), "2023-10-30", "2023-11-01").Return(false, nil)
+	repo.On("CreateLeaveRequest", model.LeaveRequest{StudentID: 2, FromDate: "2023-10-30", ToDate: "2023-11-01", Reason: "Wedding", RequestedBy: 9}).Return(int64(3), nil)
+	repo.On("GetLeaveRequestByID", int64(3)).Return(created, nil)
+
+	leave, err := svc.CreateLeaveRequest(input, 9)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), leave.ID)
+	repo.AssertExpectations(t)
+}
+
+func TestLeaveServiceCreateValidatesRange(t *testing.T) {
+	svc := newTestLeaveService(&mockLeaveRepository{}, &mockStudentRepository{})
+
+	_, err := svc.CreateLeaveRequest(model.LeaveRequest{StudentID: 1, FromDate: "2023-11-01", ToDate: "2023-10-30", Reason: "Wedding"}, 7)
+
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Contains(t, validationErr.Fields, "to_date")
+
+	_, err = svc.CreateLeaveRequest(model.LeaveRequest{StudentID: 1, FromDate: "2023-01-01", ToDate: "2024-01-02", Reason: "Sabbatical"}, 7)
+
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "leave must not exceed 366 days", validationErr.Fields["to_date"])
+}
+
+func TestLeaveServiceApproveMarksWorkingDays(t *testing.T) {
+	calendar := &mockCalendarRepository{}
+	calendar.On("GetTerms").Return(model.Terms{}, nil)
+	calendar.On("GetHolidays", "2023-10-27", "2023-10-30").Return(model.Holidays{}, nil)
+	calendar.On("GetWeekendDays").Return([]time.Weekday{time.Saturday, time.Sunday}, nil)
+	withMockCalendarRepository(t, calendar)
+
+	repo := &mockLeaveRepository{}
+	students := &mockStudentRepository{}
+	svc := newTestLeaveService(repo, students)
+
+	pending := model.LeaveRequest{ID: 3, StudentID: 1, FromDate: "2023-10-27", ToDate: "2023-10-30", State: model.LeaveStatePending}
+	approved := pending
+	approved.State = model.LeaveStateApproved
+
+	repo.On("GetLeaveRequestByID", int64(3)).Return(pending, nil).Once()
+	repo.On("ApproveLeaveRequest", pending, int64(9), "ok", []string{"2023-10-27", "2023-10-30"}).Return(nil).Once()
+	repo.On("GetLeaveRequestByID", int64(3)).Return(approved, nil).Once()
+	students.On("GetStudentByID", int64(1)).Return(model.Student{ID: 1}, nil)
+
+	leave, err := svc.ApproveLeaveRequest(3, 9, "ok")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.LeaveStateApproved, leave.State)
+	repo.AssertExpectations(t)
+}
+
+func TestLeaveServiceRejectsInvalidTransitions(t *testing.T) {
+	repo := &mockLeaveRepository{}
+	svc := newTestLeaveService(repo, &mockStudentRepository{})
+
+	repo.On("GetLeaveRequestByID", int64(3)).Return(model.LeaveRequest{ID: 3, State: model.LeaveStateRejected}, nil)
+	repo.On("GetLeaveRequestByID", int64(4)).Return(model.LeaveRequest{ID: 4, State: model.LeaveStateApproved}, nil)
+
+	_, err := svc.ApproveLeaveRequest(3, 9, "")
+	assert.ErrorIs(t, err, ErrInvalidLeaveTransition)
+
+	_, err = svc.CancelLeaveRequest(3, 9, "")
+	assert.ErrorIs(t, err, ErrInvalidLeaveTransition)
+
+	_, err = svc.RejectLeaveRequest(4, 9, "")
+	assert.ErrorIs(t, err, ErrInvalidLeaveTransition)
+}
+
+func TestLeaveServiceCancelApprovedRemovesMarks(t *testing.T) {
+	repo := &mockLeaveRepository{}
+	students := &mockStudentRepository{}
+	svc := newTestLeaveService(repo, students)
+
+	approved := model.LeaveRequest{ID: 4, StudentID: 1, State: model.LeaveStateApproved, RequestedBy: 1}
+	cancelled := approved
+	cancelled.State = model.LeaveStateCancelled
+
+	repo.On("GetLeaveRequestByID", int64(4)).Return(approved, nil).Once()
+	repo.On("CancelApprovedLeaveRequest", approved, int64(1), "plans changed").Return(nil).Once()
+	repo.On("GetLeaveRequestByID", int64(4)).Return(cancelled, nil).Once()
+	students.On("GetStudentByEmail", "user1@scopex.local").Return(model.Student{ID: 1}, nil)
+	students.On("GetStudentByID", int64(1)).Return(model.Student{ID: 1}, nil)
+
+	leave, err := svc.CancelLeaveRequest(4, 1, "plans changed")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.LeaveStateCancelled, leave.State)
+	repo.AssertExpectations(t)
+}
+
+func TestLeaveServiceCancelChecksOwnership(t *testing.T) {
+	repo := &mockLeaveRepository{}
+	students := &mockStudentRepository{}
+	svc := newTestLeaveService(repo, students)
+
+	pending := model.LeaveRequest{ID: 5, StudentID: 1, State: model.LeaveStatePending, RequestedBy: 7}
+	cancelled := pending
+	cancelled.State = model.LeaveStateCancelled
+
+	repo.On("GetLeaveRequestByID", int64(5)).Return(pending, nil).Once()
+
+	// another student
+	_, err := svc.CancelLeaveRequest(5, 8, "")
+	assert.ErrorIs(t, err, ErrNotLeaveRequester)
+
+	// the requester, whose account is not the student's
+	students.On("GetStudentByEmail", "user7@scopex.local").Return(model.Student{ID: 2}, nil)
+	repo.On("GetLeaveRequestByID", int64(5)).Return(pending, nil).Once()
+	_, err = svc.CancelLeaveRequest(5, 7, "")
+	assert.ErrorIs(t, err, ErrNotOwnLeave)
+	repo.AssertNotCalled(t, "SetLeaveState", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	// an approver
+	repo.On("GetLeaveRequestByID", int64(5)).Return(pending, nil).Once()
+	repo.On("SetLeaveState", int64(5), model.LeaveStatePending, model.LeaveStateCancelled, int64(9), "").Return(nil).Once()
+	repo.On("GetLeaveRequestByID", int64(5)).Return(cancelled, nil).Once()
+	students.On("GetStudentByID", int64(1)).Return(model.Student{ID: 1}, nil)
+
+	leave, err := svc.CancelLeaveRequest(5, 9, "")
+	assert.NoError(t, err)
+	assert.Equal(t, model.LeaveStateCancelled, leave.State)
+	repo.AssertExpectations(t)
+}
+
+// withLeaveLock locks attendance older than a week before 2023-10-20, lets
+// user 9 override the lock and mocks a calendar where 2023-10-02 and
+// 2023-10-03 are working days
+func withLeaveLock(t *testing.T) *mockLockOverrideRepository {
+	withLockPolicy(t, 7)
+	overrides := &mockLockOverrideRepository{}
+	locks := newTestLockService(overrides)
+	locks.grants = func(userID int64) (model.Grants, error) {
+		if userID == 9 {
+			return model.Grants{Roles: []string{model.RoleAdmin}, Permissions: []string{model.PermLeaveDecide, model.PermAttendanceOverrideLock}}, nil
+		}
+		return model.Grants{Roles: []string{model.RoleStudent}, Permissions: []string{model.PermLeaveRequest}}, nil
+	}
+	withTestLockService(t, locks)
+
+	calendar := &mockCalendarRepository{}
+	calendar.On("GetTerms").Return(model.Terms{}, nil)
+	calendar.On("GetHolidays", "2023-10-02", "2023-10-03").Return(model.Holidays{}, nil)
+	calendar.On("GetWeekendDays").Return([]time.Weekday{time.Saturday, time.Sunday}, nil)
+	withMockCalendarRepository(t, calendar)
+
+	return overrides
+}
+
+func TestLeaveServiceApproveRejectsLockedDays(t *testing.T) {
+	withLeaveLock(t)
+	repo := &mockLeaveRepository{}
+	svc := newTestLeaveService(repo, &mockStudentRepository{})
+
+	repo.On("GetLeaveRequestByID", int64(3)).Return(model.LeaveRequest{ID: 3, StudentID: 1, FromDate: "2023-10-02", ToDate: "2023-10-03", State: model.LeaveStatePending}, nil).Once()
+
+	_, err := svc.ApproveLeaveRequest(3, 2, "")
+
+	assert.ErrorIs(t, err, ErrAttendanceLocked)
+	repo.AssertNotCalled(t, "ApproveLeaveRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLeaveServiceApproveRecordsLockOverride(t *testing.T) {
+	overrides := withLeaveLock(t)
+	repo := &mockLeaveRepository{}
+	students := &mockStudentRepository{}
+	svc := newTestLeaveService(repo, students)
+	svc.attendance = func(int64, string, string) (model.Attendances, error) {
+		return model.Attendances{
+			{ID: 11, StudentID: 1, Date: "2023-10-02", Status: model.StatusOnLeave},
+			{ID: 12, StudentID: 1, Date: "2023-10-03", SessionID: 4, Status: "Present"},
+		}, nil
+	}
+
+	pending := model.LeaveRequest{ID: 3, StudentID: 1, FromDate: "2023-10-02", ToDate: "2023-10-03", State: model.LeaveStatePending}
+	approved := pending
+	approved.State = model.LeaveStateApproved
+
+	repo.On("GetLeaveRequestByID", int64(3)).Return(pending, nil).Once()
+	repo.On("ApproveLeaveRequest", pending, int64(9), "", []string{"2023-10-02", "2023-10-03"}).Return(nil).Once()
+	repo.On("GetLeaveRequestByID", int64(3)).Return(approved, nil).Once()
+	students.On("GetStudentByID", int64(1)).Return(model.Student{ID: 1}, nil)
+	overrides.On("CreateOverride", model.LockOverride{
+		Action: model.LockActionMark, AttendanceID: 11, StudentID: 1, Date: "2023-10-02", Status: model.StatusOnLeave, UserID: 9,
+	}).Return(nil).Once()
+
+	_, err := svc.ApproveLeaveRequest(3, 9, "")
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	overrides.AssertExpectations(t)
+}
+
+func TestLeaveServiceCancelRejectsLockedMarks(t *testing.T) {
+	withLeaveLock(t)
+	repo := &mockLeaveRepository{}
+	students := &mockStudentRepository{}
+	students.On("GetStudentByEmail", "user7@scopex.local").Return(model.Student{ID: 1}, nil)
+	svc := newTestLeaveService(repo, students)
+	svc.attendance = func(int64, string, string) (model.Attendances, error) {
+		return model.Attendances{{ID: 11, StudentID: 1, Date: "2023-10-02", Status: model.StatusOnLeave}}, nil
+	}
+
+	repo.On("GetLeaveRequestByID", int64(4)).Return(model.LeaveRequest{ID: 4, StudentID: 1, FromDate: "2023-10-02", ToDate: "2023-10-03", State: model.LeaveStateApproved, RequestedBy: 7}, nil).Once()
+
+	_, err := svc.CancelLeaveRequest(4, 7, "")
+
+	assert.ErrorIs(t, err, ErrAttendanceLocked)
+	repo.AssertNotCalled(t, "CancelApprovedLeaveRequest", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCheckNotOnLeave(t *testing.T) {
+	repo := &mockLeaveRepository{}
+	original := leaveSvc
+	leaveSvc = newTestLeaveService(repo, &mockStudentRepository{})
+	t.Cleanup(func() { leaveSvc = original })
+
+	repo.On("GetStudentsOnLeave", "2023-10-30", []int64{1}).Return(map[int64]bool{1: true}, nil)
+
+	assert.ErrorIs(t, checkNotOnLeave(1, "2023-10-30"), ErrStudentOnLeave)
+}
+
+func TestLeaveRequestsTeacherScope(t *testing.T) {
+	withTeacher(t)
+	students := &studentServiceMock{}
+	students.On("GetStudentByID", int64(9)).Return(model.Student{ID: 9, DepartmentID: 5}, nil)
+	withMockStudentService(t, students)
+
+	repo := &mockLeaveRepository{}
+	original := leaveSvc
+	leaveSvc = newTestLeaveService(repo, &mockStudentRepository{})
+	t.Cleanup(func() { leaveSvc = original })
+
+	repo.On("GetLeaveRequests", model.LeaveRequestFilter{State: model.LeaveStatePending, DepartmentIDs: []int64{3, 4}}, 10, 0).Return(model.LeaveRequests{}, nil).Once()
+	repo.On("GetLeaveRequestByID", int64(6)).Return(model.LeaveRequest{ID: 6, StudentID: 9, State: model.LeaveStatePending}, nil)
+
+	rr, _ := performTeacherRequest(getLeaveRequests, http.MethodGet, "/leave-requests/?state=pending", nil, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	params := gin.Params{{Key: "id", Value: "6"}}
+	rr, _ = performTeacherRequest(getLeaveRequestByID, http.MethodGet, "/leave-requests/6", params, nil)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr, resp := performTeacherRequest(approveLeaveRequest, http.MethodPost, "/leave-requests/6/approve", params, nil)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, ErrOutsideDepartments.Error(), resp["error"])
+	repo.AssertNotCalled(t, "ApproveLeaveRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
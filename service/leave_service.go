@@ -0,0 +1,252 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutesLeave registers the leave request routes
+func RoutesLeave(rg *gin.RouterGroup) {
+	leave := rg.Group("/leave-requests")
+
+	leave.POST("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermLeaveRequest), createLeaveRequest)
+	leave.GET("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermLeaveRead), getLeaveRequests)
+	leave.GET("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermLeaveRead), getLeaveRequestByID)
+	leave.POST("/:id/approve", util.TokenAuthMiddleware(), util.RequirePermission(model.PermLeaveDecide), approveLeaveRequest)
+	leave.POST("/:id/reject", util.TokenAuthMiddleware(), util.RequirePermission(model.PermLeaveDecide), rejectLeaveRequest)
+	leave.POST("/:id/cancel", util.TokenAuthMiddleware(), util.RequirePermission(model.PermLeaveRequest), cancelLeaveRequest)
+}
+
+// createLeaveRequest godoc
+// @Summary Request leave
+// @Description Submit a pending leave request for a student and date range of at most 366 days. Callers without the leave:decide permission can only request leave for the student whose email matches their account email (403 otherwise).
+// @Tags Leave Requests
+// @Accept  json
+// @Produce  json
+// @Param leave body model.LeaveRequest true "Leave request"
+// @Success 201 {object} model.LeaveRequest
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /leave-requests/ [post]
+func createLeaveRequest(c *gin.Context) {
+	var leave model.LeaveRequest
+	if err := c.ShouldBindJSON(&leave); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := leaveSvc.CreateLeaveRequest(leave, currentUserID(c))
+	if err != nil {
+		handleLeaveError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// getLeaveRequests godoc
+// @Summary List leave requests
+// @Description Get a page of leave requests, newest first, optionally for one student or state. Teachers only see the requests of students of their own departments.
+// @Tags Leave Requests
+// @Accept  json
+// @Produce  json
+// @Param student_id query int false "Student ID"
+// @Param state query string false "State" Enums(pending, approved, rejected, cancelled)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size, at most 100" default(10) maximum(100)
+// @Success 200 {array} model.LeaveRequest
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /leave-requests/ [get]
+func getLeaveRequests(c *gin.Context) {
+	var filter model.LeaveRequestFilter
+	if studentIDStr := c.Query("student_id"); studentIDStr != "" {
+		studentID, err := strconv.ParseInt(studentIDStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Student ID"})
+			return
+		}
+		filter.StudentID = studentID
+	}
+
+	filter.State = c.Query("state")
+	switch filter.State {
+	case "", model.LeaveStatePending, model.LeaveStateApproved, model.LeaveStateRejected, model.LeaveStateCancelled:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "state must be one of pending, approved, rejected, cancelled"})
+		return
+	}
+
+	scope, ok := callerScope(c)
+	if !ok {
+		return
+	}
+	_, limit, offset := pagination(c)
+
+	leaves, err := leaveSvc.GetLeaveRequests(filter, scope, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leave requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, leaves)
+}
+
+// getLeaveRequestByID godoc
+// @Summary Get a leave request by ID
+// @Description Get details of a specific leave request by ID. Teachers can only view the requests of students of their own departments.
+// @Tags Leave Requests
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Leave request ID"
+// @Success 200 {object} model.LeaveRequest
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Security bearerAuth
+// @Router /leave-requests/{id} [get]
+func getLeaveRequestByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	leave, err := leaveSvc.GetLeaveRequestByID(id)
+	if err != nil {
+		handleLeaveError(c, err)
+		return
+	}
+	if !authorizeStudentID(c, leave.StudentID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, leave)
+}
+
+// approveLeaveRequest godoc
+// @Summary Approve a leave request
+// @Description Approve a pending leave request; teachers can only decide the requests of students of their own departments. Every working day of its range is marked OnLeave, replacing existing whole-day marks. Ranges reaching before the attendance lock window are rejected with 403 unless the caller has the attendance:override_lock permission.
+// @Tags Leave Requests
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Leave request ID"
+// @Param decision body model.LeaveDecision false "Decision note"
+// @Success 200 {object} model.LeaveRequest
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /leave-requests/{id}/approve [post]
+func approveLeaveRequest(c *gin.Context) {
+	decideLeaveRequest(c, leaveSvc.ApproveLeaveRequest)
+}
+
+// rejectLeaveRequest godoc
+// @Summary Reject a leave request
+// @Description Reject a pending leave request. Teachers can only decide the requests of students of their own departments.
+// @Tags Leave Requests
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Leave request ID"
+// @Param decision body model.LeaveDecision false "Decision note"
+// @Success 200 {object} model.LeaveRequest
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /leave-requests/{id}/reject [post]
+func rejectLeaveRequest(c *gin.Context) {
+	decideLeaveRequest(c, leaveSvc.RejectLeaveRequest)
+}
+
+// cancelLeaveRequest godoc
+// @Summary Cancel a leave request
+// @Description Cancel a pending or approved leave request. Cancelling approved leave removes the OnLeave marks it created. Callers without the leave:decide permission can only cancel requests they submitted for the student whose email matches their account email (403 otherwise), and removing marks before the attendance lock window needs the attendance:override_lock permission.
+// @Tags Leave Requests
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Leave request ID"
+// @Param decision body model.LeaveDecision false "Decision note"
+// @Success 200 {object} model.LeaveRequest
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /leave-requests/{id}/cancel [post]
+func cancelLeaveRequest(c *gin.Context) {
+	decideLeaveRequest(c, leaveSvc.CancelLeaveRequest)
+}
+
+// decideLeaveRequest applies a state change with the optional note from the
+// request body
+func decideLeaveRequest(c *gin.Context, decide func(id, decidedBy int64, note string) (model.LeaveRequest, error)) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var decision model.LeaveDecision
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&decision); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	leave, err := leaveSvc.GetLeaveRequestByID(id)
+	if err != nil {
+		handleLeaveError(c, err)
+		return
+	}
+	if !authorizeStudentID(c, leave.StudentID) {
+		return
+	}
+
+	leave, err = decide(id, currentUserID(c), decision.Note)
+	if err != nil {
+		handleLeaveError(c, err)
+		return
+	}
+	evaluateAlertsAfterWrite(leave.StudentID)
+
+	c.JSON(http.StatusOK, leave)
+}
+
+func handleLeaveError(c *gin.Context, err error) {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error(), "details": validationErr.Fields})
+	case errors.Is(err, ErrNotLeaveRequester), errors.Is(err, ErrNotOwnLeave), errors.Is(err, ErrNotAStudent),
+		errors.Is(err, ErrAttendanceLocked):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrLeaveRequestNotFound), errors.Is(err, repository.ErrStudentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrInvalidLeaveTransition), errors.Is(err, ErrOverlappingLeave),
+		errors.Is(err, repository.ErrLeaveStateChanged):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+)
+
+// earthRadiusMeters is the mean Earth radius used by the haversine formula
+const earthRadiusMeters = 6371000.0
+
+// ErrNoGeofence is returned when coordinates are sent but no location exists
+// to check them against.
+var ErrNoGeofence = errors.New("no locations are configured to check coordinates against")
+
+// LocationService describes the geofence operations the HTTP layer relies on.
+type LocationService interface {
+	CreateLocation(location model.Location) (model.Location, error)
+	GetLocations() (model.Locations, error)
+	GetLocationByID(id int64) (model.Location, error)
+	UpdateLocation(id int64, location model.Location) (model.Location, error)
+	DeleteLocation(id int64) error
+
+	MarkAttendance(mark model.AttendanceMark, mode string, changedBy int64) (model.Attendance, bool, error)
+	GetFlags(state string, scope departmentScope, limit, offset int) (model.AttendanceFlags, error)
+	GetFlag(attendanceID int64) (model.AttendanceFlag, error)
+	ReviewFlag(attendanceID int64, approve bool, reviewedBy int64, note string) (model.AttendanceFlag, error)
+}
+
+type locationService struct {
+	repo   repository.LocationRepository
+	record func(attendance model.Attendance, flag *model.AttendanceFlag, mode string, changedBy int64) (model.Attendance, bool, error)
+}
+
+var locationSvc LocationService = newLocationService(repository.LocationRepo)
+
+func newLocationService(repo repository.LocationRepository) *locationService {
+	return &locationService{repo: repo, record: recordAttendance}
+}
+
+func (s *locationService) CreateLocation(location model.Location) (model.Location, error) {
+	if err := validateLocationInput(location); err != nil {
+		return model.Location{}, err
+	}
+
+	id, err := s.repo.CreateLocation(location)
+	if err != nil {
+		return model.Location{}, err
+	}
+
+	return s.repo.GetLocationByID(id)
+}
+
+func (s *locationService) GetLocations() (model.Locations, error) {
+	return s.repo.GetLocations()
+}
+
+func (s *locationService) GetLocationByID(id int64) (model.Location, error) {
+	return s.repo.GetLocationByID(id)
+}
+
+func (s *locationService) UpdateLocation(id int64, location model.Location) (model.Location, error) {
+	if err := validateLocationInput(location); err != nil {
+		return model.Location{}, err
+	}
+
+	if err := s.repo.UpdateLocation(id, location); err != nil {
+		return model.Location{}, err
+	}
+
+	return s.repo.GetLocationByID(id)
+}
+
+func (s *locationService) DeleteLocation(id int64) error {
+	return s.repo.DeleteLocation(id)
+}
+
+// MarkAttendance records a mark like recordAttendance, first checking the
+// device coordinates when they are given. A mark counted as present that was
+// sent from outside the geofence is stored as Flagged and queued for review.
+func (s *locationService) MarkAttendance(mark model.AttendanceMark, mode string, changedBy int64) (model.Attendance, bool, error) {
+	flag, err := s.checkGeofence(mark)
+	if err != nil {
+		return model.Attendance{}, false, err
+	}
+
+	attendance := mark.Attendance
+	if flag != nil {
+		flag.RequestedStatus = attendance.Status
+		attendance.Status = model.StatusFlagged
+	}
+
+	// an ignored conflict returns the existing record and writes no flag
+	stored, created, err := s.record(attendance, flag, mode, changedBy)
+	if err != nil {
+		return model.Attendance{}, false, err
+	}
+
+	return stored, created, nil
+}
+
+// checkGeofence returns the flag to raise for a mark sent from outside its
+// geofence, or nil when the mark has no coordinates, does not count as
+// present or was sent from inside the fence. Without a location_id the
+// device may be inside any location.
+func (s *locationService) checkGeofence(mark model.AttendanceMark) (*model.AttendanceFlag, error) {
+	if mark.Latitude == nil && mark.Longitude == nil {
+		return nil, nil
+	}
+	if err := validateCoordinates(mark.Latitude, mark.Longitude); err != nil {
+		return nil, err
+	}
+	if !catalogue.countsAsPresent(mark.Status) {
+		return nil, nil
+	}
+
+	var candidates model.Locations
+	if mark.LocationID != 0 {
+		location, err := s.repo.GetLocationByID(mark.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		candidates = model.Locations{location}
+	} else {
+		locations, err := s.repo.GetLocations()
+		if err != nil {
+			return nil, err
+		}
+		candidates = locations
+	}
+	if len(candidates) == 0 {
+		return nil, ErrNoGeofence
+	}
+
+	lat, lon := *mark.Latitude, *mark.Longitude
+	nearest, nearestDistance := candidates[0], math.Inf(1)
+	for _, location := range candidates {
+		distance := haversineMeters(lat, lon, location.Latitude, location.Longitude)
+		if distance <= float64(location.RadiusMeters) {
+			return nil, nil
+		}
+		if distance < nearestDistance {
+			nearest, nearestDistance = location, distance
+		}
+	}
+
+	return &model.AttendanceFlag{
+		LocationID:     &nearest.ID,
+		Latitude:       lat,
+		Longitude:      lon,
+		DistanceMeters: int(math.Round(nearestDistance)),
+	}, nil
+}
+
+// GetFlags lists the flags in a review state of the students in scope
+func (s *locationService) GetFlags(state string, scope departmentScope, limit, offset int) (model.AttendanceFlags, error) {
+	switch state {
+	case "", model.FlagStatePending, model.FlagStateApproved, model.FlagStateRejected:
+	default:
+		return nil, &ValidationError{Fields: map[string]string{"state": "state must be one of pending, approved, rejected"}}
+	}
+	if scope.restricted && len(scope.departments) == 0 {
+		return model.AttendanceFlags{}, nil
+	}
+	return s.repo.GetAttendanceFlags(state, scope.departments, limit, offset)
+}
+
+func (s *locationService) GetFlag(attendanceID int64) (model.AttendanceFlag, error) {
+	return s.repo.GetAttendanceFlag(attendanceID)
+}
+
+// ReviewFlag closes a pending flag. Approval restores the status the mark
+// was submitted with; rejection marks the student Absent.
+func (s *locationService) ReviewFlag(attendanceID int64, approve bool, reviewedBy int64, note string) (model.AttendanceFlag, error) {
+	flag, err := s.repo.GetAttendanceFlag(attendanceID)
+	if err != nil {
+		return model.AttendanceFlag{}, err
+	}
+	if flag.State != model.FlagStatePending {
+		return model.AttendanceFlag{}, repository.ErrFlagReviewed
+	}
+
+	state, status := model.FlagStateRejected, model.StatusAbsent
+	if approve {
+		state, status = model.FlagStateApproved, flag.RequestedStatus
+	}
+
+	if err := s.repo.ReviewAttendanceFlag(attendanceID, state, status, reviewedBy, strings.TrimSpace(note)); err != nil {
+		return model.AttendanceFlag{}, err
+	}
+
+	return s.repo.GetAttendanceFlag(attendanceID)
+}
+
+// haversineMeters is the great-circle distance between two points
+func haversineMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	toRadians := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
+
+func validateCoordinates(lat, lon *float64) error {
+	issues := make(map[string]string)
+
+	if lat == nil || lon == nil {
+		issues["coordinates"] = "latitude and longitude must be given together"
+	} else {
+		if *lat < -90 || *lat > 90 {
+			issues["latitude"] = "latitude must be between -90 and 90"
+		}
+		if *lon < -180 || *lon > 180 {
+			issues["longitude"] = "longitude must be between -180 and 180"
+		}
+	}
+
+	if len(issues) > 0 {
+		return &ValidationError{Fields: issues}
+	}
+
+	return nil
+}
+
+func validateLocationInput(location model.Location) error {
+	issues := make(map[string]string)
+
+	if strings.TrimSpace(location.Name) == "" {
+		issues["name"] = "name is required"
+	}
+	if err := validateCoordinates(&location.Latitude, &location.Longitude); err != nil {
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			for field, issue := range validationErr.Fields {
+				issues[field] = issue
+			}
+		}
+	}
+	if location.RadiusMeters < 1 {
+		issues["radius_meters"] = "radius_meters must be positive"
+	}
+
+	if len(issues) > 0 {
+		return &ValidationError{Fields: issues}
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockLocationRepository struct {
+	mock.Mock
+}
+
+func (m *mockLocationRepository) CreateLocation(location model.Location) (int64, error) {
+	args := m.Called(location)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockLocationRepository) GetLocations() (model.Locations, error) {
+	args := m.Called()
+	return args.Get(0).(model.Locations), args.Error(1)
+}
+
+func (m *mockLocationRepository) GetLocationByID(id int64) (model.Location, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Location), args.Error(1)
+}
+
+func (m *mockLocationRepository) UpdateLocation(id int64, location model.Location) error {
+	args := m.Called(id, location)
+	return args.Error(0)
+}
+
+func (m *mockLocationRepository) DeleteLocation(id int64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *mockLocationRepository) GetAttendanceFlags(state string, departmentIDs []int64, limit, offset int) (model.AttendanceFlags, error) {
+	args := m.Called(state, departmentIDs, limit, offset)
+	return args.Get(0).(model.AttendanceFlags), args.Error(1)
+}
+
+func (m *mockLocationRepository) GetAttendanceFlag(attendanceID int64) (model.AttendanceFlag, error) {
+	args := m.Called(attendanceID)
+	return args.Get(0).(model.AttendanceFlag), args.Error(1)
+}
+
+func (m *mockLocationRepository) ReviewAttendanceFlag(attendanceID int64, state, newStatus string, reviewedBy int64, note string) error {
+	args := m.Called(attendanceID, state, newStatus, reviewedBy, note)
+	return args.Error(0)
+}
+
+// campus is a 200m fence; 0.01 degrees of latitude is about 1112m
+var campus = model.Location{ID: 1, Name: "Campus", Latitude: 12.97, Longitude: 77.59, RadiusMeters: 200}
+
+// recordedMark is a mark passed to record with the flag written alongside it
+type recordedMark struct {
+	attendance model.Attendance
+	flag       *model.AttendanceFlag
+}
+
+func newTestLocationService(repo *mockLocationRepository) (*locationService, *[]recordedMark) {
+	svc := newLocationService(repo)
+	recorded := &[]recordedMark{}
+	svc.record = func(a model.Attendance, flag *model.AttendanceFlag, _ string, _ int64) (model.Attendance, bool, error) {
+		a.ID = 42
+		*recorded = append(*recorded, recordedMark{a, flag})
+		return a, true, nil
+	}
+	return svc, recorded
+}
+
+func coordinates(lat, lon float64) (*float64, *float64) {
+	return &lat, &lon
+}
+
+func TestHaversineMeters(t *testing.T) {
+	assert.InDelta(t, 1111.95, haversineMeters(12.97, 77.59, 12.98, 77.59), 0.5)
+	assert.Equal(t, 0.0, haversineMeters(12.97, 77.59, 12.97, 77.59))
+	// London to Paris is about 343.5km
+	assert.InDelta(t, 343_500, haversineMeters(51.5074, -0.1278, 48.8566, 2.3522), 1000)
+}
+
+func TestMarkAttendanceInsideGeofence(t *testing.T) {
+	repo := &mockLocationRepository{}
+	repo.On("GetLocations").Return(model.Locations{campus}, nil)
+	svc, recorded := newTestLocationService(repo)
+
+	lat, lon := coordinates(12.9705, 77.59)
+	mark := model.AttendanceMark{Attendance: model.Attendance{StudentID: 1, Date: "2023-10-27", Status: "Present"}, Latitude: lat, Longitude: lon}
+
+	stored, _, err := svc.MarkAttendance(mark, conflictReject, 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Present", stored.Status)
+	assert.Len(t, *recorded, 1)
+	assert.Nil(t, (*recorded)[0].flag)
+}
+
+func TestMarkAttendanceOutsideGeofenceIsFlagged(t *testing.T) {
+	repo := &mockLocationRepository{}
+	repo.On("GetLocationByID", int64(1)).Return(campus, nil)
+	svc, recorded := newTestLocationService(repo)
+
+	lat, lon := coordinates(12.98, 77.59)
+	mark := model.AttendanceMark{Attendance: model.Attendance{StudentID: 1, Date: "2023-10-27", Status: "Present"}, Latitude: lat, Longitude: lon, LocationID: 1}
+
+	stored, created, err := svc.MarkAttendance(mark, conflictReject, 7)
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, model.StatusFlagged, stored.Status)
+	// the flag is handed to record so it is written with the mark
+	assert.Len(t, *recorded, 1)
+	flag := (*recorded)[0].flag
+	if assert.NotNil(t, flag) {
+		assert.Equal(t, "Present", flag.RequestedStatus)
+		assert.Equal(t, int64(1), *flag.LocationID)
+		assert.InDelta(t, 1112, flag.DistanceMeters, 1)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestMarkAttendanceGeofenceValidation(t *testing.T) {
+	repo := &mockLocationRepository{}
+	repo.On("GetLocations").Return(model.Locations{}, nil)
+	svc, recorded := newTestLocationService(repo)
+
+	lat, _ := coordinates(12.98, 77.59)
+	_, _, err := svc.MarkAttendance(model.AttendanceMark{Attendance: model.Attendance{Status: "Present"}, Latitude: lat}, conflictReject, 7)
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+
+	lat, lon := coordinates(12.98, 77.59)
+	_, _, err = svc.MarkAttendance(model.AttendanceMark{Attendance: model.Attendance{Status: "Present"}, Latitude: lat, Longitude: lon}, conflictReject, 7)
+	assert.ErrorIs(t, err, ErrNoGeofence)
+
+	// absences are never flagged, so they need no fence
+	_, _, err = svc.MarkAttendance(model.AttendanceMark{Attendance: model.Attendance{Status: "Absent"}, Latitude: lat, Longitude: lon}, conflictReject, 7)
+	assert.NoError(t, err)
+	assert.Len(t, *recorded, 1)
+}
+
+func TestReviewFlag(t *testing.T) {
+	repo := &mockLocationRepository{}
+	pending := model.AttendanceFlag{AttendanceID: 42, StudentID: 1, Status: model.StatusFlagged, RequestedStatus: "Late", State: model.FlagStatePending}
+	approved := pending
+	approved.State, approved.Status = model.FlagStateApproved, "Late"
+
+	repo.On("GetAttendanceFlag", int64(42)).Return(pending, nil).Once()
+	repo.On("ReviewAttendanceFlag", int64(42), model.FlagStateApproved, "Late", int64(7), "bus broke down").Return(nil)
+	repo.On("GetAttendanceFlag", int64(42)).Return(approved, nil).Once()
+	svc, _ := newTestLocationService(repo)
+
+	flag, err := svc.ReviewFlag(42, true, 7, " bus broke down ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Late", flag.Status)
+
+	repo.On("GetAttendanceFlag", int64(42)).Return(approved, nil)
+	_, err = svc.ReviewFlag(42, false, 7, "")
+	assert.ErrorIs(t, err, repository.ErrFlagReviewed)
+	repo.AssertExpectations(t)
+}
+
+func TestAttendanceFlagsTeacherScope(t *testing.T) {
+	withTeacher(t)
+	students := &studentServiceMock{}
+	students.On("GetStudentByID", int64(9)).Return(model.Student{ID: 9, DepartmentID: 5}, nil)
+	withMockStudentService(t, students)
+
+	repo := &mockLocationRepository{}
+	svc, _ := newTestLocationService(repo)
+	original := locationSvc
+	locationSvc = svc
+	t.Cleanup(func() { locationSvc = original })
+
+	repo.On("GetAttendanceFlags", model.FlagStatePending, []int64{3, 4}, 10, 0).Return(model.AttendanceFlags{}, nil).Once()
+	repo.On("GetAttendanceFlag", int64(42)).Return(model.AttendanceFlag{AttendanceID: 42, StudentID: 9, State: model.FlagStatePending}, nil)
+
+	rr, _ := performTeacherRequest(getAttendanceFlags, http.MethodGet, "/attendance/flags", nil, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr, resp := performTeacherRequest(approveAttendanceFlag, http.MethodPost, "/attendance/flags/42/approve", gin.Params{{Key: "id", Value: "42"}}, nil)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, ErrOutsideDepartments.Error(), resp["error"])
+	repo.AssertNotCalled(t, "ReviewAttendanceFlag", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutesLocation registers the geofence location and flag review routes
+func RoutesLocation(rg *gin.RouterGroup) {
+	location := rg.Group("/locations")
+
+	location.POST("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermLocationsWrite), createLocation)
+	location.GET("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermLocationsRead), getLocations)
+	location.GET("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermLocationsRead), getLocationByID)
+	location.PUT("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermLocationsWrite), updateLocation)
+	location.DELETE("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermLocationsWrite), deleteLocation)
+
+	flags := rg.Group("/attendance/flags")
+
+	flags.GET("", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceReview), getAttendanceFlags)
+	flags.POST("/:id/approve", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceReview), approveAttendanceFlag)
+	flags.POST("/:id/reject", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceReview), rejectAttendanceFlag)
+}
+
+// createLocation godoc
+// @Summary Create a location
+// @Description Create a campus or classroom geofence: a circle of radius_meters around a latitude and longitude
+// @Tags Locations
+// @Accept  json
+// @Produce  json
+// @Param location body model.Location true "Location"
+// @Success 201 {object} model.Location
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /locations/ [post]
+func createLocation(c *gin.Context) {
+	var location model.Location
+	if err := c.ShouldBindJSON(&location); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := locationSvc.CreateLocation(location)
+	if err != nil {
+		handleLocationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// getLocations godoc
+// @Summary List locations
+// @Description Get every geofence location ordered by name
+// @Tags Locations
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} model.Location
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /locations/ [get]
+func getLocations(c *gin.Context) {
+	locations, err := locationSvc.GetLocations()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch locations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, locations)
+}
+
+// getLocationByID godoc
+// @Summary Get a location
+// @Description Get a geofence location by ID
+// @Tags Locations
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Location ID"
+// @Success 200 {object} model.Location
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /locations/{id} [get]
+func getLocationByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	location, err := locationSvc.GetLocationByID(id)
+	if err != nil {
+		handleLocationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, location)
+}
+
+// updateLocation godoc
+// @Summary Update a location
+// @Description Change the name, centre or radius of a geofence location
+// @Tags Locations
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Location ID"
+// @Param location body model.Location true "Location"
+// @Success 200 {object} model.Location
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /locations/{id} [put]
+func updateLocation(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var location model.Location
+	if err := c.ShouldBindJSON(&location); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := locationSvc.UpdateLocation(id, location)
+	if err != nil {
+		handleLocationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// deleteLocation godoc
+// @Summary Delete a location
+// @Description Delete a geofence location. Flags raised against it keep their coordinates and distance.
+// @Tags Locations
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Location ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /locations/{id} [delete]
+func deleteLocation(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := locationSvc.DeleteLocation(id); err != nil {
+		handleLocationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Location deleted successfully"})
+}
+
+// getAttendanceFlags godoc
+// @Summary Flagged attendance review queue
+// @Description List marks stored as Flagged because they were sent from outside their geofence, oldest first. Teachers only see the flags of students of their own departments.
+// @Tags Attendance
+// @Accept  json
+// @Produce  json
+// @Param state query string false "Review state" Enums(pending, approved, rejected) default(pending)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size, at most 100" default(10) maximum(100)
+// @Success 200 {array} model.AttendanceFlag
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /attendance/flags [get]
+func getAttendanceFlags(c *gin.Context) {
+	scope, ok := callerScope(c)
+	if !ok {
+		return
+	}
+	_, limit, offset := pagination(c)
+
+	flags, err := locationSvc.GetFlags(c.DefaultQuery("state", model.FlagStatePending), scope, limit, offset)
+	if err != nil {
+		handleLocationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, flags)
+}
+
+// approveAttendanceFlag godoc
+// @Summary Approve a flagged mark
+// @Description Accept a mark sent from outside its geofence, restoring the status it was submitted with. Teachers can only review students of their own departments.
+// @Tags Attendance
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Attendance ID"
+// @Param review body model.FlagReview false "Review note"
+// @Success 200 {object} model.AttendanceFlag
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /attendance/flags/{id}/approve [post]
+func approveAttendanceFlag(c *gin.Context) {
+	reviewAttendanceFlag(c, true)
+}
+
+// rejectAttendanceFlag godoc
+// @Summary Reject a flagged mark
+// @Description Refuse a mark sent from outside its geofence, marking the student Absent. Teachers can only review students of their own departments.
+// @Tags Attendance
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Attendance ID"
+// @Param review body model.FlagReview false "Review note"
+// @Success 200 {object} model.AttendanceFlag
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /attendance/flags/{id}/reject [post]
+func rejectAttendanceFlag(c *gin.Context) {
+	reviewAttendanceFlag(c, false)
+}
+
+func reviewAttendanceFlag(c *gin.Context, approve bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var review model.FlagReview
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&review); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	flag, err := locationSvc.GetFlag(id)
+	if err != nil {
+		handleLocationError(c, err)
+		return
+	}
+	if !authorizeStudentID(c, flag.StudentID) {
+		return
+	}
+
+	flag, err = locationSvc.ReviewFlag(id, approve, currentUserID(c), review.Note)
+	if err != nil {
+		handleLocationError(c, err)
+		return
+	}
+	evaluateAlertsAfterWrite(flag.StudentID)
+
+	c.JSON(http.StatusOK, flag)
+}
+
+func handleLocationError(c *gin.Context, err error) {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error(), "details": validationErr.Fields})
+	case errors.Is(err, repository.ErrLocationNotFound), errors.Is(err, repository.ErrFlagNotFound),
+		errors.Is(err, repository.ErrAttendanceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrFlagReviewed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockLockOverrideRepository struct {
+	mock.Mock
+}
+
+func (m *mockLockOverrideRepository) CreateOverride(override model.LockOverride) error {
+	args := m.Called(override)
+	return args.Error(0)
+}
+
+func (m *mockLockOverrideRepository) GetOverrides(limit, offset int) (model.LockOverrides, error) {
+	args := m.Called(limit, offset)
+	return args.Get(0).(model.LockOverrides), args.Error(1)
+}
+
+func withLockPolicy(t *testing.T, days int) {
+	viper.Set("ATTENDANCE.LOCK_AFTER_DAYS", days)
+	t.Cleanup(func() { viper.Set("ATTENDANCE.LOCK_AFTER_DAYS", 0) })
+}
+
+func newTestLockService(repo *mockLockOverrideRepository) *lockService {
+	svc := newLockService(repo)
+	svc.now = func() time.Time { return time.Date(2023, 10, 20, 15, 0, 0, 0, time.UTC) }
+	svc.grants = func(userID int64) (model.Grants, error) {
+		switch userID {
+		case 1:
+			return model.Grants{Roles: []string{model.RoleAdmin}, Permissions: []string{model.PermAttendanceMark, model.PermAttendanceOverrideLock}}, nil
+		case 2:
+			return model.Grants{Roles: []string{model.RoleTeacher}, Permissions: []string{model.PermAttendanceMark}}, nil
+		}
+		return model.Grants{}, errors.New("unexpected grants lookup")
+	}
+	return svc
+}
+
+func TestAttendanceLockPolicyWindow(t *testing.T) {
+	today := time.Date(2023, 10, 20, 15, 0, 0, 0, time.UTC)
+	policy := attendanceLockPolicy{AfterDays: 7}
+
+	assert.False(t, policy.locks("2023-10-13", today))
+	assert.True(t, policy.locks("2023-10-12", today))
+	assert.False(t, attendanceLockPolicy{}.locks("2020-01-01", today))
+}
+
+func TestAuthorizeLockedDate(t *testing.T) {
+	withLockPolicy(t, 7)
+	svc := newTestLockService(&mockLockOverrideRepository{})
+
+	overridden, err := svc.Authorize("2023-10-19", 2)
+	assert.NoError(t, err)
+	assert.False(t, overridden)
+
+	_, err = svc.Authorize("2023-10-01", 2)
+	assert.ErrorIs(t, err, ErrAttendanceLocked)
+
+	_, err = svc.Authorize("2023-10-01", 0)
+	assert.ErrorIs(t, err, ErrAttendanceLocked)
+
+	overridden, err = svc.Authorize("2023-10-01", 1)
+	assert.NoError(t, err)
+	assert.True(t, overridden)
+}
+
+func TestRecordOverride(t *testing.T) {
+	repo := &mockLockOverrideRepository{}
+	repo.On("CreateOverride", model.LockOverride{
+		Action: model.LockActionUpdate, AttendanceID: 9, StudentID: 3, Date: "2023-10-01", Status: "Late", UserID: 1,
+	}).Return(nil)
+	svc := newTestLockService(repo)
+
+	svc.RecordOverride(model.LockActionUpdate, model.Attendance{ID: 9, StudentID: 3, Date: "2023-10-01T00:00:00Z", Status: "Late"}, 1)
+
+	repo.AssertExpectations(t)
+}
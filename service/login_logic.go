@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/spf13/viper"
+)
+
+// Defaults used when the LOGIN settings are unset
+const (
+	defaultLoginMaxFailures    = 5
+	defaultLoginIPMaxFailures  = 20
+	defaultLoginWindowMinutes  = 15
+	defaultLoginLockoutMinutes = 15
+)
+
+// ErrTooManyLoginAttempts is returned while a username or client IP is
+// locked out after repeated failed logins.
+var ErrTooManyLoginAttempts = errors.New("too many failed logins, try again later")
+
+// loginThrottledError carries how long the lockout lasts
+type loginThrottledError struct {
+	retryAfter time.Duration
+}
+
+func (e *loginThrottledError) Error() string { return ErrTooManyLoginAttempts.Error() }
+func (e *loginThrottledError) Unwrap() error { return ErrTooManyLoginAttempts }
+
+func currentLoginThrottle() util.LoginThrottle {
+	t := util.LoginThrottle{
+		MaxFailures:   defaultLoginMaxFailures,
+		IPMaxFailures: defaultLoginIPMaxFailures,
+		Window:        defaultLoginWindowMinutes * time.Minute,
+		LockFor:       defaultLoginLockoutMinutes * time.Minute,
+	}
+	if viper.IsSet("LOGIN.MAX_FAILURES") {
+		t.MaxFailures = viper.GetInt("LOGIN.MAX_FAILURES")
+	}
+	if viper.IsSet("LOGIN.IP_MAX_FAILURES") {
+		t.IPMaxFailures = viper.GetInt("LOGIN.IP_MAX_FAILURES")
+	}
+	if viper.IsSet("LOGIN.FAILURE_WINDOW_MINUTES") {
+		t.Window = time.Duration(viper.GetInt("LOGIN.FAILURE_WINDOW_MINUTES")) * time.Minute
+	}
+	if viper.IsSet("LOGIN.LOCKOUT_MINUTES") {
+		t.LockFor = time.Duration(viper.GetInt("LOGIN.LOCKOUT_MINUTES")) * time.Minute
+	}
+	return t
+}
+
+// LoginService describes the credential checks behind POST /login and the
+// admin unlock of an account.
+type LoginService interface {
+	Login(username, password, ip string) (model.MUser, error)
+	Unlock(userID int64) error
+}
+
+type loginService struct {
+	authenticate  func(username, password string) (model.MUser, error)
+	users         func(id int64) (model.MUser, error)
+	unlock        func(id int64) error
+	lockedFor     func(username, ip string) (time.Duration, error)
+	recordFailure func(username, ip string, t util.LoginThrottle) (time.Duration, error)
+	clearFailures func(username string) error
+}
+
+var loginSvc LoginService = newLoginService()
+
+func newLoginService() *loginService {
+	return &loginService{
+		authenticate:  repository.GetUserLogin,
+		users:         repository.GetUserByID,
+		unlock:        repository.UnlockUser,
+		lockedFor:     util.LoginLockedFor,
+		recordFailure: util.RecordLoginFailure,
+		clearFailures: util.ClearLoginFailures,
+	}
+}
+
+// Login checks the credentials and account flags of a user. Wrong
+// credentials count towards the lockout of the username and of ip; a
+// successful login clears the username's count.
+func (s *loginService) Login(username, password, ip string) (model.MUser, error) {
+	remaining, err := s.lockedFor(username, ip)
+	if err != nil {
+		return model.MUser{}, err
+	}
+	if remaining > 0 {
+		return model.MUser{}, &loginThrottledError{retryAfter: remaining}
+	}
+
+	user, err := s.authenticate(username, password)
+	if errors.Is(err, repository.ErrBadCredentials) {
+		locked, recordErr := s.recordFailure(username, ip, currentLoginThrottle())
+		if recordErr != nil {
+			return model.MUser{}, recordErr
+		}
+		if locked > 0 {
+			return model.MUser{}, &loginThrottledError{retryAfter: locked}
+		}
+		return model.MUser{}, err
+	}
+	if err != nil {
+		return model.MUser{}, err
+	}
+
+	if err := s.clearFailures(username); err != nil {
+		return model.MUser{}, err
+	}
+	return user, nil
+}
+
+// Unlock clears both the locked flag of an account and its failed-login
+// lockout
+func (s *loginService) Unlock(userID int64) error {
+	if err := s.unlock(userID); err != nil {
+		return err
+	}
+	user, err := s.users(userID)
+	if err != nil {
+		return err
+	}
+	return s.clearFailures(user.UserName)
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLoginThrottle records what the login service asks of the lockout
+// counters
+type fakeLoginThrottle struct {
+	locked   time.Duration
+	lockNext time.Duration
+	failures []string
+	cleared  []string
+}
+
+func newTestLoginService(throttle *fakeLoginThrottle) *loginService {
+	svc := newLoginService()
+	svc.authenticate = func(username, password string) (model.MUser, error) {
+		switch {
+		case username == "budi" && password == "secret123":
+			return model.MUser{ID: 2, UserName: "budi", Enabled: true}, nil
+		case username == "haya" && password == "secret123":
+			return model.MUser{ID: 4, UserName: "haya", Enabled: true, AccountLocked: true}, repository.ErrAccountLocked
+		}
+		return model.MUser{}, repository.ErrBadCredentials
+	}
+	svc.users = func(id int64) (model.MUser, error) {
+		return model.MUser{ID: id, UserName: "haya"}, nil
+	}
+	svc.unlock = func(id int64) error { return nil }
+	svc.lockedFor = func(username, ip string) (time.Duration, error) { return throttle.locked, nil }
+	svc.recordFailure = func(username, ip string, t util.LoginThrottle) (time.Duration, error) {
+		throttle.failures = append(throttle.failures, username+"@"+ip)
+		return throttle.lockNext, nil
+	}
+	svc.clearFailures = func(username string) error {
+		throttle.cleared = append(throttle.cleared, username)
+		return nil
+	}
+	return svc
+}
+
+func TestLoginCountsBadCredentials(t *testing.T) {
+	throttle := &fakeLoginThrottle{}
+	svc := newTestLoginService(throttle)
+
+	_, err := svc.Login("budi", "wrong", "203.0.113.7")
+	assert.ErrorIs(t, err, repository.ErrBadCredentials)
+	assert.Equal(t, []string{"budi@203.0.113.7"}, throttle.failures)
+
+	// the account flags are not failed attempts
+	_, err = svc.Login("haya", "secret123", "203.0.113.7")
+	assert.ErrorIs(t, err, repository.ErrAccountLocked)
+	assert.Len(t, throttle.failures, 1)
+
+	user, err := svc.Login("budi", "secret123", "203.0.113.7")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), user.ID)
+	assert.Equal(t, []string{"budi"}, throttle.cleared)
+}
+
+func TestLoginLockout(t *testing.T) {
+	throttle := &fakeLoginThrottle{lockNext: 15 * time.Minute}
+	svc := newTestLoginService(throttle)
+
+	// the failure that reaches the limit reports the lockout
+	_, err := svc.Login("budi", "wrong", "203.0.113.7")
+	var throttled *loginThrottledError
+	assert.True(t, errors.As(err, &throttled))
+	assert.Equal(t, 15*time.Minute, throttled.retryAfter)
+
+	// while locked, even the right password is refused without a check
+	throttle.locked = 10 * time.Minute
+	_, err = svc.Login("budi", "secret123", "203.0.113.7")
+	assert.ErrorIs(t, err, ErrTooManyLoginAttempts)
+	assert.Empty(t, throttle.cleared)
+}
+
+func TestUnlockClearsLockout(t *testing.T) {
+	throttle := &fakeLoginThrottle{}
+	svc := newTestLoginService(throttle)
+
+	assert.NoError(t, svc.Unlock(4))
+	assert.Equal(t, []string{"haya"}, throttle.cleared)
+}
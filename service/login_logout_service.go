@@ -1,7 +1,10 @@
 package service
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	model "github.com/shravanasati/scopex-go-assignment/model"
 	repository "github.com/shravanasati/scopex-go-assignment/repository"
@@ -16,24 +19,39 @@ type CredentialsLogin struct {
 	Password string `json:"password"`
 }
 
+// RefreshRequest carries the refresh token to exchange
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
 // RoutesLoginLogout ...
 func RoutesLoginLogout(rg *gin.RouterGroup) {
 	cred := rg.Group("/")
 
 	cred.POST("login", getUserLogin)
 	cred.GET("logout", getUserLogout)
+	cred.POST("token/refresh", refreshToken)
+}
+
+// LoginFailure is the body of a refused login. Code is one of
+// BAD_CREDENTIALS, ACCOUNT_DISABLED, ACCOUNT_LOCKED, ACCOUNT_EXPIRED,
+// CREDENTIALS_EXPIRED or TOO_MANY_ATTEMPTS.
+type LoginFailure struct {
+	Message string `json:"message" example:"account is locked"`
+	Code    string `json:"code" example:"ACCOUNT_LOCKED"`
 }
 
 // getUserLogin godoc
 // @Summary Auth user
-// @Description login user
+// @Description login user. Disabled, locked and expired accounts and expired credentials are refused with 403 and their own code. After LOGIN.MAX_FAILURES wrong passwords for a username (or LOGIN.IP_MAX_FAILURES from one IP) within LOGIN.FAILURE_WINDOW_MINUTES, logins are refused with 429 for LOGIN.LOCKOUT_MINUTES.
 // @Accept  json
 // @Produce  json
 // @Param user body CredentialsLogin true "Input username & password"
 // @Success 200 {object} util.TokenDetails
-// @Failure 400 {string} string
-// @Failure 401 {string} string
-// @Failure 404 {string} string
+// @Failure 401 {object} LoginFailure
+// @Failure 403 {object} LoginFailure
+// @Failure 422 {string} string
+// @Failure 429 {object} LoginFailure
 // @Failure 500 {string} string
 // @Router /login [post]
 func getUserLogin(c *gin.Context) {
@@ -47,22 +65,29 @@ func getUserLogin(c *gin.Context) {
 		return
 	}
 
-	user, err = repository.GetUserLogin(creds.Username, creds.Password)
+	user, err = loginSvc.Login(creds.Username, creds.Password, c.ClientIP())
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		handleLoginError(c, err)
 		return
 	}
 
-	jwt, err := util.CreateToken(user)
+	grants, err := repository.RoleRepo.GetUserGrants(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	jwt, err := util.CreateToken(user, grants)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
 
-	err = util.SaveToRedis(user.ID, jwt)
+	err = util.SaveToRedis(user.ID, jwt, clientInfo(c))
 	if err != nil {
 		ad := &util.AccessDetails{
 			AccessUUID: jwt.AccessUUID,
+			FamilyID:   jwt.FamilyID,
 			UserID:     user.ID,
 		}
 		util.DeleteToken(ad)
@@ -73,6 +98,27 @@ func getUserLogin(c *gin.Context) {
 	c.JSON(http.StatusOK, jwt)
 }
 
+func handleLoginError(c *gin.Context, err error) {
+	var throttled *loginThrottledError
+	switch {
+	case errors.Is(err, repository.ErrBadCredentials):
+		c.JSON(http.StatusUnauthorized, LoginFailure{Message: err.Error(), Code: "BAD_CREDENTIALS"})
+	case errors.Is(err, repository.ErrAccountDisabled):
+		c.JSON(http.StatusForbidden, LoginFailure{Message: err.Error(), Code: "ACCOUNT_DISABLED"})
+	case errors.Is(err, repository.ErrAccountLocked):
+		c.JSON(http.StatusForbidden, LoginFailure{Message: err.Error(), Code: "ACCOUNT_LOCKED"})
+	case errors.Is(err, repository.ErrAccountExpired):
+		c.JSON(http.StatusForbidden, LoginFailure{Message: err.Error(), Code: "ACCOUNT_EXPIRED"})
+	case errors.Is(err, repository.ErrCredentialsExpired):
+		c.JSON(http.StatusForbidden, LoginFailure{Message: err.Error(), Code: "CREDENTIALS_EXPIRED"})
+	case errors.As(err, &throttled):
+		c.Header("Retry-After", strconv.FormatInt(int64(math.Ceil(throttled.retryAfter.Seconds())), 10))
+		c.JSON(http.StatusTooManyRequests, LoginFailure{Message: err.Error(), Code: "TOO_MANY_ATTEMPTS"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
+
 // getUserLogout godoc
 // @Summary Logout
 // @Description logout
@@ -101,3 +147,81 @@ func getUserLogout(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Successfully logged out!"})
 }
+
+// refreshToken godoc
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access/refresh pair. Each refresh token can be exchanged once: the old pair is revoked, and presenting an already exchanged token revokes every token of its login session. When the account has since been disabled, locked or expired, or its credentials expired, the session ends and the refresh is refused with 403 and the same code as login.
+// @Accept  json
+// @Produce  json
+// @Param token body RefreshRequest true "Refresh token"
+// @Success 200 {object} util.TokenDetails
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} LoginFailure
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /token/refresh [post]
+func refreshToken(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": "invalid json"})
+		return
+	}
+
+	details, err := util.VerifyRefreshToken(req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+		return
+	}
+
+	if err := util.ConsumeRefreshToken(details); err != nil {
+		if errors.Is(err, util.ErrInvalidRefreshToken) || errors.Is(err, util.ErrRefreshTokenReused) {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	// reload the user and their roles so the new pair reflects any change
+	user, err := repository.GetUserByID(details.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	if user.ID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": util.ErrInvalidRefreshToken.Error()})
+		return
+	}
+	if err := repository.CheckAccountFlags(user); err != nil {
+		// the account was disabled, locked or expired since login: end the
+		// session instead of extending it
+		if revokeErr := util.RevokeTokenFamily(details.FamilyID); revokeErr != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": revokeErr.Error()})
+			return
+		}
+		handleLoginError(c, err)
+		return
+	}
+	grants, err := repository.RoleRepo.GetUserGrants(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	jwt, err := util.RotateToken(details, user, grants)
+	if err == nil {
+		err = util.SaveToRedis(user.ID, jwt, clientInfo(c))
+	}
+	if errors.Is(err, util.ErrTokenFamilyRevoked) {
+		// the session was revoked while the token was being exchanged, e.g.
+		// by a concurrent reuse of the same refresh token
+		c.JSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, jwt)
+}
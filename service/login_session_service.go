@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutesLoginSession registers the routes through which the caller lists and
+// logs out their own login sessions
+func RoutesLoginSession(rg *gin.RouterGroup) {
+	session := rg.Group("/sessions")
+
+	session.GET("", util.TokenAuthMiddleware(), getMySessions)
+	session.DELETE("/:id", util.TokenAuthMiddleware(), deleteMySession)
+	session.DELETE("", util.TokenAuthMiddleware(), deleteMySessions)
+}
+
+// getMySessions godoc
+// @Summary List my login sessions
+// @Description Get the caller's active login sessions (one per login, kept across token refreshes), newest first. The session of the calling token is marked current.
+// @Tags Login Sessions
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} model.LoginSession
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /sessions [get]
+func getMySessions(c *gin.Context) {
+	sessions, err := util.ListSessions(currentUserID(c), c.GetString(util.ContextSessionID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// deleteMySession godoc
+// @Summary Log out a session
+// @Description Revoke the tokens of one of the caller's login sessions
+// @Tags Login Sessions
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Session ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /sessions/{id} [delete]
+func deleteMySession(c *gin.Context) {
+	err := util.RevokeUserSession(currentUserID(c), c.Param("id"))
+	if errors.Is(err, util.ErrLoginSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session logged out successfully"})
+}
+
+// deleteMySessions godoc
+// @Summary Log out everywhere
+// @Description Revoke the tokens of every login session of the caller, including the current one
+// @Tags Login Sessions
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /sessions [delete]
+func deleteMySessions(c *gin.Context) {
+	if err := util.RevokeUserSessions(currentUserID(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "All sessions logged out successfully"})
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"net/http"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutesMe registers the routes through which the caller manages their own
+// account
+func RoutesMe(rg *gin.RouterGroup) {
+	me := rg.Group("/me")
+
+	me.POST("/password", util.TokenAuthMiddleware(), changeMyPassword)
+}
+
+// changeMyPassword godoc
+// @Summary Change my password
+// @Description Replace the caller's password after checking the current one. The caller's other sessions are logged out; the calling session stays. Expired credentials become valid again.
+// @Tags Me
+// @Accept  json
+// @Produce  json
+// @Param change body model.PasswordChange true "Current and new password"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /me/password [post]
+func changeMyPassword(c *gin.Context) {
+	var change model.PasswordChange
+	if err := c.ShouldBindJSON(&change); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := passwordSvc.Change(currentUserID(c), c.GetString(util.ContextSessionID), change); err != nil {
+		handlePasswordError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// defaultPasswordResetTTL is used when PASSWORD_RESET.TOKEN_TTL_MINUTES is
+// unset
+const defaultPasswordResetTTL = 30 * time.Minute
+
+// Password policy applied wherever a password is set. bcrypt only reads the
+// first 72 bytes of a password.
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 72
+)
+
+// PasswordService describes changing a password and the forgot-password
+// flow
+type PasswordService interface {
+	Change(userID int64, sessionID string, change model.PasswordChange) error
+	RequestReset(email string) error
+	Reset(reset model.PasswordReset) error
+}
+
+type passwordService struct {
+	users          func(id int64) (model.MUser, error)
+	usersByEmail   func(email string) (model.MUser, error)
+	setPassword    func(id int64, hash string) error
+	issueToken     func(userID int64, ttl time.Duration) (util.PasswordResetToken, error)
+	consumeToken   func(token string) (int64, error)
+	sendResetEmail func(user model.MUser, reset util.PasswordResetToken, link string)
+	sessions       func(userID int64, currentID string) (model.LoginSessions, error)
+	revokeSession  func(userID int64, familyID string) error
+	revokeSessions func(userID int64) error
+}
+
+var passwordSvc PasswordService = newPasswordService()
+
+func newPasswordService() *passwordService {
+	return &passwordService{
+		users:        repository.GetUserByID,
+		usersByEmail: repository.GetUserByEmail,
+		setPassword:  repository.SetUserPassword,
+		issueToken:   util.IssuePasswordResetToken,
+		consumeToken: util.ConsumePasswordResetToken,
+		sendResetEmail: func(user model.MUser, reset util.PasswordResetToken, link string) {
+			go util.SendPasswordResetEmail(user, reset, link)
+		},
+		sessions:       util.ListSessions,
+		revokeSession:  util.RevokeUserSession,
+		revokeSessions: util.RevokeUserSessions,
+	}
+}
+
+func passwordResetTTL() time.Duration {
+	if viper.IsSet("PASSWORD_RESET.TOKEN_TTL_MINUTES") && viper.GetInt("PASSWORD_RESET.TOKEN_TTL_MINUTES") > 0 {
+		return time.Duration(viper.GetInt("PASSWORD_RESET.TOKEN_TTL_MINUTES")) * time.Minute
+	}
+	return defaultPasswordResetTTL
+}
+
+// passwordResetLink is the link emailed with a reset token: PASSWORD_RESET.URL
+// carrying the token when that is configured, otherwise empty so the email
+// holds the bare token.
+func passwordResetLink(token string) string {
+	base := viper.GetString("PASSWORD_RESET.URL")
+	if base == "" {
+		return ""
+	}
+	return base + "?token=" + url.QueryEscape(token)
+}
+
+// passwordPolicyIssue describes how password breaks the password policy, or
+// returns "" when it complies
+func passwordPolicyIssue(field, password string) string {
+	switch {
+	case len(password) < minPasswordLength:
+		return fmt.Sprintf("%s must be at least %d characters", field, minPasswordLength)
+	case len(password) > maxPasswordLength:
+		return fmt.Sprintf("%s must be at most %d bytes", field, maxPasswordLength)
+	case !strings.ContainsFunc(password, unicode.IsLetter) || !strings.ContainsFunc(password, unicode.IsDigit):
+		return field + " must contain a letter and a digit"
+	}
+	return ""
+}
+
+func validateNewPassword(password string) error {
+	if issue := passwordPolicyIssue("newPassword", password); issue != "" {
+		return &ValidationError{Fields: map[string]string{"newPassword": issue}}
+	}
+	return nil
+}
+
+func hashPassword(password string) (string, error) {
+	return util.HashPassword(password, bcrypt.DefaultCost)
+}
+
+// Change sets a new password for a user who knows their current one. The
+// user's other sessions are logged out; sessionID, the caller's, is kept.
+func (s *passwordService) Change(userID int64, sessionID string, change model.PasswordChange) error {
+	if err := validateNewPassword(change.NewPassword); err != nil {
+		return err
+	}
+
+	user, err := s.users(userID)
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 {
+		return repository.ErrUserNotFound
+	}
+	if !util.CheckPasswordHash(change.CurrentPassword, user.Password) {
+		return &ValidationError{Fields: map[string]string{"currentPassword": "currentPassword is incorrect"}}
+	}
+	if change.NewPassword == change.CurrentPassword {
+		return &ValidationError{Fields: map[string]string{"newPassword": "newPassword must differ from the current password"}}
+	}
+
+	hash, err := hashPassword(change.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.setPassword(userID, hash); err != nil {
+		return err
+	}
+
+	sessions, err := s.sessions(userID, sessionID)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if session.Current {
+			continue
+		}
+		if err := s.revokeSession(userID, session.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RequestReset emails a reset token to the enabled account owning email.
+// Unknown or disabled accounts are ignored without an error, so the answer
+// does not reveal which emails are registered.
+func (s *passwordService) RequestReset(email string) error {
+	user, err := s.usersByEmail(strings.TrimSpace(email))
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 || !user.Enabled {
+		return nil
+	}
+
+	reset, err := s.issueToken(user.ID, passwordResetTTL())
+	if err != nil {
+		return err
+	}
+	s.sendResetEmail(user, reset, passwordResetLink(reset.Token))
+	return nil
+}
+
+// Reset sets a new password with a reset token and logs out every session of
+// the user. The token is only spent once the new password is acceptable.
+func (s *passwordService) Reset(reset model.PasswordReset) error {
+	if err := validateNewPassword(reset.NewPassword); err != nil {
+		return err
+	}
+
+	userID, err := s.consumeToken(reset.Token)
+	if err != nil {
+		return err
+	}
+
+	hash, err := hashPassword(reset.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.setPassword(userID, hash); err != nil {
+		return err
+	}
+	return s.revokeSessions(userID)
+}
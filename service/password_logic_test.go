@@ -0,0 +1,133 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakePasswordStore records the writes of the password service
+type fakePasswordStore struct {
+	hashes    map[int64]string
+	tokens    map[string]int64
+	emailed   []string
+	revoked   []string
+	loggedOut []int64
+}
+
+func newTestPasswordService(t *testing.T) (*passwordService, *fakePasswordStore) {
+	hash, err := util.HashPassword("old-secret", bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := map[int64]model.MUser{
+		2: {ID: 2, UserName: "budi", Password: hash, Email: "budi@scopex.local", Enabled: true},
+		3: {ID: 3, UserName: "anduk", Password: hash, Email: "anduk@scopex.local"},
+	}
+	store := &fakePasswordStore{hashes: map[int64]string{}, tokens: map[string]int64{}}
+
+	svc := newPasswordService()
+	svc.users = func(id int64) (model.MUser, error) { return users[id], nil }
+	svc.usersByEmail = func(email string) (model.MUser, error) {
+		for _, u := range users {
+			if u.Email == email {
+				return u, nil
+			}
+		}
+		return model.MUser{}, nil
+	}
+	svc.setPassword = func(id int64, hash string) error {
+		store.hashes[id] = hash
+		return nil
+	}
+	svc.issueToken = func(userID int64, ttl time.Duration) (util.PasswordResetToken, error) {
+		token := "token-" + users[userID].UserName
+		store.tokens[token] = userID
+		return util.PasswordResetToken{Token: token, UserID: userID, ExpiresAt: time.Now().Add(ttl)}, nil
+	}
+	svc.consumeToken = func(token string) (int64, error) {
+		userID, ok := store.tokens[token]
+		if !ok {
+			return 0, util.ErrInvalidResetToken
+		}
+		delete(store.tokens, token)
+		return userID, nil
+	}
+	svc.sendResetEmail = func(user model.MUser, reset util.PasswordResetToken, link string) {
+		store.emailed = append(store.emailed, user.Email)
+	}
+	svc.sessions = func(userID int64, currentID string) (model.LoginSessions, error) {
+		return model.LoginSessions{{ID: "laptop", Current: currentID == "laptop"}, {ID: "phone", Current: currentID == "phone"}}, nil
+	}
+	svc.revokeSession = func(userID int64, familyID string) error {
+		store.revoked = append(store.revoked, familyID)
+		return nil
+	}
+	svc.revokeSessions = func(userID int64) error {
+		store.loggedOut = append(store.loggedOut, userID)
+		return nil
+	}
+	return svc, store
+}
+
+func TestChangePassword(t *testing.T) {
+	svc, store := newTestPasswordService(t)
+
+	err := svc.Change(2, "laptop", model.PasswordChange{CurrentPassword: "wrong", NewPassword: "new-secret1"})
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Contains(t, validationErr.Fields, "currentPassword")
+
+	err = svc.Change(2, "laptop", model.PasswordChange{CurrentPassword: "old-secret", NewPassword: "short"})
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Contains(t, validationErr.Fields, "newPassword")
+	assert.Empty(t, store.hashes)
+
+	err = svc.Change(2, "laptop", model.PasswordChange{CurrentPassword: "old-secret", NewPassword: "new-secret1"})
+	assert.NoError(t, err)
+	assert.True(t, util.CheckPasswordHash("new-secret1", store.hashes[2]))
+	// the calling session survives the change
+	assert.Equal(t, []string{"phone"}, store.revoked)
+
+	err = svc.Change(9, "laptop", model.PasswordChange{CurrentPassword: "old-secret", NewPassword: "new-secret1"})
+	assert.ErrorIs(t, err, repository.ErrUserNotFound)
+}
+
+func TestRequestResetIgnoresUnknownAndDisabledAccounts(t *testing.T) {
+	svc, store := newTestPasswordService(t)
+
+	assert.NoError(t, svc.RequestReset("nobody@scopex.local"))
+	assert.NoError(t, svc.RequestReset("anduk@scopex.local"))
+	assert.Empty(t, store.emailed)
+	assert.Empty(t, store.tokens)
+
+	assert.NoError(t, svc.RequestReset(" budi@scopex.local "))
+	assert.Equal(t, []string{"budi@scopex.local"}, store.emailed)
+	assert.Contains(t, store.tokens, "token-budi")
+}
+
+func TestResetPassword(t *testing.T) {
+	svc, store := newTestPasswordService(t)
+	assert.NoError(t, svc.RequestReset("budi@scopex.local"))
+
+	// an unacceptable password does not spend the token
+	err := svc.Reset(model.PasswordReset{Token: "token-budi", NewPassword: "short"})
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Contains(t, store.tokens, "token-budi")
+
+	err = svc.Reset(model.PasswordReset{Token: "token-budi", NewPassword: "new-secret1"})
+	assert.NoError(t, err)
+	assert.True(t, util.CheckPasswordHash("new-secret1", store.hashes[2]))
+	assert.Equal(t, []int64{2}, store.loggedOut)
+
+	err = svc.Reset(model.PasswordReset{Token: "token-budi", NewPassword: "newer-secret1"})
+	assert.ErrorIs(t, err, util.ErrInvalidResetToken)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutesPassword registers the forgot-password routes, which need no login.
+// Changing a known password is POST /me/password.
+func RoutesPassword(rg *gin.RouterGroup) {
+	password := rg.Group("/password")
+
+	password.POST("/forgot", forgotPassword)
+	password.POST("/reset", resetPassword)
+}
+
+// forgotPassword godoc
+// @Summary Request a password reset
+// @Description Email a single-use password reset token to the enabled account with this email. The token expires after PASSWORD_RESET.TOKEN_TTL_MINUTES and voids earlier ones; the email links to PASSWORD_RESET.URL when configured, otherwise it holds the bare token. The answer is the same whether or not the email is registered.
+// @Tags Password
+// @Accept  json
+// @Produce  json
+// @Param request body model.PasswordResetRequest true "Account email"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /password/forgot [post]
+func forgotPassword(c *gin.Context) {
+	var req model.PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := passwordSvc.RequestReset(req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to request a password reset"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the email belongs to an account, a reset link has been sent"})
+}
+
+// resetPassword godoc
+// @Summary Reset a password
+// @Description Set a new password with an emailed reset token. The token works once, and every session of the user is logged out. Expired credentials become valid again.
+// @Tags Password
+// @Accept  json
+// @Produce  json
+// @Param reset body model.PasswordReset true "Reset token and new password"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /password/reset [post]
+func resetPassword(c *gin.Context) {
+	var reset model.PasswordReset
+	if err := c.ShouldBindJSON(&reset); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := passwordSvc.Reset(reset); err != nil {
+		handlePasswordError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
+
+func handlePasswordError(c *gin.Context, err error) {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error(), "details": validationErr.Fields})
+	case errors.Is(err, util.ErrInvalidResetToken):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -3,13 +3,84 @@ package service
 import (
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
+	model "github.com/shravanasati/scopex-go-assignment/model"
 	repository "github.com/shravanasati/scopex-go-assignment/repository"
 	"github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/spf13/viper"
+)
+
+// Report roll-up modes configured through REPORT.ROLLUP
+const (
+	rollupSession = "session"
+	rollupDay     = "day"
 )
 
+// defaultDayPresentPercent is used when REPORT.DAY_PRESENT_PERCENT is unset
+const defaultDayPresentPercent = 50.0
+
+// fetchAttendanceReport aggregates attendance per record (REPORT.ROLLUP
+// "session", the default) or per day (REPORT.ROLLUP "day"), where a day is
+// present once REPORT.DAY_PRESENT_PERCENT of its sessions were attended,
+// for the students of a scope. Each report also counts the calendar working
+// days left unmarked, except for section scopes because a section does not
+// meet on every working day.
+func fetchAttendanceReport(scope model.ReportScope, startDate, endDate string) (model.AttendanceReports, error) {
+	var reports model.AttendanceReports
+	var err error
+	if viper.GetString("REPORT.ROLLUP") != rollupDay {
+		reports, err = repository.GetScopedAttendanceReport(scope, startDate, endDate)
+	} else {
+		reports, err = repository.GetScopedDailyAttendanceReport(scope, startDate, endDate, dayPresentPercent())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if scope.SectionID != 0 {
+		return reports, nil
+	}
+	if err := attachUnmarkedWorkingDays(reports, startDate, endDate); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// attachUnmarkedWorkingDays fills in how many working days of the range each
+// student has no attendance record for
+func attachUnmarkedWorkingDays(reports model.AttendanceReports, startDate, endDate string) error {
+	if len(reports) == 0 {
+		return nil
+	}
+
+	workingDays, err := calendarSvc.WorkingDays(startDate, endDate)
+	if err != nil {
+		return err
+	}
+	marked, err := repository.GetMarkedDates(startDate, endDate)
+	if err != nil {
+		return err
+	}
+
+	for i := range reports {
+		reports[i].UnmarkedWorkingDays = countUnmarkedWorkingDays(workingDays, marked[reports[i].StudentID])
+	}
+	return nil
+}
+
+// dayPresentPercent returns the share of a day's sessions a student must
+// attend for the day to count as present.
+func dayPresentPercent() float64 {
+	if viper.IsSet("REPORT.DAY_PRESENT_PERCENT") {
+		return viper.GetFloat64("REPORT.DAY_PRESENT_PERCENT")
+	}
+	return defaultDayPresentPercent
+}
+
 // GenerateWeeklyReport generates and prints weekly attendance reports for all students
 func GenerateWeeklyReport() {
 	log.Println("Starting Weekly Attendance Report Generation...")
@@ -18,7 +89,7 @@ func GenerateWeeklyReport() {
 	endDate := now.Format("2006-01-02")
 	startDate := now.AddDate(0, 0, -7).Format("2006-01-02")
 
-	reports, err := repository.GetAttendanceReport(startDate, endDate)
+	reports, err := fetchAttendanceReport(model.ReportScope{}, startDate, endDate)
 	if err != nil {
 		log.Println("Error fetching weekly attendance report: ", err)
 		return
@@ -28,8 +99,8 @@ func GenerateWeeklyReport() {
 
 	for _, report := range reports {
 		output := fmt.Sprintf(
-			"Weekly Report for %s (%s)\nPeriod: %s to %s\nPresent: %d, Absent: %d\n-----------------------------",
-			report.StudentName, report.StudentEmail, startDate, endDate, report.PresentCount, report.AbsentCount,
+			"Weekly Report for %s (%s)\nPeriod: %s to %s\nPresent: %d, Absent: %d, Unmarked working days: %d\n%s-----------------------------",
+			report.StudentName, report.StudentEmail, startDate, endDate, report.PresentCount, report.AbsentCount, report.UnmarkedWorkingDays, formatStatusCounts(report.StatusCounts),
 		)
 		fmt.Println(output)
 
@@ -52,7 +123,7 @@ func GenerateMonthlyReport() {
 	endDate := now.Format("2006-01-02")
 	startDate := now.AddDate(0, -1, 0).Format("2006-01-02")
 
-	reports, err := repository.GetAttendanceReport(startDate, endDate)
+	reports, err := fetchAttendanceReport(model.ReportScope{}, startDate, endDate)
 	if err != nil {
 		log.Println("Error fetching monthly attendance report: ", err)
 		return
@@ -62,8 +133,8 @@ func GenerateMonthlyReport() {
 
 	for _, report := range reports {
 		output := fmt.Sprintf(
-			"Monthly Report for %s (%s)\nPeriod: %s to %s\nPresent: %d, Absent: %d\n-----------------------------",
-			report.StudentName, report.StudentEmail, startDate, endDate, report.PresentCount, report.AbsentCount,
+			"Monthly Report for %s (%s)\nPeriod: %s to %s\nPresent: %d, Absent: %d, Unmarked working days: %d\n%s-----------------------------",
+			report.StudentName, report.StudentEmail, startDate, endDate, report.PresentCount, report.AbsentCount, report.UnmarkedWorkingDays, formatStatusCounts(report.StatusCounts),
 		)
 		fmt.Println(output)
 
@@ -78,3 +149,13 @@ func GenerateMonthlyReport() {
 	wg.Wait()
 	log.Println("Monthly Attendance Report Generation Completed.")
 }
+
+// formatStatusCounts renders the per-status breakdown of a report, one line
+// per status
+func formatStatusCounts(counts []model.StatusCount) string {
+	var b strings.Builder
+	for _, sc := range counts {
+		fmt.Fprintf(&b, "  %s: %d\n", sc.Label, sc.Count)
+	}
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"slices"
+	"strings"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+)
+
+// RoleService describes the role catalogue and role assignment operations
+// the HTTP layer relies on. Permissions are checked by util.RequirePermission
+// against the token, so assignments apply from the user's next login or token refresh.
+type RoleService interface {
+	GetRoles() (model.Roles, error)
+	GetUserGrants(userID int64) (model.Grants, error)
+	SetUserRoles(userID int64, roles []string) (model.Grants, error)
+}
+
+type roleService struct {
+	repo  repository.RoleRepository
+	users func(id int64) (model.MUser, error)
+}
+
+var roleSvc RoleService = newRoleService(repository.RoleRepo)
+
+func newRoleService(repo repository.RoleRepository) *roleService {
+	return &roleService{repo: repo, users: repository.GetUserByID}
+}
+
+func (s *roleService) GetRoles() (model.Roles, error) {
+	return s.repo.GetRoles()
+}
+
+func (s *roleService) GetUserGrants(userID int64) (model.Grants, error) {
+	if err := s.checkUser(userID); err != nil {
+		return model.Grants{}, err
+	}
+	return s.repo.GetUserGrants(userID)
+}
+
+// SetUserRoles replaces the roles of a user and returns what they now grant.
+// An empty list revokes every role.
+func (s *roleService) SetUserRoles(userID int64, roles []string) (model.Grants, error) {
+	normalized := make([]string, 0, len(roles))
+	for _, role := range roles {
+		role = strings.ToLower(strings.TrimSpace(role))
+		if role == "" {
+			return model.Grants{}, &ValidationError{Fields: map[string]string{"roles": "role codes must not be empty"}}
+		}
+		if !slices.Contains(normalized, role) {
+			normalized = append(normalized, role)
+		}
+	}
+
+	if err := s.checkUser(userID); err != nil {
+		return model.Grants{}, err
+	}
+	if err := s.repo.SetUserRoles(userID, normalized); err != nil {
+		return model.Grants{}, err
+	}
+	return s.repo.GetUserGrants(userID)
+}
+
+// checkUser fails with repository.ErrUserNotFound for unknown users
+func (s *roleService) checkUser(userID int64) error {
+	user, err := s.users(userID)
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 {
+		return repository.ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *mockRoleRepository) GetRoles() (model.Roles, error) {
+	args := m.Called()
+	return args.Get(0).(model.Roles), args.Error(1)
+}
+
+func (m *mockRoleRepository) GetUserGrants(userID int64) (model.Grants, error) {
+	args := m.Called(userID)
+	return args.Get(0).(model.Grants), args.Error(1)
+}
+
+func (m *mockRoleRepository) SetUserRoles(userID int64, roles []string) error {
+	args := m.Called(userID, roles)
+	return args.Error(0)
+}
+
+func newTestRoleService(repo *mockRoleRepository) *roleService {
+	svc := newRoleService(repo)
+	svc.users = func(id int64) (model.MUser, error) {
+		if id == 2 {
+			return model.MUser{ID: 2, UserName: "budi"}, nil
+		}
+		return model.MUser{}, nil
+	}
+	return svc
+}
+
+func TestSetUserRolesNormalizesCodes(t *testing.T) {
+	repo := &mockRoleRepository{}
+	repo.On("SetUserRoles", int64(2), []string{"teacher", "auditor"}).Return(nil)
+	repo.On("GetUserGrants", int64(2)).Return(model.Grants{Roles: []string{"auditor", "teacher"}, Permissions: []string{"students:read"}}, nil)
+	svc := newTestRoleService(repo)
+
+	grants, err := svc.SetUserRoles(2, []string{" Teacher", "auditor", "teacher"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"auditor", "teacher"}, grants.Roles)
+	repo.AssertExpectations(t)
+}
+
+func TestSetUserRolesValidation(t *testing.T) {
+	repo := &mockRoleRepository{}
+	svc := newTestRoleService(repo)
+
+	_, err := svc.SetUserRoles(2, []string{"teacher", " "})
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+
+	_, err = svc.SetUserRoles(99, []string{"teacher"})
+	assert.ErrorIs(t, err, repository.ErrUserNotFound)
+
+	repo.AssertNotCalled(t, "SetUserRoles", mock.Anything, mock.Anything)
+}
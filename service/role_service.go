@@ -0,0 +1,127 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutesRole registers the role catalogue and role assignment routes
+func RoutesRole(rg *gin.RouterGroup) {
+	role := rg.Group("/roles")
+
+	role.GET("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermRolesRead), getRoles)
+
+	user := rg.Group("/user")
+
+	user.GET("/:id/roles", util.TokenAuthMiddleware(), util.RequirePermission(model.PermRolesRead), getUserRoles)
+	user.PUT("/:id/roles", util.TokenAuthMiddleware(), util.RequirePermission(model.PermRolesWrite), setUserRoles)
+}
+
+// getRoles godoc
+// @Summary List roles
+// @Description Get every role with the permissions it grants
+// @Tags Roles
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} model.Role
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /roles/ [get]
+func getRoles(c *gin.Context) {
+	roles, err := roleSvc.GetRoles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch roles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
+
+// getUserRoles godoc
+// @Summary Get the roles of a user
+// @Description Get the roles of a user and the permissions they grant
+// @Tags Roles
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Success 200 {object} model.Grants
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /user/{id}/roles [get]
+func getUserRoles(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	grants, err := roleSvc.GetUserGrants(id)
+	if err != nil {
+		handleRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, grants)
+}
+
+// setUserRoles godoc
+// @Summary Assign roles to a user
+// @Description Replace the roles of a user; an empty list revokes them all. The new permissions apply from the user's next login or token refresh.
+// @Tags Roles
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param roles body model.UserRoles true "Role codes"
+// @Success 200 {object} model.Grants
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /user/{id}/roles [put]
+func setUserRoles(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var body model.UserRoles
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	grants, err := roleSvc.SetUserRoles(id, body.Roles)
+	if err != nil {
+		handleRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, grants)
+}
+
+func handleRoleError(c *gin.Context, err error) {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error(), "details": validationErr.Fields})
+	case errors.Is(err, repository.ErrRoleNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+)
+
+// ErrSessionDateMismatch is returned when attendance names a session held on
+// a different date.
+var ErrSessionDateMismatch = errors.New("session is not held on the attendance date")
+
+// SessionService describes the session operations the HTTP layer relies on.
+type SessionService interface {
+	CreateSession(session model.Session) (model.Session, error)
+	GetSessionsByDate(date string) (model.Sessions, error)
+	GetSessionByID(id int64) (model.Session, error)
+	DeleteSession(id, deletedBy int64) error
+}
+
+type sessionService struct {
+	repo repository.SessionRepository
+}
+
+var sessionSvc SessionService = newSessionService(repository.SessionRepo)
+
+func newSessionService(repo repository.SessionRepository) SessionService {
+	return &sessionService{repo: repo}
+}
+
+func (s *sessionService) CreateSession(session model.Session) (model.Session, error) {
+	if err := validateSessionInput(session); err != nil {
+		return model.Session{}, err
+	}
+
+	id, err := s.repo.CreateSession(session)
+	if err != nil {
+		return model.Session{}, err
+	}
+
+	return s.repo.GetSessionByID(id)
+}
+
+func (s *sessionService) GetSessionsByDate(date string) (model.Sessions, error) {
+	return s.repo.GetSessionsByDate(date)
+}
+
+func (s *sessionService) GetSessionByID(id int64) (model.Session, error) {
+	return s.repo.GetSessionByID(id)
+}
+
+// DeleteSession deletes a session and its attendance. Removing marks before
+// the lock window needs the attendance:override_lock permission.
+func (s *sessionService) DeleteSession(id, deletedBy int64) error {
+	session, err := s.repo.GetSessionByID(id)
+	if err != nil {
+		return err
+	}
+
+	overridden, err := lockSvc.Authorize(session.Date, deletedBy)
+	if err != nil {
+		return err
+	}
+	removed, err := s.repo.DeleteSession(id, deletedBy)
+	if err != nil {
+		return err
+	}
+
+	if overridden {
+		for _, m := range removed {
+			lockSvc.RecordOverride(model.LockActionDelete, m, deletedBy)
+		}
+	}
+	return nil
+}
+
+func validateSessionInput(session model.Session) error {
+	issues := make(map[string]string)
+
+	if err := validateISODate(session.Date); err != nil {
+		issues["date"] = err.Error()
+	}
+	if err := validateTimeRange(session.StartTime, session.EndTime); err != nil {
+		issues["time"] = err.Error()
+	}
+	if session.SectionID != 0 && session.DepartmentID != 0 {
+		issues["department_id"] = "department_id is only set for sessions without a section; a section's sessions belong to its course's department"
+	}
+
+	if len(issues) > 0 {
+		return &ValidationError{Fields: issues}
+	}
+
+	return nil
+}
+
+// validateAttendanceSession checks that the session attendance is marked
+// against exists and is held on the attendance date, and returns it. Session
+// 0 stands for the whole day and is always valid.
+func validateAttendanceSession(sessionID int64, date string) (model.Session, error) {
+	if sessionID == 0 {
+		return model.Session{}, nil
+	}
+
+	session, err := sessionSvc.GetSessionByID(sessionID)
+	if err != nil {
+		return model.Session{}, err
+	}
+	if session.Date != date {
+		return model.Session{}, ErrSessionDateMismatch
+	}
+
+	return session, nil
+}
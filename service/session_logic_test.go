@@ -0,0 +1,123 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestValidateSessionInput(t *testing.T) {
+	err := validateSessionInput(model.Session{Date: "2023-10-27", StartTime: "09:00", EndTime: "09:50"})
+	assert.NoError(t, err)
+
+	err = validateSessionInput(model.Session{Date: "2023-10-27", StartTime: "10:00", EndTime: "09:50"})
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "end_time must be after start_time", validationErr.Fields["time"])
+
+	err = validateSessionInput(model.Session{Date: "27/10/2023", StartTime: "9am", EndTime: "09:50"})
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Contains(t, validationErr.Fields, "date")
+	assert.Equal(t, "start_time must be in HH:MM format", validationErr.Fields["time"])
+
+	err = validateSessionInput(model.Session{Date: "2023-10-27", StartTime: "09:00", EndTime: "09:50", DepartmentID: 2})
+	assert.NoError(t, err)
+
+	err = validateSessionInput(model.Session{Date: "2023-10-27", StartTime: "09:00", EndTime: "09:50", SectionID: 1, DepartmentID: 2})
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Contains(t, validationErr.Fields, "department_id")
+}
+
+func TestDeleteSessionRejectsLockedDate(t *testing.T) {
+	withLockPolicy(t, 7)
+	withTestLockService(t, newTestLockService(&mockLockOverrideRepository{}))
+	repo := &mockSessionRepository{}
+	svc := newSessionService(repo)
+
+	repo.On("GetSessionByID", int64(4)).Return(model.Session{ID: 4, Date: "2023-10-02"}, nil)
+
+	err := svc.DeleteSession(4, 2)
+
+	assert.ErrorIs(t, err, ErrAttendanceLocked)
+	repo.AssertNotCalled(t, "DeleteSession", mock.Anything, mock.Anything)
+}
+
+func TestDeleteSessionRecordsLockOverrides(t *testing.T) {
+	withLockPolicy(t, 7)
+	overrides := &mockLockOverrideRepository{}
+	withTestLockService(t, newTestLockService(overrides))
+	repo := &mockSessionRepository{}
+	svc := newSessionService(repo)
+
+	repo.On("GetSessionByID", int64(4)).Return(model.Session{ID: 4, Date: "2023-10-02"}, nil)
+	repo.On("DeleteSession", int64(4), int64(1)).Return(model.Attendances{
+		{ID: 11, StudentID: 1, Date: "2023-10-02", SessionID: 4, Status: "Present"},
+		{ID: 12, StudentID: 2, Date: "2023-10-02", SessionID: 4, Status: "Absent"},
+	}, nil)
+	overrides.On("CreateOverride", model.LockOverride{
+		Action: model.LockActionDelete, AttendanceID: 11, StudentID: 1, Date: "2023-10-02", SessionID: 4, Status: "Present", UserID: 1,
+	}).Return(nil).Once()
+	overrides.On("CreateOverride", model.LockOverride{
+		Action: model.LockActionDelete, AttendanceID: 12, StudentID: 2, Date: "2023-10-02", SessionID: 4, Status: "Absent", UserID: 1,
+	}).Return(nil).Once()
+
+	err := svc.DeleteSession(4, 1)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	overrides.AssertExpectations(t)
+}
+
+func TestSessionHandlersOutsideTeacherDepartments(t *testing.T) {
+	withTeacher(t)
+	courses := &mockCourseRepository{}
+	originalCourses := courseSvc
+	courseSvc = newCourseService(courses, &mockStudentRepository{})
+	t.Cleanup(func() { courseSvc = originalCourses })
+	sessions := &mockSessionRepository{}
+	originalSessions := sessionSvc
+	sessionSvc = newSessionService(sessions)
+	t.Cleanup(func() { sessionSvc = originalSessions })
+
+	courses.On("GetSectionByID", int64(7)).Return(model.Section{ID: 7, CourseID: 10}, nil)
+	courses.On("GetCourseByID", int64(10)).Return(model.Course{ID: 10, DepartmentID: 5}, nil)
+	courses.On("GetSectionByID", int64(8)).Return(model.Section{ID: 8, CourseID: 11}, nil)
+	courses.On("GetCourseByID", int64(11)).Return(model.Course{ID: 11, DepartmentID: 3}, nil)
+	sessions.On("GetSessionByID", int64(4)).Return(model.Session{ID: 4, Date: "2023-10-27", DepartmentID: 5}, nil)
+	sessions.On("GetSessionByID", int64(6)).Return(model.Session{ID: 6, Date: "2023-10-27", SectionID: 8}, nil)
+	sessions.On("DeleteSession", int64(6), int64(2)).Return(model.Attendances{}, nil)
+
+	rr, resp := performTeacherRequest(createSession, http.MethodPost, "/class-sessions/", nil,
+		model.Session{Date: "2023-10-27", StartTime: "09:00", EndTime: "09:50", SectionID: 7})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, ErrOutsideDepartments.Error(), resp["error"])
+
+	rr, _ = performTeacherRequest(createSession, http.MethodPost, "/class-sessions/", nil,
+		model.Session{Date: "2023-10-27", StartTime: "09:00", EndTime: "09:50", DepartmentID: 5})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr, _ = performTeacherRequest(deleteSession, http.MethodDelete, "/class-sessions/4", gin.Params{{Key: "id", Value: "4"}}, nil)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr, _ = performTeacherRequest(createCheckinToken, http.MethodPost, "/class-sessions/4/checkin-token", gin.Params{{Key: "id", Value: "4"}}, nil)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr, _ = performTeacherRequest(getCheckinQR, http.MethodGet, "/class-sessions/4/checkin-qr", gin.Params{{Key: "id", Value: "4"}}, nil)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr, _ = performTeacherRequest(deleteSession, http.MethodDelete, "/class-sessions/6", gin.Params{{Key: "id", Value: "6"}}, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	sessions.AssertNotCalled(t, "DeleteSession", int64(4), mock.Anything)
+}
+
+func TestValidateAttendanceSessionWholeDay(t *testing.T) {
+	session, err := validateAttendanceSession(0, "2023-10-27")
+	assert.NoError(t, err)
+	assert.Zero(t, session.SectionID)
+}
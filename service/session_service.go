@@ -0,0 +1,320 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutesSession registers the class session routes. They live under
+// /class-sessions because /sessions holds the caller's login sessions.
+func RoutesSession(rg *gin.RouterGroup) {
+	session := rg.Group("/class-sessions")
+
+	session.POST("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermSessionsWrite), createSession)
+	session.GET("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermSessionsRead), getSessionsByDate)
+	session.GET("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermSessionsRead), getSessionByID)
+	session.DELETE("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermSessionsWrite), deleteSession)
+	session.POST("/:id/checkin-token", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceMark), createCheckinToken)
+	session.GET("/:id/checkin-qr", util.TokenAuthMiddleware(), util.RequirePermission(model.PermAttendanceMark), getCheckinQR)
+}
+
+// createSession godoc
+// @Summary Create a session
+// @Description Create a lecture or period that attendance can be marked against. Sessions of a section only accept marks for students enrolled in it and belong to its course's department; other sessions may name a department_id. When the session is closed, the department's students without a record are marked Absent. Teachers can only create, delete and issue check-in tokens for sessions of their departments.
+// @Tags Class Sessions
+// @Accept  json
+// @Produce  json
+// @Param session body model.Session true "Session"
+// @Success 201 {object} model.Session
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /class-sessions/ [post]
+func createSession(c *gin.Context) {
+	var session model.Session
+	if err := c.ShouldBindJSON(&session); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !authorizeSession(c, session) {
+		return
+	}
+
+	created, err := sessionSvc.CreateSession(session)
+	if err != nil {
+		handleSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// getSessionsByDate godoc
+// @Summary List sessions
+// @Description Get the sessions held on a date
+// @Tags Class Sessions
+// @Accept  json
+// @Produce  json
+// @Param date query string true "Date (YYYY-MM-DD)"
+// @Success 200 {array} model.Session
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /class-sessions/ [get]
+func getSessionsByDate(c *gin.Context) {
+	date := c.Query("date")
+	if err := validateISODate(date); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sessions, err := sessionSvc.GetSessionsByDate(date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions"})
+		return
+	}
+	if sessions == nil {
+		sessions = model.Sessions{}
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// getSessionByID godoc
+// @Summary Get a session by ID
+// @Description Get details of a specific session by ID
+// @Tags Class Sessions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Session ID"
+// @Success 200 {object} model.Session
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Security bearerAuth
+// @Router /class-sessions/{id} [get]
+func getSessionByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	session, err := sessionSvc.GetSessionByID(id)
+	if err != nil {
+		handleSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// deleteSession godoc
+// @Summary Delete a session
+// @Description Delete a session together with the attendance marked for it. Each removed mark is recorded in the attendance audit log. Deleting a session dated before the attendance lock window needs attendance:override_lock.
+// @Tags Class Sessions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Session ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /class-sessions/{id} [delete]
+func deleteSession(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if !authorizeSessionID(c, id) {
+		return
+	}
+
+	if err := sessionSvc.DeleteSession(id, currentUserID(c)); err != nil {
+		handleSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session deleted successfully"})
+}
+
+// createCheckinToken godoc
+// @Summary Issue a check-in token
+// @Description Sign a short-lived token students can check in to the session with. It expires after CHECKIN.TOKEN_TTL_SECONDS. Tokens are only issued for a session that is under way: dated today, not closed and between its start and end times.
+// @Tags Class Sessions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Session ID"
+// @Success 201 {object} util.CheckinToken
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /class-sessions/{id}/checkin-token [post]
+func createCheckinToken(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	if !authorizeSessionID(c, id) {
+		return
+	}
+
+	token, err := checkinSvc.IssueToken(id)
+	if err != nil {
+		handleSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+// getCheckinQR godoc
+// @Summary Check-in QR code
+// @Description Issue a fresh check-in token for the session and render it as a QR code to display in class. The session must be under way, as for checkin-token. The code links to CHECKIN.URL when configured, otherwise it holds the bare token. The token expiry is returned in the X-Checkin-Expires-At header.
+// @Tags Class Sessions
+// @Accept  json
+// @Produce  png
+// @Produce  image/svg+xml
+// @Param id path int true "Session ID"
+// @Param format query string false "Image format" Enums(png, svg) default(png)
+// @Param size query int false "PNG width and height in pixels" default(256)
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /class-sessions/{id}/checkin-qr [get]
+func getCheckinQR(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	format := c.DefaultQuery("format", "png")
+	size, err := strconv.Atoi(c.DefaultQuery("size", "256"))
+	if err != nil || size < 64 || size > 2048 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "size must be between 64 and 2048"})
+		return
+	}
+	if format != "png" && format != "svg" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be png or svg"})
+		return
+	}
+	if !authorizeSessionID(c, id) {
+		return
+	}
+
+	token, err := checkinSvc.IssueToken(id)
+	if err != nil {
+		handleSessionError(c, err)
+		return
+	}
+
+	content := checkinQRContent(token.Token)
+	var image []byte
+	contentType := "image/png"
+	if format == "svg" {
+		image, err = util.RenderQRSVG(content)
+		contentType = "image/svg+xml"
+	} else {
+		image, err = util.RenderQRPNG(content, size)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render QR code: " + err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Checkin-Expires-At", strconv.FormatInt(token.ExpiresAt, 10))
+	c.Data(http.StatusOK, contentType, image)
+}
+
+// authorizeSession fails unless a teacher caller teaches in the department
+// the session belongs to
+func authorizeSession(c *gin.Context, session model.Session) bool {
+	scope, ok := callerScope(c)
+	if !ok {
+		return false
+	}
+	if !scope.restricted {
+		return true
+	}
+	return sessionInScope(c, scope, session)
+}
+
+// authorizeSessionID is authorizeSession for a stored session
+func authorizeSessionID(c *gin.Context, id int64) bool {
+	scope, ok := callerScope(c)
+	if !ok {
+		return false
+	}
+	if !scope.restricted {
+		return true
+	}
+
+	session, err := sessionSvc.GetSessionByID(id)
+	if err != nil {
+		handleSessionError(c, err)
+		return false
+	}
+	return sessionInScope(c, scope, session)
+}
+
+// sessionInScope checks the session's department against scope: the
+// department of its section's course, or its own department_id when it has
+// no section
+func sessionInScope(c *gin.Context, scope departmentScope, session model.Session) bool {
+	departmentID := session.DepartmentID
+	if session.SectionID != 0 {
+		section, err := courseSvc.GetSectionByID(session.SectionID)
+		if err != nil {
+			handleCourseError(c, err)
+			return false
+		}
+		course, err := courseSvc.GetCourseByID(section.CourseID)
+		if err != nil {
+			handleCourseError(c, err)
+			return false
+		}
+		departmentID = course.DepartmentID
+	}
+
+	if !scope.allows(departmentID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": ErrOutsideDepartments.Error()})
+		return false
+	}
+	return true
+}
+
+func handleSessionError(c *gin.Context, err error) {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error(), "details": validationErr.Fields})
+	case errors.Is(err, repository.ErrSessionNotFound), errors.Is(err, repository.ErrSectionNotFound), errors.Is(err, repository.ErrDepartmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrAttendanceLocked):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
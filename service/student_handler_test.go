@@ -9,6 +9,7 @@ import (
 
 	model "github.com/shravanasati/scopex-go-assignment/model"
 	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -19,14 +20,14 @@ type studentServiceMock struct {
 	mock.Mock
 }
 
-func (m *studentServiceMock) CreateStudent(student model.Student) (model.Student, error) {
-	args := m.Called(student)
+func (m *studentServiceMock) CreateStudent(student model.Student, scope departmentScope) (model.Student, error) {
+	args := m.Called(student, scope)
 	result, _ := args.Get(0).(model.Student)
 	return result, args.Error(1)
 }
 
-func (m *studentServiceMock) GetAllStudents(limit, offset int) (model.Students, error) {
-	args := m.Called(limit, offset)
+func (m *studentServiceMock) GetAllStudents(departmentID int64, scope departmentScope, limit, offset int) (model.Students, error) {
+	args := m.Called(departmentID, scope, limit, offset)
 	result, _ := args.Get(0).(model.Students)
 	return result, args.Error(1)
 }
@@ -37,14 +38,20 @@ func (m *studentServiceMock) GetStudentByID(id int64) (model.Student, error) {
 	return result, args.Error(1)
 }
 
-func (m *studentServiceMock) UpdateStudent(id int64, student model.Student) (model.Student, error) {
-	args := m.Called(id, student)
+func (m *studentServiceMock) GetStudentDepartments(ids []int64) (map[int64]int64, error) {
+	args := m.Called(ids)
+	result, _ := args.Get(0).(map[int64]int64)
+	return result, args.Error(1)
+}
+
+func (m *studentServiceMock) UpdateStudent(id int64, student model.Student, scope departmentScope) (model.Student, error) {
+	args := m.Called(id, student, scope)
 	result, _ := args.Get(0).(model.Student)
 	return result, args.Error(1)
 }
 
-func (m *studentServiceMock) DeleteStudent(id int64) error {
-	args := m.Called(id)
+func (m *studentServiceMock) DeleteStudent(id int64, scope departmentScope) error {
+	args := m.Called(id, scope)
 	return args.Error(0)
 }
 
@@ -61,12 +68,17 @@ func withMockStudentService(t *testing.T, mockSvc StudentService) {
 }
 
 func performJSONRequest(handler gin.HandlerFunc, method, path string, payload any) (*httptest.ResponseRecorder, map[string]any) {
+	return performJSONRequestWithParams(handler, method, path, nil, payload)
+}
+
+func performJSONRequestWithParams(handler gin.HandlerFunc, method, path string, params gin.Params, payload any) (*httptest.ResponseRecorder, map[string]any) {
 	body, _ := json.Marshal(payload)
 	req := httptest.NewRequest(method, path, bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(rr)
+	c.Params = params
 	c.Request = req
 
 	handler(c)
@@ -82,7 +94,7 @@ func performJSONRequest(handler gin.HandlerFunc, method, path string, payload an
 func TestCreateStudentReturnsValidationErrors(t *testing.T) {
 	mockSvc := &studentServiceMock{}
 	validationErr := &ValidationError{Fields: map[string]string{"email": "invalid"}}
-	mockSvc.On("CreateStudent", mock.AnythingOfType("model.Student")).Return(model.Student{}, validationErr)
+	mockSvc.On("CreateStudent", mock.AnythingOfType("model.Student"), departmentScope{}).Return(model.Student{}, validationErr)
 
 	withMockStudentService(t, mockSvc)
 
@@ -99,7 +111,7 @@ func TestCreateStudentReturnsValidationErrors(t *testing.T) {
 
 func TestCreateStudentRejectsDuplicateEmails(t *testing.T) {
 	mockSvc := &studentServiceMock{}
-	mockSvc.On("CreateStudent", mock.AnythingOfType("model.Student")).Return(model.Student{}, ErrDuplicateStudentEmail)
+	mockSvc.On("CreateStudent", mock.AnythingOfType("model.Student"), departmentScope{}).Return(model.Student{}, ErrDuplicateStudentEmail)
 
 	withMockStudentService(t, mockSvc)
 
@@ -117,7 +129,7 @@ func TestCreateStudentRejectsDuplicateEmails(t *testing.T) {
 func TestCreateStudentSuccess(t *testing.T) {
 	mockSvc := &studentServiceMock{}
 	expected := model.Student{ID: 1, Name: "Jane", Email: "jane@example.com", Department: "Science"}
-	mockSvc.On("CreateStudent", mock.AnythingOfType("model.Student")).Return(expected, nil)
+	mockSvc.On("CreateStudent", mock.AnythingOfType("model.Student"), departmentScope{}).Return(expected, nil)
 
 	withMockStudentService(t, mockSvc)
 
@@ -134,7 +146,7 @@ func TestCreateStudentSuccess(t *testing.T) {
 
 func TestDeleteStudentReturnsNotFound(t *testing.T) {
 	mockSvc := &studentServiceMock{}
-	mockSvc.On("DeleteStudent", int64(9)).Return(repository.ErrStudentNotFound)
+	mockSvc.On("DeleteStudent", int64(9), departmentScope{}).Return(repository.ErrStudentNotFound)
 
 	withMockStudentService(t, mockSvc)
 
@@ -173,7 +185,7 @@ func TestGetAllStudentsSuccess(t *testing.T) {
 		{ID: 1, Name: "John", Email: "john@example.com", Department: "Math"},
 		{ID: 2, Name: "Jane", Email: "jane@example.com", Department: "Science"},
 	}
-	mockSvc.On("GetAllStudents", 10, 0).Return(expected, nil)
+	mockSvc.On("GetAllStudents", int64(0), departmentScope{}, 10, 0).Return(expected, nil)
 
 	withMockStudentService(t, mockSvc)
 
@@ -234,7 +246,7 @@ func TestGetStudentByIDNotFound(t *testing.T) {
 func TestUpdateStudentSuccess(t *testing.T) {
 	mockSvc := &studentServiceMock{}
 	expected := model.Student{ID: 1, Name: "John Updated", Email: "john@example.com", Department: "Math"}
-	mockSvc.On("UpdateStudent", int64(1), mock.AnythingOfType("model.Student")).Return(expected, nil)
+	mockSvc.On("UpdateStudent", int64(1), mock.AnythingOfType("model.Student"), departmentScope{}).Return(expected, nil)
 
 	withMockStudentService(t, mockSvc)
 
@@ -263,7 +275,7 @@ func TestUpdateStudentSuccess(t *testing.T) {
 func TestUpdateStudentValidationErrors(t *testing.T) {
 	mockSvc := &studentServiceMock{}
 	validationErr := &ValidationError{Fields: map[string]string{"email": "invalid"}}
-	mockSvc.On("UpdateStudent", int64(1), mock.AnythingOfType("model.Student")).Return(model.Student{}, validationErr)
+	mockSvc.On("UpdateStudent", int64(1), mock.AnythingOfType("model.Student"), departmentScope{}).Return(model.Student{}, validationErr)
 
 	withMockStudentService(t, mockSvc)
 
@@ -291,7 +303,7 @@ func TestUpdateStudentValidationErrors(t *testing.T) {
 
 func TestUpdateStudentNotFound(t *testing.T) {
 	mockSvc := &studentServiceMock{}
-	mockSvc.On("UpdateStudent", int64(999), mock.AnythingOfType("model.Student")).Return(model.Student{}, repository.ErrStudentNotFound)
+	mockSvc.On("UpdateStudent", int64(999), mock.AnythingOfType("model.Student"), departmentScope{}).Return(model.Student{}, repository.ErrStudentNotFound)
 
 	withMockStudentService(t, mockSvc)
 
@@ -319,7 +331,7 @@ func TestUpdateStudentNotFound(t *testing.T) {
 
 func TestDeleteStudentSuccess(t *testing.T) {
 	mockSvc := &studentServiceMock{}
-	mockSvc.On("DeleteStudent", int64(1)).Return(nil)
+	mockSvc.On("DeleteStudent", int64(1), departmentScope{}).Return(nil)
 
 	withMockStudentService(t, mockSvc)
 
@@ -337,3 +349,24 @@ func TestDeleteStudentSuccess(t *testing.T) {
 	assert.Equal(t, "Student deleted successfully", resp["message"])
 	mockSvc.AssertExpectations(t)
 }
+
+func TestGetStudentByIDOutsideTeacherDepartments(t *testing.T) {
+	withTeacher(t)
+	mockSvc := &studentServiceMock{}
+	mockSvc.On("GetStudentByID", int64(1)).Return(model.Student{ID: 1, DepartmentID: 5}, nil)
+
+	withMockStudentService(t, mockSvc)
+
+	req := httptest.NewRequest(http.MethodGet, "/students/1", nil)
+	req.Header.Set("userId", "2")
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	c.Request = req
+	c.Set(util.ContextRoles, []string{model.RoleTeacher})
+
+	getStudentByID(c)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	mockSvc.AssertExpectations(t)
+}
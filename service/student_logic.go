@@ -16,22 +16,27 @@ var ErrDuplicateStudentEmail = errors.New("student with this email already exist
 // StudentService describes the student domain operations that the HTTP layer
 // relies on. Having an explicit interface allows us to unit-test handlers by
 // supplying mocks.
+//
+// Writes and listings take the caller's department scope and fail with
+// ErrOutsideDepartments for students of other departments.
 type StudentService interface {
-	CreateStudent(student model.Student) (model.Student, error)
-	GetAllStudents(limit, offset int) (model.Students, error)
+	CreateStudent(student model.Student, scope departmentScope) (model.Student, error)
+	GetAllStudents(departmentID int64, scope departmentScope, limit, offset int) (model.Students, error)
 	GetStudentByID(id int64) (model.Student, error)
-	UpdateStudent(id int64, student model.Student) (model.Student, error)
-	DeleteStudent(id int64) error
+	GetStudentDepartments(ids []int64) (map[int64]int64, error)
+	UpdateStudent(id int64, student model.Student, scope departmentScope) (model.Student, error)
+	DeleteStudent(id int64, scope departmentScope) error
 }
 
 type studentService struct {
-	repo repository.StudentRepository
+	repo        repository.StudentRepository
+	departments repository.DepartmentRepository
 }
 
-var studentSvc StudentService = newStudentService(repository.StudentRepo)
+var studentSvc StudentService = newStudentService(repository.StudentRepo, repository.DepartmentRepo)
 
-func newStudentService(repo repository.StudentRepository) StudentService {
-	return &studentService{repo: repo}
+func newStudentService(repo repository.StudentRepository, departments repository.DepartmentRepository) StudentService {
+	return &studentService{repo: repo, departments: departments}
 }
 
 // setStudentService allows tests to inject a mock implementation.
@@ -39,10 +44,17 @@ func setStudentService(svc StudentService) {
 	studentSvc = svc
 }
 
-func (s *studentService) CreateStudent(student model.Student) (model.Student, error) {
+func (s *studentService) CreateStudent(student model.Student, scope departmentScope) (model.Student, error) {
 	if err := validateStudentInput(student); err != nil {
 		return model.Student{}, err
 	}
+	student, err := s.resolveDepartment(student)
+	if err != nil {
+		return model.Student{}, err
+	}
+	if !scope.allows(student.DepartmentID) {
+		return model.Student{}, ErrOutsideDepartments
+	}
 
 	existing, err := s.repo.GetStudentByEmail(student.Email)
 	if err != nil {
@@ -61,18 +73,46 @@ func (s *studentService) CreateStudent(student model.Student) (model.Student, er
 	return student, nil
 }
 
-func (s *studentService) GetAllStudents(limit, offset int) (model.Students, error) {
-	return s.repo.GetAllStudents(limit, offset)
+// GetAllStudents lists the students of departmentID, or of every department
+// in scope when it is 0
+func (s *studentService) GetAllStudents(departmentID int64, scope departmentScope, limit, offset int) (model.Students, error) {
+	departmentIDs := scope.departments
+	if departmentID != 0 {
+		if !scope.allows(departmentID) {
+			return nil, ErrOutsideDepartments
+		}
+		departmentIDs = []int64{departmentID}
+	}
+	if scope.restricted && len(departmentIDs) == 0 {
+		return model.Students{}, nil
+	}
+
+	return s.repo.GetAllStudents(departmentIDs, limit, offset)
 }
 
 func (s *studentService) GetStudentByID(id int64) (model.Student, error) {
 	return s.repo.GetStudentByID(id)
 }
 
-func (s *studentService) UpdateStudent(id int64, student model.Student) (model.Student, error) {
+func (s *studentService) GetStudentDepartments(ids []int64) (map[int64]int64, error) {
+	return s.repo.GetStudentDepartments(ids)
+}
+
+func (s *studentService) UpdateStudent(id int64, student model.Student, scope departmentScope) (model.Student, error) {
 	if err := validateStudentInput(student); err != nil {
 		return model.Student{}, err
 	}
+	student, err := s.resolveDepartment(student)
+	if err != nil {
+		return model.Student{}, err
+	}
+	// a teacher can neither take a student from nor move one to another department
+	if err := s.checkScope(id, scope); err != nil {
+		return model.Student{}, err
+	}
+	if !scope.allows(student.DepartmentID) {
+		return model.Student{}, ErrOutsideDepartments
+	}
 
 	existing, err := s.repo.GetStudentByEmail(student.Email)
 	if err != nil {
@@ -90,10 +130,54 @@ func (s *studentService) UpdateStudent(id int64, student model.Student) (model.S
 	return student, nil
 }
 
-func (s *studentService) DeleteStudent(id int64) error {
+func (s *studentService) DeleteStudent(id int64, scope departmentScope) error {
+	if err := s.checkScope(id, scope); err != nil {
+		return err
+	}
 	return s.repo.DeleteStudent(id)
 }
 
+// checkScope fails with ErrOutsideDepartments when the stored student is
+// outside a restricted scope
+func (s *studentService) checkScope(id int64, scope departmentScope) error {
+	if !scope.restricted {
+		return nil
+	}
+
+	current, err := s.repo.GetStudentByID(id)
+	if err != nil {
+		return err
+	}
+	if !scope.allows(current.DepartmentID) {
+		return ErrOutsideDepartments
+	}
+	return nil
+}
+
+// resolveDepartment fills in both the department ID and its canonical name.
+// A department given by name may also be its code or one of its aliases.
+func (s *studentService) resolveDepartment(student model.Student) (model.Student, error) {
+	var department model.Department
+	var err error
+	field := "department"
+	if student.DepartmentID != 0 {
+		field = "department_id"
+		department, err = s.departments.GetDepartmentByID(student.DepartmentID)
+	} else {
+		department, err = s.departments.ResolveDepartment(strings.TrimSpace(student.Department))
+	}
+	if errors.Is(err, repository.ErrDepartmentNotFound) {
+		return model.Student{}, &ValidationError{Fields: map[string]string{field: "department does not exist"}}
+	}
+	if err != nil {
+		return model.Student{}, err
+	}
+
+	student.DepartmentID = department.ID
+	student.Department = department.Name
+	return student, nil
+}
+
 // ValidationError captures field-level validation failures.
 type ValidationError struct {
 	Fields map[string]string
@@ -114,8 +198,8 @@ func validateStudentInput(student model.Student) error {
 	} else if _, err := mail.ParseAddress(student.Email); err != nil {
 		issues["email"] = "email format is invalid"
 	}
-	if strings.TrimSpace(student.Department) == "" {
-		issues["department"] = "department is required"
+	if student.DepartmentID == 0 && strings.TrimSpace(student.Department) == "" {
+		issues["department"] = "department or department_id is required"
 	}
 
 	if len(issues) > 0 {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -19,8 +20,8 @@ func (m *mockStudentRepository) CreateStudent(student model.Student) (int64, err
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *mockStudentRepository) GetAllStudents(limit, offset int) (model.Students, error) {
-	args := m.Called(limit, offset)
+func (m *mockStudentRepository) GetAllStudents(departmentIDs []int64, limit, offset int) (model.Students, error) {
+	args := m.Called(departmentIDs, limit, offset)
 	if students, ok := args.Get(0).(model.Students); ok {
 		return students, args.Error(1)
 	}
@@ -37,6 +38,12 @@ func (m *mockStudentRepository) GetStudentByEmail(email string) (model.Student,
 	return args.Get(0).(model.Student), args.Error(1)
 }
 
+func (m *mockStudentRepository) GetStudentDepartments(ids []int64) (map[int64]int64, error) {
+	args := m.Called(ids)
+	departments, _ := args.Get(0).(map[int64]int64)
+	return departments, args.Error(1)
+}
+
 func (m *mockStudentRepository) UpdateStudent(id int64, student model.Student) error {
 	args := m.Called(id, student)
 	return args.Error(0)
@@ -47,29 +54,44 @@ func (m *mockStudentRepository) DeleteStudent(id int64) error {
 	return args.Error(0)
 }
 
+// scienceDepartment resolves "Science" (or its code "SCI") to department 3
+func scienceDepartment() *mockDepartmentRepository {
+	departments := &mockDepartmentRepository{}
+	science := model.Department{ID: 3, Code: "SCI", Name: "Science"}
+	departments.On("ResolveDepartment", "Science").Return(science, nil)
+	departments.On("ResolveDepartment", "SCI").Return(science, nil)
+	departments.On("ResolveDepartment", mock.Anything).Return(model.Department{}, repository.ErrDepartmentNotFound)
+	departments.On("GetDepartmentByID", int64(3)).Return(science, nil)
+	departments.On("GetDepartmentByID", mock.Anything).Return(model.Department{}, repository.ErrDepartmentNotFound)
+	return departments
+}
+
 func TestStudentServiceCreateStudentSuccess(t *testing.T) {
 	repo := &mockStudentRepository{}
-	svc := newStudentService(repo)
+	svc := newStudentService(repo, scienceDepartment())
 
 	input := model.Student{Name: "Jane", Email: "jane@example.com", Department: "Science"}
 
 	repo.On("GetStudentByEmail", input.Email).Return(model.Student{}, nil).Once()
-	repo.On("CreateStudent", input).Return(int64(42), nil).Once()
+	stored := input
+	stored.DepartmentID = 3
+	repo.On("CreateStudent", stored).Return(int64(42), nil).Once()
 
-	created, err := svc.CreateStudent(input)
+	created, err := svc.CreateStudent(input, departmentScope{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, int64(42), created.ID)
+	assert.Equal(t, int64(3), created.DepartmentID)
 	repo.AssertExpectations(t)
 }
 
 func TestStudentServiceCreateStudentInvalidEmail(t *testing.T) {
 	repo := &mockStudentRepository{}
-	svc := newStudentService(repo)
+	svc := newStudentService(repo, scienceDepartment())
 
 	input := model.Student{Name: "Jane", Email: "invalid-email", Department: "Science"}
 
-	_, err := svc.CreateStudent(input)
+	_, err := svc.CreateStudent(input, departmentScope{})
 
 	var validationErr *ValidationError
 	assert.True(t, errors.As(err, &validationErr))
@@ -78,13 +100,13 @@ func TestStudentServiceCreateStudentInvalidEmail(t *testing.T) {
 
 func TestStudentServiceCreateStudentDuplicateEmail(t *testing.T) {
 	repo := &mockStudentRepository{}
-	svc := newStudentService(repo)
+	svc := newStudentService(repo, scienceDepartment())
 
 	input := model.Student{Name: "Jane", Email: "jane@example.com", Department: "Science"}
 
 	repo.On("GetStudentByEmail", input.Email).Return(model.Student{ID: 7, Email: input.Email}, nil).Once()
 
-	_, err := svc.CreateStudent(input)
+	_, err := svc.CreateStudent(input, departmentScope{})
 
 	assert.ErrorIs(t, err, ErrDuplicateStudentEmail)
 	repo.AssertExpectations(t)
@@ -92,13 +114,13 @@ func TestStudentServiceCreateStudentDuplicateEmail(t *testing.T) {
 
 func TestStudentServiceUpdateStudentDuplicateEmail(t *testing.T) {
 	repo := &mockStudentRepository{}
-	svc := newStudentService(repo)
+	svc := newStudentService(repo, scienceDepartment())
 
 	input := model.Student{Name: "Jane", Email: "jane@example.com", Department: "Science"}
 
 	repo.On("GetStudentByEmail", input.Email).Return(model.Student{ID: 99, Email: input.Email}, nil).Once()
 
-	_, err := svc.UpdateStudent(1, input)
+	_, err := svc.UpdateStudent(1, input, departmentScope{})
 
 	assert.ErrorIs(t, err, ErrDuplicateStudentEmail)
 	repo.AssertExpectations(t)
@@ -106,16 +128,88 @@ func TestStudentServiceUpdateStudentDuplicateEmail(t *testing.T) {
 
 func TestStudentServiceUpdateStudentSuccess(t *testing.T) {
 	repo := &mockStudentRepository{}
-	svc := newStudentService(repo)
+	svc := newStudentService(repo, scienceDepartment())
 
 	input := model.Student{Name: "Jane", Email: "jane@example.com", Department: "Science"}
 
 	repo.On("GetStudentByEmail", input.Email).Return(model.Student{ID: 1, Email: input.Email}, nil).Once()
-	repo.On("UpdateStudent", int64(1), input).Return(nil).Once()
+	repo.On("UpdateStudent", int64(1), model.Student{Name: "Jane", Email: "jane@example.com", DepartmentID: 3, Department: "Science"}).Return(nil).Once()
 
-	updated, err := svc.UpdateStudent(1, input)
+	updated, err := svc.UpdateStudent(1, input, departmentScope{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, int64(1), updated.ID)
 	repo.AssertExpectations(t)
 }
+
+func TestStudentServiceCreateStudentResolvesDepartmentCode(t *testing.T) {
+	repo := &mockStudentRepository{}
+	svc := newStudentService(repo, scienceDepartment())
+
+	repo.On("GetStudentByEmail", "jane@example.com").Return(model.Student{}, nil).Once()
+	repo.On("CreateStudent", model.Student{Name: "Jane", Email: "jane@example.com", DepartmentID: 3, Department: "Science"}).Return(int64(42), nil).Once()
+
+	created, err := svc.CreateStudent(model.Student{Name: "Jane", Email: "jane@example.com", Department: "SCI"}, departmentScope{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Science", created.Department)
+	repo.AssertExpectations(t)
+}
+
+func TestStudentServiceCreateStudentUnknownDepartment(t *testing.T) {
+	repo := &mockStudentRepository{}
+	svc := newStudentService(repo, scienceDepartment())
+
+	_, err := svc.CreateStudent(model.Student{Name: "Jane", Email: "jane@example.com", Department: "Comp Sci"}, departmentScope{})
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "department does not exist", validationErr.Fields["department"])
+
+	_, err = svc.CreateStudent(model.Student{Name: "Jane", Email: "jane@example.com", DepartmentID: 8}, departmentScope{})
+
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Contains(t, validationErr.Fields, "department_id")
+	repo.AssertNotCalled(t, "CreateStudent", mock.Anything)
+}
+
+func TestStudentServiceGetAllStudentsScoped(t *testing.T) {
+	repo := &mockStudentRepository{}
+	svc := newStudentService(repo, scienceDepartment())
+	teacher := departmentScope{restricted: true, departments: []int64{3, 4}}
+
+	repo.On("GetAllStudents", []int64{3, 4}, 10, 0).Return(model.Students{{ID: 1}}, nil).Once()
+	repo.On("GetAllStudents", []int64{4}, 10, 0).Return(model.Students{{ID: 2}}, nil).Once()
+
+	students, err := svc.GetAllStudents(0, teacher, 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, students, 1)
+
+	_, err = svc.GetAllStudents(4, teacher, 10, 0)
+	assert.NoError(t, err)
+
+	_, err = svc.GetAllStudents(5, teacher, 10, 0)
+	assert.ErrorIs(t, err, ErrOutsideDepartments)
+
+	students, err = svc.GetAllStudents(0, departmentScope{restricted: true}, 10, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, students)
+	repo.AssertExpectations(t)
+}
+
+func TestStudentServiceUpdateStudentOutsideScope(t *testing.T) {
+	repo := &mockStudentRepository{}
+	svc := newStudentService(repo, scienceDepartment())
+	input := model.Student{Name: "Jane", Email: "jane@example.com", Department: "Science"}
+
+	// the student belongs to department 5, which the teacher is not assigned to
+	repo.On("GetStudentByID", int64(1)).Return(model.Student{ID: 1, DepartmentID: 5}, nil)
+
+	_, err := svc.UpdateStudent(1, input, departmentScope{restricted: true, departments: []int64{3}})
+	assert.ErrorIs(t, err, ErrOutsideDepartments)
+
+	err = svc.DeleteStudent(1, departmentScope{restricted: true, departments: []int64{3}})
+	assert.ErrorIs(t, err, ErrOutsideDepartments)
+	repo.AssertNotCalled(t, "UpdateStudent", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "DeleteStudent", mock.Anything)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	model "github.com/shravanasati/scopex-go-assignment/model"
 	repository "github.com/shravanasati/scopex-go-assignment/repository"
@@ -16,22 +17,24 @@ import (
 func RoutesStudent(rg *gin.RouterGroup) {
 	student := rg.Group("/students")
 
-	student.POST("/", util.TokenAuthMiddleware(), createStudent)
-	student.GET("/", util.TokenAuthMiddleware(), getAllStudents)
-	student.GET("/:id", util.TokenAuthMiddleware(), getStudentByID)
-	student.PUT("/:id", util.TokenAuthMiddleware(), updateStudent)
-	student.DELETE("/:id", util.TokenAuthMiddleware(), deleteStudent)
+	student.POST("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermStudentsWrite), createStudent)
+	student.GET("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermStudentsRead), getAllStudents)
+	student.GET("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermStudentsRead), getStudentByID)
+	student.GET("/:id/attendance/summary", util.TokenAuthMiddleware(), util.RequirePermission(model.PermReportsRead), getStudentAttendanceSummary)
+	student.PUT("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermStudentsWrite), updateStudent)
+	student.DELETE("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermStudentsDelete), deleteStudent)
 }
 
 // createStudent godoc
 // @Summary Create a new student
-// @Description Create a new student with the input payload
+// @Description Create a new student with the input payload. Teachers can only create students in their own departments.
 // @Tags Students
 // @Accept  json
 // @Produce  json
 // @Param student body model.Student true "Student"
 // @Success 201 {object} model.Student
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security bearerAuth
@@ -43,7 +46,12 @@ func createStudent(c *gin.Context) {
 		return
 	}
 
-	created, err := studentSvc.CreateStudent(student)
+	scope, ok := callerScope(c)
+	if !ok {
+		return
+	}
+
+	created, err := studentSvc.CreateStudent(student, scope)
 	if err != nil {
 		handleStudentError(c, err)
 		return
@@ -54,33 +62,42 @@ func createStudent(c *gin.Context) {
 
 // getAllStudents godoc
 // @Summary List all students
-// @Description Get a list of students with pagination
+// @Description Get a list of students with pagination, optionally only those of one department. Teachers only see the students of their own departments.
 // @Tags Students
 // @Accept  json
 // @Produce  json
+// @Param department_id query int false "Only students of this department"
 // @Param page query int false "Page number" default(1)
-// @Param limit query int false "Page size" default(10)
+// @Param limit query int false "Page size, at most 100" default(10) maximum(100)
 // @Success 200 {array} model.Student
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security bearerAuth
 // @Router /students/ [get]
 func getAllStudents(c *gin.Context) {
-	pageStr := c.DefaultQuery("page", "1")
-	limitStr := c.DefaultQuery("limit", "10")
+	_, limit, offset := pagination(c)
 
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		page = 1
+	var departmentID int64
+	if raw := c.Query("department_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid department_id"})
+			return
+		}
+		departmentID = id
 	}
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 {
-		limit = 10
+	scope, ok := callerScope(c)
+	if !ok {
+		return
 	}
 
-	offset := (page - 1) * limit
-
-	students, err := studentSvc.GetAllStudents(limit, offset)
+	students, err := studentSvc.GetAllStudents(departmentID, scope, limit, offset)
+	if errors.Is(err, ErrOutsideDepartments) {
+		handleStudentError(c, err)
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch students"})
 		return
@@ -98,6 +115,7 @@ func getAllStudents(c *gin.Context) {
 // @Param id path int true "Student ID"
 // @Success 200 {object} model.Student
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Security bearerAuth
 // @Router /students/{id} [get]
@@ -114,10 +132,69 @@ func getStudentByID(c *gin.Context) {
 		handleStudentError(c, err)
 		return
 	}
+	if !authorizeStudent(c, student) {
+		return
+	}
 
 	c.JSON(http.StatusOK, student)
 }
 
+// getStudentAttendanceSummary godoc
+// @Summary Get a student's attendance summary
+// @Description Get present/absent counts, attendance percentage, day streaks and a per-month breakdown for a student. to defaults to today and from to one year before to.
+// @Tags Students
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Student ID"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} model.AttendanceSummary
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /students/{id}/attendance/summary [get]
+func getStudentAttendanceSummary(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	from, to, err := resolveSummaryRange(c.Query("from"), c.Query("to"), time.Now())
+	if err != nil {
+		handleStudentError(c, err)
+		return
+	}
+
+	student, err := studentSvc.GetStudentByID(id)
+	if err != nil {
+		handleStudentError(c, err)
+		return
+	}
+	if !authorizeStudent(c, student) {
+		return
+	}
+
+	records, err := repository.GetAttendanceByDateRange(id, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attendance"})
+		return
+	}
+
+	workingDays, err := calendarSvc.WorkingDays(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute working days"})
+		return
+	}
+
+	summary := summarizeAttendance(student, from, to, records, catalogue.all(), dayPresentPercent())
+	summary.UnmarkedWorkingDays = countUnmarkedWorkingDays(workingDays, markedDays(records))
+	c.JSON(http.StatusOK, summary)
+}
+
 // updateStudent godoc
 // @Summary Update a student
 // @Description Update an existing student by ID
@@ -128,6 +205,7 @@ func getStudentByID(c *gin.Context) {
 // @Param student body model.Student true "Student"
 // @Success 200 {object} model.Student
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security bearerAuth
 // @Router /students/{id} [put]
@@ -145,7 +223,12 @@ func updateStudent(c *gin.Context) {
 		return
 	}
 
-	updated, err := studentSvc.UpdateStudent(id, student)
+	scope, ok := callerScope(c)
+	if !ok {
+		return
+	}
+
+	updated, err := studentSvc.UpdateStudent(id, student, scope)
 	if err != nil {
 		handleStudentError(c, err)
 		return
@@ -163,6 +246,7 @@ func updateStudent(c *gin.Context) {
 // @Param id path int true "Student ID"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security bearerAuth
 // @Router /students/{id} [delete]
@@ -174,7 +258,12 @@ func deleteStudent(c *gin.Context) {
 		return
 	}
 
-	err = studentSvc.DeleteStudent(id)
+	scope, ok := callerScope(c)
+	if !ok {
+		return
+	}
+
+	err = studentSvc.DeleteStudent(id, scope)
 	if err != nil {
 		handleStudentError(c, err)
 		return
@@ -183,6 +272,75 @@ func deleteStudent(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Student deleted successfully"})
 }
 
+// authorizeStudent answers 403 and returns false when the student is outside
+// the caller's departments
+func authorizeStudent(c *gin.Context, student model.Student) bool {
+	scope, ok := callerScope(c)
+	if !ok {
+		return false
+	}
+	if !scope.allows(student.DepartmentID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": ErrOutsideDepartments.Error()})
+		return false
+	}
+	return true
+}
+
+// authorizeStudentID is authorizeStudent for a student known by ID only; the
+// student is looked up for restricted callers alone
+func authorizeStudentID(c *gin.Context, studentID int64) bool {
+	scope, ok := callerScope(c)
+	if !ok {
+		return false
+	}
+	if !scope.restricted {
+		return true
+	}
+
+	student, err := studentSvc.GetStudentByID(studentID)
+	if err != nil {
+		handleStudentError(c, err)
+		return false
+	}
+	if !scope.allows(student.DepartmentID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": ErrOutsideDepartments.Error()})
+		return false
+	}
+	return true
+}
+
+// authorizeStudentIDs is authorizeStudentID for a batch: it answers 403
+// listing the students outside the caller's departments. Unknown students are
+// left for the caller to report.
+func authorizeStudentIDs(c *gin.Context, studentIDs []int64) bool {
+	scope, ok := callerScope(c)
+	if !ok {
+		return false
+	}
+	if !scope.restricted {
+		return true
+	}
+
+	departments, err := studentSvc.GetStudentDepartments(studentIDs)
+	if err != nil {
+		handleStudentError(c, err)
+		return false
+	}
+
+	var outside []int64
+	for _, id := range studentIDs {
+		departmentID, known := departments[id]
+		if known && !scope.allows(departmentID) {
+			outside = append(outside, id)
+		}
+	}
+	if len(outside) > 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": ErrOutsideDepartments.Error(), "student_ids": outside})
+		return false
+	}
+	return true
+}
+
 func handleStudentError(c *gin.Context, err error) {
 	var validationErr *ValidationError
 	switch {
@@ -192,6 +350,8 @@ func handleStudentError(c *gin.Context, err error) {
 		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 	case errors.Is(err, repository.ErrStudentNotFound):
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrOutsideDepartments):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 	default:
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 	}
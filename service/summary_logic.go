@@ -0,0 +1,164 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+)
+
+// resolveSummaryRange validates the optional from/to bounds of a summary.
+// to defaults to today and from to one year before to.
+func resolveSummaryRange(from, to string, today time.Time) (string, string, error) {
+	issues := make(map[string]string)
+
+	if to == "" {
+		to = today.Format(isoDateLayout)
+	} else if err := validateISODate(to); err != nil {
+		issues["to"] = err.Error()
+	}
+
+	if from == "" {
+		if end, err := time.Parse(isoDateLayout, to); err == nil {
+			from = end.AddDate(-1, 0, 0).Format(isoDateLayout)
+		}
+	} else if err := validateISODate(from); err != nil {
+		issues["from"] = err.Error()
+	}
+
+	if len(issues) == 0 && from > to {
+		issues["to"] = "to must not be before from"
+	}
+
+	if len(issues) > 0 {
+		return "", "", &ValidationError{Fields: issues}
+	}
+
+	return from, to, nil
+}
+
+// summarizeAttendance builds a student's attendance summary from their
+// records in date order. Counts are per record, like the attendance report,
+// while streaks run over marked days: a day is present once dayPercent of its
+// records count as present. Excused records count neither way, and days
+// without other records neither extend nor break a streak.
+func summarizeAttendance(student model.Student, from, to string, records model.Attendances, statuses model.AttendanceStatuses, dayPercent float64) model.AttendanceSummary {
+	countsAsPresent := make(map[string]bool, len(statuses))
+	excused := make(map[string]bool, len(statuses))
+	statusCounts := make(map[string]int, len(statuses))
+	for _, s := range statuses {
+		countsAsPresent[s.Code] = s.CountsAsPresent
+		excused[s.Code] = s.Excused
+	}
+
+	summary := model.AttendanceSummary{
+		AttendanceReport: model.AttendanceReport{
+			StudentID:    student.ID,
+			StudentName:  student.Name,
+			StudentEmail: student.Email,
+		},
+		From:   from,
+		To:     to,
+		Months: []model.MonthlyAttendance{},
+	}
+
+	var (
+		day                      string
+		dayPresent, dayTotal     int
+		presentStreak, absentRun int
+		monthIndex               = make(map[string]int)
+	)
+
+	closeDay := func() {
+		if dayTotal == 0 {
+			return
+		}
+		if float64(dayPresent)*100 >= dayPercent*float64(dayTotal) {
+			presentStreak++
+			absentRun = 0
+		} else {
+			absentRun++
+			presentStreak = 0
+		}
+		summary.LongestPresentStreak = max(summary.LongestPresentStreak, presentStreak)
+		summary.LongestAbsentStreak = max(summary.LongestAbsentStreak, absentRun)
+		dayPresent, dayTotal = 0, 0
+	}
+
+	for _, a := range records {
+		date := recordDay(a)
+		if date != day {
+			closeDay()
+			day = date
+		}
+
+		month := date[:7]
+		i, ok := monthIndex[month]
+		if !ok {
+			i = len(summary.Months)
+			monthIndex[month] = i
+			summary.Months = append(summary.Months, model.MonthlyAttendance{Month: month})
+		}
+
+		statusCounts[a.Status]++
+		if excused[a.Status] {
+			continue
+		}
+		dayTotal++
+		if countsAsPresent[a.Status] {
+			dayPresent++
+			summary.PresentCount++
+			summary.Months[i].PresentCount++
+		} else {
+			summary.AbsentCount++
+			summary.Months[i].AbsentCount++
+		}
+	}
+	closeDay()
+
+	summary.CurrentPresentStreak = presentStreak
+	summary.Percentage = attendancePercentage(summary.PresentCount, summary.AbsentCount)
+	for i := range summary.Months {
+		summary.Months[i].Percentage = attendancePercentage(summary.Months[i].PresentCount, summary.Months[i].AbsentCount)
+	}
+
+	summary.StatusCounts = make([]model.StatusCount, 0, len(statuses))
+	for _, s := range statuses {
+		summary.StatusCounts = append(summary.StatusCounts, model.StatusCount{
+			Code:            s.Code,
+			Label:           s.Label,
+			CountsAsPresent: s.CountsAsPresent,
+			Excused:         s.Excused,
+			Count:           statusCounts[s.Code],
+		})
+	}
+
+	return summary
+}
+
+// markedDays returns the set of days with at least one record.
+func markedDays(records model.Attendances) map[string]bool {
+	days := make(map[string]bool, len(records))
+	for _, a := range records {
+		days[recordDay(a)] = true
+	}
+	return days
+}
+
+// recordDay returns the YYYY-MM-DD day of a record, whose date may have been
+// scanned with a time part.
+func recordDay(a model.Attendance) string {
+	if len(a.Date) > len(isoDateLayout) {
+		return a.Date[:len(isoDateLayout)]
+	}
+	return a.Date
+}
+
+// attendancePercentage returns the present share rounded to two decimals.
+func attendancePercentage(present, absent int) float64 {
+	total := present + absent
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(present)*10000/float64(total)) / 100
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveSummaryRange(t *testing.T) {
+	today := time.Date(2023, 10, 27, 12, 0, 0, 0, time.UTC)
+
+	from, to, err := resolveSummaryRange("", "", today)
+	assert.NoError(t, err)
+	assert.Equal(t, "2022-10-27", from)
+	assert.Equal(t, "2023-10-27", to)
+
+	_, _, err = resolveSummaryRange("2023-10-31", "2023-10-01", today)
+	assert.Error(t, err)
+
+	_, _, err = resolveSummaryRange("01-10-2023", "", today)
+	assert.Error(t, err)
+}
+
+func TestSummarizeAttendance(t *testing.T) {
+	student := model.Student{ID: 1, Name: "Asha", Email: "asha@example.com"}
+	records := model.Attendances{
+		{StudentID: 1, Date: "2023-09-28T00:00:00Z", Status: "Present"},
+		{StudentID: 1, Date: "2023-09-29T00:00:00Z", Status: "Absent"},
+		{StudentID: 1, Date: "2023-10-02T00:00:00Z", Status: "Absent"},
+		{StudentID: 1, Date: "2023-10-03T00:00:00Z", Status: "Late"},
+		{StudentID: 1, Date: "2023-10-04T00:00:00Z", SessionID: 1, Status: "Present"},
+		{StudentID: 1, Date: "2023-10-04T00:00:00Z", SessionID: 2, Status: "Absent"},
+		{StudentID: 1, Date: "2023-10-05T00:00:00Z", Status: "Present"},
+	}
+
+	summary := summarizeAttendance(student, "2023-09-01", "2023-10-31", records, model.DefaultAttendanceStatuses, 50)
+
+	assert.Equal(t, "Asha", summary.StudentName)
+	assert.Equal(t, 4, summary.PresentCount)
+	assert.Equal(t, 3, summary.AbsentCount)
+	assert.Equal(t, 57.14, summary.Percentage)
+	assert.Equal(t, 3, summary.CurrentPresentStreak)
+	assert.Equal(t, 3, summary.LongestPresentStreak)
+	assert.Equal(t, 2, summary.LongestAbsentStreak)
+	assert.Equal(t, []model.MonthlyAttendance{
+		{Month: "2023-09", PresentCount: 1, AbsentCount: 1, Percentage: 50},
+		{Month: "2023-10", PresentCount: 3, AbsentCount: 2, Percentage: 60},
+	}, summary.Months)
+	assert.Len(t, summary.StatusCounts, len(model.DefaultAttendanceStatuses))
+}
+
+func TestSummarizeAttendanceLeavesOutExcusedStatuses(t *testing.T) {
+	student := model.Student{ID: 1, Name: "Asha", Email: "asha@example.com"}
+	records := model.Attendances{
+		{StudentID: 1, Date: "2023-10-02", Status: "Present"},
+		{StudentID: 1, Date: "2023-10-03", Status: "Excused"},
+		{StudentID: 1, Date: "2023-10-04", Status: model.StatusOnLeave},
+		{StudentID: 1, Date: "2023-10-05", SessionID: 1, Status: "Present"},
+		{StudentID: 1, Date: "2023-10-05", SessionID: 2, Status: "Excused"},
+		{StudentID: 1, Date: "2023-10-06", Status: "Absent"},
+	}
+
+	summary := summarizeAttendance(student, "2023-10-01", "2023-10-31", records, model.DefaultAttendanceStatuses, 100)
+
+	assert.Equal(t, 2, summary.PresentCount)
+	assert.Equal(t, 1, summary.AbsentCount)
+	assert.Equal(t, 66.67, summary.Percentage)
+	// excused days neither extend nor break the streak, and an excused
+	// session does not make a day fall short of 100%
+	assert.Equal(t, 2, summary.LongestPresentStreak)
+	assert.Equal(t, 0, summary.CurrentPresentStreak)
+	for _, c := range summary.StatusCounts {
+		switch c.Code {
+		case "Excused":
+			assert.Equal(t, 2, c.Count)
+			assert.True(t, c.Excused)
+		case model.StatusOnLeave:
+			assert.Equal(t, 1, c.Count)
+			assert.True(t, c.Excused)
+		}
+	}
+}
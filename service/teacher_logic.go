@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"net/mail"
+	"slices"
+	"strings"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+)
+
+// ErrOutsideDepartments is returned when a teacher works with a student of a
+// department they are not assigned to.
+var ErrOutsideDepartments = errors.New("student is outside your departments")
+
+// departmentScope is the set of departments a caller may work with. Only
+// callers with the teacher role are restricted.
+type departmentScope struct {
+	restricted  bool
+	departments []int64
+}
+
+func (s departmentScope) allows(departmentID int64) bool {
+	return !s.restricted || slices.Contains(s.departments, departmentID)
+}
+
+// TeacherService describes the teacher profile operations the HTTP layer
+// relies on, and the department scope every student and attendance handler
+// checks.
+type TeacherService interface {
+	CreateTeacher(teacher model.Teacher) (model.Teacher, error)
+	GetTeachers() (model.Teachers, error)
+	GetTeacherByUserID(userID int64) (model.Teacher, error)
+	UpdateTeacher(userID int64, teacher model.Teacher) (model.Teacher, error)
+	DeleteTeacher(userID int64) error
+	Scope(userID int64) (departmentScope, error)
+}
+
+type teacherService struct {
+	repo repository.TeacherRepository
+}
+
+var teacherSvc TeacherService = newTeacherService(repository.TeacherRepo)
+
+func newTeacherService(repo repository.TeacherRepository) TeacherService {
+	return &teacherService{repo: repo}
+}
+
+func (s *teacherService) CreateTeacher(teacher model.Teacher) (model.Teacher, error) {
+	teacher, err := normalizeTeacherInput(teacher)
+	if err != nil {
+		return model.Teacher{}, err
+	}
+	if teacher.UserID == 0 {
+		return model.Teacher{}, &ValidationError{Fields: map[string]string{"user_id": "user_id is required"}}
+	}
+
+	if err := s.repo.CreateTeacher(teacher); err != nil {
+		return model.Teacher{}, err
+	}
+
+	return s.repo.GetTeacherByUserID(teacher.UserID)
+}
+
+func (s *teacherService) GetTeachers() (model.Teachers, error) {
+	return s.repo.GetTeachers()
+}
+
+func (s *teacherService) GetTeacherByUserID(userID int64) (model.Teacher, error) {
+	return s.repo.GetTeacherByUserID(userID)
+}
+
+func (s *teacherService) UpdateTeacher(userID int64, teacher model.Teacher) (model.Teacher, error) {
+	teacher, err := normalizeTeacherInput(teacher)
+	if err != nil {
+		return model.Teacher{}, err
+	}
+
+	if err := s.repo.UpdateTeacher(userID, teacher); err != nil {
+		return model.Teacher{}, err
+	}
+
+	return s.repo.GetTeacherByUserID(userID)
+}
+
+func (s *teacherService) DeleteTeacher(userID int64) error {
+	return s.repo.DeleteTeacher(userID)
+}
+
+// Scope returns the departments a user with the teacher role may work with:
+// those assigned to their teacher profile, or none when they have no profile
+func (s *teacherService) Scope(userID int64) (departmentScope, error) {
+	teacher, err := s.repo.GetTeacherByUserID(userID)
+	if errors.Is(err, repository.ErrTeacherNotFound) {
+		return departmentScope{restricted: true}, nil
+	}
+	if err != nil {
+		return departmentScope{}, err
+	}
+
+	return departmentScope{restricted: true, departments: teacher.DepartmentIDs}, nil
+}
+
+// normalizeTeacherInput trims the name and email and drops repeated
+// department IDs
+func normalizeTeacherInput(teacher model.Teacher) (model.Teacher, error) {
+	teacher.Name = strings.TrimSpace(teacher.Name)
+	teacher.Email = strings.TrimSpace(teacher.Email)
+
+	departments := make([]int64, 0, len(teacher.DepartmentIDs))
+	for _, id := range teacher.DepartmentIDs {
+		if !slices.Contains(departments, id) {
+			departments = append(departments, id)
+		}
+	}
+	teacher.DepartmentIDs = departments
+
+	issues := make(map[string]string)
+	if teacher.Name == "" {
+		issues["name"] = "name is required"
+	}
+	if teacher.Email == "" {
+		issues["email"] = "email is required"
+	} else if _, err := mail.ParseAddress(teacher.Email); err != nil {
+		issues["email"] = "email format is invalid"
+	}
+	if slices.Contains(departments, 0) {
+		issues["department_ids"] = "department IDs must not be 0"
+	}
+
+	if len(issues) > 0 {
+		return teacher, &ValidationError{Fields: issues}
+	}
+
+	return teacher, nil
+}
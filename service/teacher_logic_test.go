@@ -0,0 +1,129 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockTeacherRepository struct {
+	mock.Mock
+}
+
+func (m *mockTeacherRepository) CreateTeacher(teacher model.Teacher) error {
+	args := m.Called(teacher)
+	return args.Error(0)
+}
+
+func (m *mockTeacherRepository) GetTeachers() (model.Teachers, error) {
+	args := m.Called()
+	return args.Get(0).(model.Teachers), args.Error(1)
+}
+
+func (m *mockTeacherRepository) GetTeacherByUserID(userID int64) (model.Teacher, error) {
+	args := m.Called(userID)
+	return args.Get(0).(model.Teacher), args.Error(1)
+}
+
+func (m *mockTeacherRepository) UpdateTeacher(userID int64, teacher model.Teacher) error {
+	args := m.Called(userID, teacher)
+	return args.Error(0)
+}
+
+func (m *mockTeacherRepository) DeleteTeacher(userID int64) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+// withTeacher makes user 2 a teacher of departments 3 and 4
+func withTeacher(t *testing.T) {
+	repo := &mockTeacherRepository{}
+	repo.On("GetTeacherByUserID", int64(2)).Return(model.Teacher{UserID: 2, DepartmentIDs: []int64{3, 4}}, nil)
+	repo.On("GetTeacherByUserID", mock.Anything).Return(model.Teacher{}, repository.ErrTeacherNotFound)
+
+	original := teacherSvc
+	teacherSvc = newTeacherService(repo)
+	t.Cleanup(func() {
+		teacherSvc = original
+	})
+}
+
+// performTeacherRequest calls handler as user 2 with the teacher role
+func performTeacherRequest(handler gin.HandlerFunc, method, path string, params gin.Params, payload any) (*httptest.ResponseRecorder, map[string]any) {
+	var body io.Reader = http.NoBody
+	if payload != nil {
+		encoded, _ := json.Marshal(payload)
+		body = bytes.NewBuffer(encoded)
+	}
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("userId", "2")
+
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	c.Params = params
+	c.Request = req
+	c.Set(util.ContextRoles, []string{model.RoleTeacher})
+
+	handler(c)
+
+	var resp map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+	return rr, resp
+}
+
+func TestTeacherScope(t *testing.T) {
+	withTeacher(t)
+
+	scope, err := teacherSvc.Scope(2)
+	assert.NoError(t, err)
+	assert.True(t, scope.allows(3))
+	assert.False(t, scope.allows(5))
+
+	// a teacher without a profile has no departments
+	scope, err = teacherSvc.Scope(1)
+	assert.NoError(t, err)
+	assert.True(t, scope.restricted)
+	assert.False(t, scope.allows(5))
+}
+
+func TestCreateTeacherValidation(t *testing.T) {
+	repo := &mockTeacherRepository{}
+	svc := newTeacherService(repo)
+
+	_, err := svc.CreateTeacher(model.Teacher{Name: " ", Email: "not-an-email", DepartmentIDs: []int64{3, 0}})
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Contains(t, validationErr.Fields, "name")
+	assert.Contains(t, validationErr.Fields, "email")
+	assert.Contains(t, validationErr.Fields, "department_ids")
+	repo.AssertNotCalled(t, "CreateTeacher", mock.Anything)
+}
+
+func TestCreateTeacherDedupesDepartments(t *testing.T) {
+	repo := &mockTeacherRepository{}
+	svc := newTeacherService(repo)
+
+	stored := model.Teacher{UserID: 2, Name: "Budi", Email: "budi@example.com", DepartmentIDs: []int64{3, 4}}
+	repo.On("CreateTeacher", stored).Return(nil).Once()
+	repo.On("GetTeacherByUserID", int64(2)).Return(stored, nil).Once()
+
+	created, err := svc.CreateTeacher(model.Teacher{UserID: 2, Name: " Budi", Email: "budi@example.com ", DepartmentIDs: []int64{3, 4, 3}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 4}, created.DepartmentIDs)
+	repo.AssertExpectations(t)
+}
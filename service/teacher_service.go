@@ -0,0 +1,188 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutesTeacher registers the teacher profile routes
+func RoutesTeacher(rg *gin.RouterGroup) {
+	teacher := rg.Group("/teachers")
+
+	teacher.POST("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermTeachersWrite), createTeacher)
+	teacher.GET("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermTeachersRead), getTeachers)
+	teacher.GET("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermTeachersRead), getTeacherByUserID)
+	teacher.PUT("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermTeachersWrite), updateTeacher)
+	teacher.DELETE("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermTeachersWrite), deleteTeacher)
+}
+
+// createTeacher godoc
+// @Summary Create a teacher profile
+// @Description Make an existing user a teacher of the given departments. Teachers can only mark and view the attendance of students in their departments.
+// @Tags Teachers
+// @Accept  json
+// @Produce  json
+// @Param teacher body model.Teacher true "Teacher"
+// @Success 201 {object} model.Teacher
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /teachers/ [post]
+func createTeacher(c *gin.Context) {
+	var teacher model.Teacher
+	if err := c.ShouldBindJSON(&teacher); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := teacherSvc.CreateTeacher(teacher)
+	if err != nil {
+		handleTeacherError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// getTeachers godoc
+// @Summary List teachers
+// @Description Get every teacher with their departments, ordered by name
+// @Tags Teachers
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} model.Teacher
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /teachers/ [get]
+func getTeachers(c *gin.Context) {
+	teachers, err := teacherSvc.GetTeachers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch teachers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, teachers)
+}
+
+// getTeacherByUserID godoc
+// @Summary Get a teacher profile
+// @Description Get the teacher profile of a user
+// @Tags Teachers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Success 200 {object} model.Teacher
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /teachers/{id} [get]
+func getTeacherByUserID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	teacher, err := teacherSvc.GetTeacherByUserID(id)
+	if err != nil {
+		handleTeacherError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, teacher)
+}
+
+// updateTeacher godoc
+// @Summary Update a teacher profile
+// @Description Change the name, email or departments of a teacher. The department list replaces the stored one.
+// @Tags Teachers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param teacher body model.Teacher true "Teacher"
+// @Success 200 {object} model.Teacher
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /teachers/{id} [put]
+func updateTeacher(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var teacher model.Teacher
+	if err := c.ShouldBindJSON(&teacher); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := teacherSvc.UpdateTeacher(id, teacher)
+	if err != nil {
+		handleTeacherError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// deleteTeacher godoc
+// @Summary Delete a teacher profile
+// @Description Remove the teacher profile of a user. The user account and its roles are kept, so a user with the teacher role is left without departments.
+// @Tags Teachers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /teachers/{id} [delete]
+func deleteTeacher(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := teacherSvc.DeleteTeacher(id); err != nil {
+		handleTeacherError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Teacher deleted successfully"})
+}
+
+func handleTeacherError(c *gin.Context, err error) {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error(), "details": validationErr.Fields})
+	case errors.Is(err, repository.ErrTeacherNotFound), errors.Is(err, repository.ErrUserNotFound),
+		errors.Is(err, repository.ErrDepartmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrDuplicateTeacher):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+
+	"github.com/spf13/viper"
+)
+
+// Defaults used when TIMETABLE.CLOSE_AFTER_MINUTES is unset and the longest
+// range sessions are generated for in one request
+const (
+	defaultSessionCloseAfterMinutes = 30
+	maxSessionGenerationDays        = 366
+)
+
+// sessionCloseAfter is how long after its end a session is closed
+func sessionCloseAfter() time.Duration {
+	minutes := defaultSessionCloseAfterMinutes
+	if viper.IsSet("TIMETABLE.CLOSE_AFTER_MINUTES") && viper.GetInt("TIMETABLE.CLOSE_AFTER_MINUTES") >= 0 {
+		minutes = viper.GetInt("TIMETABLE.CLOSE_AFTER_MINUTES")
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// TimetableService describes the timetable operations the HTTP layer and the
+// cron job rely on.
+type TimetableService interface {
+	CreateTimetableEntry(entry model.TimetableEntry) (model.TimetableEntry, error)
+	GetTimetableEntries(sectionID int64) (model.TimetableEntries, error)
+	GetTimetableEntryByID(id int64) (model.TimetableEntry, error)
+	UpdateTimetableEntry(id int64, entry model.TimetableEntry) (model.TimetableEntry, error)
+	DeleteTimetableEntry(id int64) error
+	GenerateSessions(req model.SessionGenerationRequest) (model.SessionGenerationResult, error)
+	CloseDueSessions() (closed, marked int, err error)
+}
+
+type timetableService struct {
+	repo         repository.TimetableRepository
+	sessions     repository.SessionRepository
+	courses      repository.CourseRepository
+	workingDays  func(from, to string) ([]string, error)
+	closeSession func(sessionID int64) ([]int64, error)
+	evaluate     func(studentIDs ...int64)
+	now          func() time.Time
+}
+
+var timetableSvc TimetableService = newTimetableService(repository.TimetableRepo, repository.SessionRepo, repository.CourseRepo)
+
+func newTimetableService(repo repository.TimetableRepository, sessions repository.SessionRepository, courses repository.CourseRepository) *timetableService {
+	return &timetableService{
+		repo:     repo,
+		sessions: sessions,
+		courses:  courses,
+		workingDays: func(from, to string) ([]string, error) {
+			return calendarSvc.WorkingDays(from, to)
+		},
+		closeSession: repository.CloseSession,
+		evaluate:     evaluateAlertsAfterWrite,
+		now:          time.Now,
+	}
+}
+
+func (s *timetableService) CreateTimetableEntry(entry model.TimetableEntry) (model.TimetableEntry, error) {
+	entry, err := normalizeTimetableEntry(entry)
+	if err != nil {
+		return model.TimetableEntry{}, err
+	}
+
+	id, err := s.repo.CreateTimetableEntry(entry)
+	if err != nil {
+		return model.TimetableEntry{}, err
+	}
+
+	return s.repo.GetTimetableEntryByID(id)
+}
+
+func (s *timetableService) GetTimetableEntries(sectionID int64) (model.TimetableEntries, error) {
+	return s.repo.GetTimetableEntries(sectionID)
+}
+
+func (s *timetableService) GetTimetableEntryByID(id int64) (model.TimetableEntry, error) {
+	return s.repo.GetTimetableEntryByID(id)
+}
+
+func (s *timetableService) UpdateTimetableEntry(id int64, entry model.TimetableEntry) (model.TimetableEntry, error) {
+	entry, err := normalizeTimetableEntry(entry)
+	if err != nil {
+		return model.TimetableEntry{}, err
+	}
+
+	if err := s.repo.UpdateTimetableEntry(id, entry); err != nil {
+		return model.TimetableEntry{}, err
+	}
+
+	return s.repo.GetTimetableEntryByID(id)
+}
+
+func (s *timetableService) DeleteTimetableEntry(id int64) error {
+	return s.repo.DeleteTimetableEntry(id)
+}
+
+// GenerateSessions creates a session for every timetable entry on each
+// working day of the range that falls on the entry's weekday, so holidays,
+// weekends and days outside every term get none. Running it again for an
+// overlapping range only adds the sessions that are missing.
+func (s *timetableService) GenerateSessions(req model.SessionGenerationRequest) (model.SessionGenerationResult, error) {
+	if err := validateSessionGenerationRange(req.From, req.To); err != nil {
+		return model.SessionGenerationResult{}, err
+	}
+	if req.SectionID != 0 {
+		if _, err := s.courses.GetSectionByID(req.SectionID); err != nil {
+			return model.SessionGenerationResult{}, err
+		}
+	}
+
+	entries, err := s.repo.GetTimetableEntries(req.SectionID)
+	if err != nil {
+		return model.SessionGenerationResult{}, err
+	}
+	days, err := s.workingDays(req.From, req.To)
+	if err != nil {
+		return model.SessionGenerationResult{}, err
+	}
+
+	var sessions model.Sessions
+	for _, day := range days {
+		date, err := time.Parse(isoDateLayout, day)
+		if err != nil {
+			return model.SessionGenerationResult{}, err
+		}
+		for _, entry := range entries {
+			if time.Weekday(entry.Weekday) != date.Weekday() {
+				continue
+			}
+			sessions = append(sessions, model.Session{
+				Date:             day,
+				StartTime:        entry.StartTime,
+				EndTime:          entry.EndTime,
+				Title:            entry.Title,
+				SectionID:        entry.SectionID,
+				TimetableEntryID: entry.ID,
+			})
+		}
+	}
+	if len(sessions) == 0 {
+		return model.SessionGenerationResult{}, nil
+	}
+
+	created, err := s.sessions.CreateSessions(sessions)
+	if err != nil {
+		return model.SessionGenerationResult{}, err
+	}
+
+	return model.SessionGenerationResult{Created: created, Existing: len(sessions) - created}, nil
+}
+
+// CloseDueSessions closes every open session that ended more than
+// TIMETABLE.CLOSE_AFTER_MINUTES ago, marking the department's students
+// without a record Absent and evaluating the alert rule for them. It reports
+// how many sessions were closed and students marked; a failing session does
+// not stop the others.
+func (s *timetableService) CloseDueSessions() (int, int, error) {
+	due, err := s.sessions.GetSessionsToClose(s.now().Add(-sessionCloseAfter()))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	closed, marked := 0, 0
+	var errs []error
+	for _, session := range due {
+		absentees, err := s.closeSession(session.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("session %d: %w", session.ID, err))
+			continue
+		}
+		closed++
+		marked += len(absentees)
+		if len(absentees) > 0 {
+			s.evaluate(absentees...)
+		}
+	}
+
+	return closed, marked, errors.Join(errs...)
+}
+
+// CloseEndedSessions closes the sessions past their cutoff and marks their
+// absentees. It is run by the cron scheduler.
+func CloseEndedSessions() {
+	closed, marked, err := timetableSvc.CloseDueSessions()
+	if err != nil {
+		log.Println("Error closing sessions: ", err)
+	}
+	if closed > 0 {
+		log.Printf("Closed %d sessions, marked %d students absent\n", closed, marked)
+	}
+}
+
+func normalizeTimetableEntry(entry model.TimetableEntry) (model.TimetableEntry, error) {
+	entry.StartTime = strings.TrimSpace(entry.StartTime)
+	entry.EndTime = strings.TrimSpace(entry.EndTime)
+	entry.Title = strings.TrimSpace(entry.Title)
+	entry.Room = strings.TrimSpace(entry.Room)
+	entry.Teacher = strings.TrimSpace(entry.Teacher)
+
+	issues := make(map[string]string)
+	if entry.SectionID == 0 {
+		issues["section_id"] = "section_id is required"
+	}
+	if entry.Weekday < int(time.Sunday) || entry.Weekday > int(time.Saturday) {
+		issues["weekday"] = "weekday must be between 0 (Sunday) and 6 (Saturday)"
+	}
+	if err := validateTimeRange(entry.StartTime, entry.EndTime); err != nil {
+		issues["time"] = err.Error()
+	}
+
+	if len(issues) > 0 {
+		return entry, &ValidationError{Fields: issues}
+	}
+
+	return entry, nil
+}
+
+func validateSessionGenerationRange(from, to string) error {
+	if err := validateDateRange(from, to); err != nil {
+		return err
+	}
+
+	start, _ := time.Parse(isoDateLayout, from)
+	end, _ := time.Parse(isoDateLayout, to)
+	if end.Sub(start) >= maxSessionGenerationDays*24*time.Hour {
+		return &ValidationError{Fields: map[string]string{"to": fmt.Sprintf("range must not exceed %d days", maxSessionGenerationDays)}}
+	}
+
+	return nil
+}
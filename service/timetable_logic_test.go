@@ -0,0 +1,123 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockTimetableRepository struct {
+	mock.Mock
+}
+
+func (m *mockTimetableRepository) CreateTimetableEntry(entry model.TimetableEntry) (int64, error) {
+	args := m.Called(entry)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockTimetableRepository) GetTimetableEntries(sectionID int64) (model.TimetableEntries, error) {
+	args := m.Called(sectionID)
+	return args.Get(0).(model.TimetableEntries), args.Error(1)
+}
+
+func (m *mockTimetableRepository) GetTimetableEntryByID(id int64) (model.TimetableEntry, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.TimetableEntry), args.Error(1)
+}
+
+func (m *mockTimetableRepository) UpdateTimetableEntry(id int64, entry model.TimetableEntry) error {
+	args := m.Called(id, entry)
+	return args.Error(0)
+}
+
+func (m *mockTimetableRepository) DeleteTimetableEntry(id int64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestCreateTimetableEntryValidation(t *testing.T) {
+	repo := &mockTimetableRepository{}
+	svc := newTimetableService(repo, &mockSessionRepository{}, &mockCourseRepository{})
+
+	_, err := svc.CreateTimetableEntry(model.TimetableEntry{Weekday: 7, StartTime: "10:00", EndTime: "09:00"})
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Contains(t, validationErr.Fields, "section_id")
+	assert.Contains(t, validationErr.Fields, "weekday")
+	assert.Contains(t, validationErr.Fields, "time")
+	repo.AssertNotCalled(t, "CreateTimetableEntry", mock.Anything)
+}
+
+func TestGenerateSessionsSkipsNonWorkingDays(t *testing.T) {
+	repo := &mockTimetableRepository{}
+	sessions := &mockSessionRepository{}
+	courses := &mockCourseRepository{}
+	svc := newTimetableService(repo, sessions, courses)
+
+	// 2023-10-23 and 2023-10-30 are Mondays; the 30th is a holiday
+	svc.workingDays = func(from, to string) ([]string, error) {
+		return []string{"2023-10-23", "2023-10-24", "2023-10-25", "2023-10-26", "2023-10-27", "2023-10-31"}, nil
+	}
+	courses.On("GetSectionByID", int64(2)).Return(model.Section{ID: 2}, nil)
+	repo.On("GetTimetableEntries", int64(2)).Return(model.TimetableEntries{
+		{ID: 7, SectionID: 2, Weekday: int(time.Monday), StartTime: "09:00", EndTime: "09:50", Title: "Algebra"},
+		{ID: 8, SectionID: 2, Weekday: int(time.Tuesday), StartTime: "11:00", EndTime: "11:50", Title: "Algebra lab"},
+	}, nil)
+	sessions.On("CreateSessions", model.Sessions{
+		{Date: "2023-10-23", StartTime: "09:00", EndTime: "09:50", Title: "Algebra", SectionID: 2, TimetableEntryID: 7},
+		{Date: "2023-10-24", StartTime: "11:00", EndTime: "11:50", Title: "Algebra lab", SectionID: 2, TimetableEntryID: 8},
+		{Date: "2023-10-31", StartTime: "11:00", EndTime: "11:50", Title: "Algebra lab", SectionID: 2, TimetableEntryID: 8},
+	}).Return(2, nil)
+
+	result, err := svc.GenerateSessions(model.SessionGenerationRequest{From: "2023-10-23", To: "2023-10-31", SectionID: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.SessionGenerationResult{Created: 2, Existing: 1}, result)
+	sessions.AssertExpectations(t)
+}
+
+func TestGenerateSessionsRejectsLongRange(t *testing.T) {
+	svc := newTimetableService(&mockTimetableRepository{}, &mockSessionRepository{}, &mockCourseRepository{})
+
+	_, err := svc.GenerateSessions(model.SessionGenerationRequest{From: "2023-01-01", To: "2024-01-02"})
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Contains(t, validationErr.Fields, "to")
+}
+
+func TestCloseDueSessions(t *testing.T) {
+	sessions := &mockSessionRepository{}
+	svc := newTimetableService(&mockTimetableRepository{}, sessions, &mockCourseRepository{})
+
+	now := time.Date(2023, 10, 27, 11, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	sessions.On("GetSessionsToClose", now.Add(-sessionCloseAfter())).
+		Return(model.Sessions{{ID: 4}, {ID: 5}, {ID: 6}}, nil)
+
+	failure := errors.New("deadlock")
+	svc.closeSession = func(sessionID int64) ([]int64, error) {
+		switch sessionID {
+		case 5:
+			return nil, failure
+		case 6:
+			return nil, nil
+		}
+		return []int64{1, 2, 3}, nil
+	}
+	var evaluated [][]int64
+	svc.evaluate = func(studentIDs ...int64) { evaluated = append(evaluated, studentIDs) }
+
+	closed, marked, err := svc.CloseDueSessions()
+
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, 2, closed)
+	assert.Equal(t, 3, marked)
+	assert.Equal(t, [][]int64{{1, 2, 3}}, evaluated)
+}
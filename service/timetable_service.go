@@ -0,0 +1,226 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutesTimetable registers the timetable routes
+func RoutesTimetable(rg *gin.RouterGroup) {
+	timetable := rg.Group("/timetable")
+
+	timetable.POST("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermTimetableWrite), createTimetableEntry)
+	timetable.GET("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermTimetableRead), getTimetableEntries)
+	timetable.POST("/generate", util.TokenAuthMiddleware(), util.RequirePermission(model.PermTimetableWrite), generateSessions)
+	timetable.GET("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermTimetableRead), getTimetableEntryByID)
+	timetable.PUT("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermTimetableWrite), updateTimetableEntry)
+	timetable.DELETE("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermTimetableWrite), deleteTimetableEntry)
+}
+
+// createTimetableEntry godoc
+// @Summary Create a timetable entry
+// @Description Add a weekly slot of a section. Weekday follows Go's time.Weekday: 0 is Sunday, 6 is Saturday.
+// @Tags Timetable
+// @Accept  json
+// @Produce  json
+// @Param entry body model.TimetableEntry true "Timetable entry"
+// @Success 201 {object} model.TimetableEntry
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /timetable/ [post]
+func createTimetableEntry(c *gin.Context) {
+	var entry model.TimetableEntry
+	if err := c.ShouldBindJSON(&entry); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := timetableSvc.CreateTimetableEntry(entry)
+	if err != nil {
+		handleTimetableError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// getTimetableEntries godoc
+// @Summary List the timetable
+// @Description Get the weekly timetable ordered by weekday and start time, or only a section's with section_id
+// @Tags Timetable
+// @Accept  json
+// @Produce  json
+// @Param section_id query int false "Section ID"
+// @Success 200 {array} model.TimetableEntry
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /timetable/ [get]
+func getTimetableEntries(c *gin.Context) {
+	var sectionID int64
+	if raw := c.Query("section_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid section_id"})
+			return
+		}
+		sectionID = id
+	}
+
+	entries, err := timetableSvc.GetTimetableEntries(sectionID)
+	if err != nil {
+		handleTimetableError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// getTimetableEntryByID godoc
+// @Summary Get a timetable entry
+// @Description Get a timetable entry by ID
+// @Tags Timetable
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Timetable entry ID"
+// @Success 200 {object} model.TimetableEntry
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /timetable/{id} [get]
+func getTimetableEntryByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	entry, err := timetableSvc.GetTimetableEntryByID(id)
+	if err != nil {
+		handleTimetableError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// updateTimetableEntry godoc
+// @Summary Update a timetable entry
+// @Description Replace a weekly slot. Sessions generated from it earlier keep their times.
+// @Tags Timetable
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Timetable entry ID"
+// @Param entry body model.TimetableEntry true "Timetable entry"
+// @Success 200 {object} model.TimetableEntry
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /timetable/{id} [put]
+func updateTimetableEntry(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var entry model.TimetableEntry
+	if err := c.ShouldBindJSON(&entry); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := timetableSvc.UpdateTimetableEntry(id, entry)
+	if err != nil {
+		handleTimetableError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// deleteTimetableEntry godoc
+// @Summary Delete a timetable entry
+// @Description Remove a weekly slot. Sessions generated from it are kept.
+// @Tags Timetable
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Timetable entry ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /timetable/{id} [delete]
+func deleteTimetableEntry(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := timetableSvc.DeleteTimetableEntry(id); err != nil {
+		handleTimetableError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Timetable entry deleted successfully"})
+}
+
+// generateSessions godoc
+// @Summary Generate sessions from the timetable
+// @Description Create the dated sessions of the timetable for every working day of a range of up to 366 days, skipping holidays, weekends and days outside every term. Sessions generated earlier are kept and counted as existing.
+// @Tags Timetable
+// @Accept  json
+// @Produce  json
+// @Param request body model.SessionGenerationRequest true "Date range and optional section"
+// @Success 200 {object} model.SessionGenerationResult
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /timetable/generate [post]
+func generateSessions(c *gin.Context) {
+	var req model.SessionGenerationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := timetableSvc.GenerateSessions(req)
+	if err != nil {
+		handleTimetableError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func handleTimetableError(c *gin.Context, err error) {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error(), "details": validationErr.Fields})
+	case errors.Is(err, repository.ErrTimetableEntryNotFound), errors.Is(err, repository.ErrSectionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
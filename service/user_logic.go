@@ -0,0 +1,185 @@
+package service
+
+import (
+	"net/mail"
+	"strings"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+)
+
+// UserService describes the management of login accounts. It works with
+// UserView so password hashes never leave the service.
+type UserService interface {
+	GetUser(id int64) (model.UserView, error)
+	ListUsers(search string, limit, offset int) (model.UserViews, int, error)
+	CreateUser(input model.UserCreate) (model.UserView, error)
+	UpdateUser(id int64, input model.UserUpdate) (model.UserView, error)
+}
+
+type userService struct {
+	byID           func(id int64) (model.MUser, error)
+	page           func(search string, limit, offset int) ([]model.MUser, int, error)
+	create         func(user model.MUser) (model.MUser, error)
+	update         func(user model.MUser) (model.MUser, error)
+	revokeSessions func(userID int64) error
+}
+
+var userSvc UserService = newUserService()
+
+func newUserService() *userService {
+	return &userService{
+		byID:           repository.GetUserByID,
+		page:           repository.GetUserPage,
+		create:         repository.CreateUser,
+		update:         repository.UpdateUser,
+		revokeSessions: util.RevokeUserSessions,
+	}
+}
+
+func userView(user model.MUser) model.UserView {
+	return model.UserView{
+		ID:                 user.ID,
+		UserName:           user.UserName,
+		Email:              user.Email,
+		AccountExpired:     user.AccountExpired,
+		AccountLocked:      user.AccountLocked,
+		CredentialsExpired: user.CredentialsExpired,
+		Enabled:            user.Enabled,
+	}
+}
+
+// GetUser retrieves one user
+func (s *userService) GetUser(id int64) (model.UserView, error) {
+	user, err := s.byID(id)
+	if err != nil {
+		return model.UserView{}, err
+	}
+	if user.ID == 0 {
+		return model.UserView{}, repository.ErrUserNotFound
+	}
+	return userView(user), nil
+}
+
+// ListUsers retrieves one page of users whose user name or email contains
+// search, and the number of matches
+func (s *userService) ListUsers(search string, limit, offset int) (model.UserViews, int, error) {
+	users, total, err := s.page(strings.TrimSpace(search), limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	views := make(model.UserViews, 0, len(users))
+	for _, user := range users {
+		views = append(views, userView(user))
+	}
+	return views, total, nil
+}
+
+// CreateUser validates and stores a new user with a hashed password
+func (s *userService) CreateUser(input model.UserCreate) (model.UserView, error) {
+	issues := make(map[string]string)
+	if strings.TrimSpace(input.UserName) == "" {
+		issues["userName"] = "userName is required"
+	}
+	if issue := passwordPolicyIssue("password", input.Password); issue != "" {
+		issues["password"] = issue
+	}
+	if len(issues) > 0 {
+		return model.UserView{}, &ValidationError{Fields: issues}
+	}
+
+	hash, err := hashPassword(input.Password)
+	if err != nil {
+		return model.UserView{}, err
+	}
+
+	user := model.MUser{
+		UserName:           strings.TrimSpace(input.UserName),
+		Password:           hash,
+		Email:              strings.TrimSpace(input.Email),
+		AccountExpired:     input.AccountExpired,
+		AccountLocked:      input.AccountLocked,
+		CredentialsExpired: input.CredentialsExpired,
+		Enabled:            input.Enabled == nil || *input.Enabled,
+	}
+
+	created, err := s.create(user)
+	if err != nil {
+		return model.UserView{}, err
+	}
+	return userView(created), nil
+}
+
+// UpdateUser applies the supplied fields to an existing user. A new password
+// is hashed. Setting a password, or leaving the account in a state login
+// refuses (disabled, locked, expired), logs the user out of every session.
+func (s *userService) UpdateUser(id int64, input model.UserUpdate) (model.UserView, error) {
+	issues := make(map[string]string)
+	if input.UserName != nil && strings.TrimSpace(*input.UserName) == "" {
+		issues["userName"] = "userName must not be empty"
+	}
+	if input.Password != nil {
+		if issue := passwordPolicyIssue("password", *input.Password); issue != "" {
+			issues["password"] = issue
+		}
+	}
+	// an empty email removes it, so it cannot be left to the binding rules
+	if input.Email != nil {
+		if email := strings.TrimSpace(*input.Email); email != "" {
+			if _, err := mail.ParseAddress(email); err != nil {
+				issues["email"] = "email format is invalid"
+			}
+		}
+	}
+	if len(issues) > 0 {
+		return model.UserView{}, &ValidationError{Fields: issues}
+	}
+
+	user, err := s.byID(id)
+	if err != nil {
+		return model.UserView{}, err
+	}
+	if user.ID == 0 {
+		return model.UserView{}, repository.ErrUserNotFound
+	}
+
+	if input.UserName != nil {
+		user.UserName = strings.TrimSpace(*input.UserName)
+	}
+	if input.Email != nil {
+		user.Email = strings.TrimSpace(*input.Email)
+	}
+	if input.AccountExpired != nil {
+		user.AccountExpired = *input.AccountExpired
+	}
+	if input.AccountLocked != nil {
+		user.AccountLocked = *input.AccountLocked
+	}
+	if input.CredentialsExpired != nil {
+		user.CredentialsExpired = *input.CredentialsExpired
+	}
+	if input.Enabled != nil {
+		user.Enabled = *input.Enabled
+	}
+	if input.Password != nil {
+		hash, err := hashPassword(*input.Password)
+		if err != nil {
+			return model.UserView{}, err
+		}
+		user.Password = hash
+	}
+
+	updated, err := s.update(user)
+	if err != nil {
+		return model.UserView{}, err
+	}
+
+	if input.Password != nil || repository.CheckAccountFlags(updated) != nil {
+		if err := s.revokeSessions(id); err != nil {
+			return model.UserView{}, err
+		}
+	}
+	return userView(updated), nil
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+	repository "github.com/shravanasati/scopex-go-assignment/repository"
+	util "github.com/shravanasati/scopex-go-assignment/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestUserService(stored model.MUser) (*userService, *[]model.MUser, *[]int64) {
+	var written []model.MUser
+	var loggedOut []int64
+
+	svc := newUserService()
+	svc.byID = func(id int64) (model.MUser, error) {
+		if id == stored.ID {
+			return stored, nil
+		}
+		return model.MUser{}, nil
+	}
+	svc.create = func(user model.MUser) (model.MUser, error) {
+		written = append(written, user)
+		user.ID = 7
+		return user, nil
+	}
+	svc.update = func(user model.MUser) (model.MUser, error) {
+		written = append(written, user)
+		return user, nil
+	}
+	svc.revokeSessions = func(userID int64) error {
+		loggedOut = append(loggedOut, userID)
+		return nil
+	}
+	return svc, &written, &loggedOut
+}
+
+func strPtr(s string) *string { return &s }
+func boolPtr(b bool) *bool    { return &b }
+
+func TestCreateUserHashesPasswordAndEnablesByDefault(t *testing.T) {
+	svc, written, _ := newTestUserService(model.MUser{})
+
+	view, err := svc.CreateUser(model.UserCreate{UserName: " wiro ", Password: "pass3456", Email: "wiro@scopex.local"})
+	assert.NoError(t, err)
+	assert.Equal(t, model.UserView{ID: 7, UserName: "wiro", Email: "wiro@scopex.local", Enabled: true}, view)
+
+	stored := (*written)[0]
+	assert.NotEqual(t, "pass3456", stored.Password)
+	assert.True(t, util.CheckPasswordHash("pass3456", stored.Password))
+
+	_, err = svc.CreateUser(model.UserCreate{UserName: "sari", Password: "pass3456", Enabled: boolPtr(false)})
+	assert.NoError(t, err)
+	assert.False(t, (*written)[1].Enabled)
+}
+
+func TestCreateUserPasswordPolicy(t *testing.T) {
+	svc, written, _ := newTestUserService(model.MUser{})
+
+	for _, password := range []string{"pass345", "passwords", "12345678", string(make([]byte, 73)) + "a1"} {
+		_, err := svc.CreateUser(model.UserCreate{UserName: "wiro", Password: password})
+		var validationErr *ValidationError
+		if assert.True(t, errors.As(err, &validationErr), password) {
+			assert.Contains(t, validationErr.Fields, "password")
+		}
+	}
+	assert.Empty(t, *written)
+}
+
+func TestUpdateUserOnlyTouchesSuppliedFields(t *testing.T) {
+	stored := model.MUser{ID: 2, UserName: "budi", Password: "$2a$10$existinghash", Email: "budi@scopex.local", Enabled: true}
+	svc, written, loggedOut := newTestUserService(stored)
+
+	view, err := svc.UpdateUser(2, model.UserUpdate{CredentialsExpired: boolPtr(false), Email: strPtr("")})
+	assert.NoError(t, err)
+	assert.Equal(t, model.UserView{ID: 2, UserName: "budi", Enabled: true}, view)
+	assert.Equal(t, "$2a$10$existinghash", (*written)[0].Password)
+	assert.Empty(t, *loggedOut)
+
+	_, err = svc.UpdateUser(2, model.UserUpdate{Password: strPtr("n3w-password")})
+	assert.NoError(t, err)
+	assert.True(t, util.CheckPasswordHash("n3w-password", (*written)[1].Password))
+	assert.Equal(t, []int64{2}, *loggedOut)
+
+	_, err = svc.UpdateUser(2, model.UserUpdate{Password: strPtr("short")})
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+
+	_, err = svc.UpdateUser(9, model.UserUpdate{Enabled: boolPtr(false)})
+	assert.ErrorIs(t, err, repository.ErrUserNotFound)
+	assert.Len(t, *written, 2)
+}
+
+func TestUpdateUserHandlerEmail(t *testing.T) {
+	stored := model.MUser{ID: 2, UserName: "budi", Email: "budi@scopex.local", Enabled: true}
+	svc, written, _ := newTestUserService(stored)
+	original := userSvc
+	userSvc = svc
+	t.Cleanup(func() { userSvc = original })
+	params := gin.Params{{Key: "id", Value: "2"}}
+
+	rr, resp := performJSONRequestWithParams(updateUser, http.MethodPut, "/api/user/2", params, map[string]any{"email": ""})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "", resp["email"])
+
+	rr, resp = performJSONRequestWithParams(updateUser, http.MethodPut, "/api/user/2", params, map[string]any{"email": "not-an-address"})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp["details"], "email")
+	assert.Len(t, *written, 1)
+}
+
+func TestUpdateUserLegacyRouteTakesIDFromBody(t *testing.T) {
+	stored := model.MUser{ID: 2, UserName: "budi", Email: "budi@scopex.local", Enabled: true}
+	svc, written, _ := newTestUserService(stored)
+	original := userSvc
+	userSvc = svc
+	t.Cleanup(func() { userSvc = original })
+
+	rr, resp := performJSONRequestWithParams(updateUserLegacy, http.MethodPut, "/api/user/", nil, map[string]any{"id": 2, "userName": "budi2"})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "budi2", resp["userName"])
+	assert.Equal(t, "budi@scopex.local", resp["email"])
+
+	rr, _ = performJSONRequestWithParams(updateUserLegacy, http.MethodPut, "/api/user/", nil, map[string]any{"userName": "budi3"})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Len(t, *written, 1)
+}
+
+func TestUpdateUserLogsOutBlockedAccounts(t *testing.T) {
+	stored := model.MUser{ID: 2, UserName: "budi", Password: "$2a$10$existinghash", Enabled: true}
+
+	for name, input := range map[string]model.UserUpdate{
+		"disabled":            {Enabled: boolPtr(false)},
+		"locked":              {AccountLocked: boolPtr(true)},
+		"expired":             {AccountExpired: boolPtr(true)},
+		"credentials expired": {CredentialsExpired: boolPtr(true)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc, _, loggedOut := newTestUserService(stored)
+
+			_, err := svc.UpdateUser(2, input)
+			assert.NoError(t, err)
+			assert.Equal(t, []int64{2}, *loggedOut)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -15,11 +16,13 @@ import (
 func RoutesUser(rg *gin.RouterGroup) {
 	user := rg.Group("/user")
 
-	user.GET("/:id", util.TokenAuthMiddleware(), getUserByID)
-	user.GET("/", util.TokenAuthMiddleware(), getUsers)
-	user.POST("/", util.TokenAuthMiddleware(), createUser)
-	user.PUT("/", util.TokenAuthMiddleware(), updateUser)
-	user.DELETE("/:id", util.TokenAuthMiddleware(), deleteUserByID)
+	user.GET("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermUsersRead), getUserByID)
+	user.GET("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermUsersRead), getUsers)
+	user.POST("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermUsersWrite), createUser)
+	user.PUT("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermUsersWrite), updateUser)
+	user.PUT("/", util.TokenAuthMiddleware(), util.RequirePermission(model.PermUsersWrite), updateUserLegacy)
+	user.DELETE("/:id", util.TokenAuthMiddleware(), util.RequirePermission(model.PermUsersDelete), deleteUserByID)
+	user.POST("/:id/unlock", util.TokenAuthMiddleware(), util.RequirePermission(model.PermUsersWrite), unlockUser)
 }
 
 // getUserByID godoc
@@ -29,85 +32,79 @@ func RoutesUser(rg *gin.RouterGroup) {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "User ID"
-// @Success 200 {object} model.MUser
-// @Failure 400 {string} string
-// @Failure 404 {object} model.MUser
-// @Failure 500 {string} string
+// @Success 200 {object} model.UserView
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
 // @Security bearerAuth
 // @Router /user/{id} [get]
 func getUserByID(c *gin.Context) {
-	var user model.MUser
-	paramID := c.Param("id")
-	varID, err := strconv.ParseInt(paramID, 10, 64)
-
+	varID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
 
-	user, err = repository.GetUserByID(varID)
+	user, err := userSvc.GetUser(varID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		handleUserError(c, err)
 		return
 	}
 
-	if (model.MUser{}) == user {
-		c.JSON(http.StatusNotFound, user)
-	} else {
-		c.JSON(http.StatusOK, user)
-	}
+	c.JSON(http.StatusOK, user)
 }
 
 // getUsers godoc
 // @Summary show list master user
-// @Description get users
+// @Description Get a page of users ordered by ID, optionally only those whose user name or email contains search
 // @Tags User
 // @Accept  json
 // @Produce  json
-// @Success 200 {array} model.MUser
-// @Failure 400 {string} string
-// @Failure 404 {object} model.MUser
-// @Failure 500 {string} string
+// @Param search query string false "Part of the user name or email"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size, at most 100" default(10) maximum(100)
+// @Success 200 {object} model.UserPage
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 500 {object} map[string]string
 // @Security bearerAuth
 // @Router /user/ [get]
 func getUsers(c *gin.Context) {
+	page, limit, offset := pagination(c)
 
-	var users []model.MUser
-	users, err := repository.GetUserAll()
+	users, total, err := userSvc.ListUsers(c.Query("search"), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
 		return
 	}
 
-	c.JSON(http.StatusOK, users)
-
+	c.JSON(http.StatusOK, model.UserPage{Data: users, Total: total, Page: page, Limit: limit})
 }
 
 // createUser godoc
 // @Summary create master user
-// @Description add by json master user
+// @Description Create a user. The password must be 8 to 72 bytes long and contain a letter and a digit. Enabled defaults to true.
 // @Tags User
 // @Accept  json
 // @Produce  json
-// @Param user body model.MUser true "User ID"
-// @Success 200 {object} model.MUser
-// @Failure 400 {string} string
-// @Failure 404 {string} string
-// @Failure 500 {string} string
+// @Param user body model.UserCreate true "User"
+// @Success 201 {object} model.UserView
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
 // @Security bearerAuth
 // @Router /user/ [post]
 func createUser(c *gin.Context) {
-
-	var user model.MUser
-
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": "invalid json"})
+	var input model.UserCreate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	user, err := repository.CreateUser(user)
+	user, err := userSvc.CreateUser(input)
 	if err != nil {
-		c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		handleUserError(c, err)
 		return
 	}
 
@@ -116,33 +113,86 @@ func createUser(c *gin.Context) {
 
 // updateUser godoc
 // @Summary update master user
-// @Description update by json master user
+// @Description Update the supplied fields of a user; omitted fields keep their value. The password is only changed when supplied, follows the same policy as on creation, and logs the user out of every session. Disabling, locking or expiring the account, or expiring its credentials, logs the user out too. An empty email removes it.
 // @Tags User
 // @Accept  json
 // @Produce  json
-// @Param user body model.MUser true "User ID"
-// @Success 200 {object} model.MUser
-// @Failure 400 {string} string
-// @Failure 404 {string} string
-// @Failure 500 {string} string
+// @Param id path int true "User ID"
+// @Param user body model.UserUpdate true "Fields to change"
+// @Success 200 {object} model.UserView
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
 // @Security bearerAuth
-// @Router /user/ [put]
+// @Router /user/{id} [put]
 func updateUser(c *gin.Context) {
+	varID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
 
-	var user model.MUser
+	var input model.UserUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": "invalid json"})
+	user, err := userSvc.UpdateUser(varID, input)
+	if err != nil {
+		handleUserError(c, err)
 		return
 	}
 
-	usr, err := repository.UpdateUser(user)
+	c.JSON(http.StatusOK, user)
+}
+
+// updateUserLegacy godoc
+// @Summary update master user (deprecated)
+// @Description Kept for clients of the original API, which send the user id in the body. It behaves like PUT /user/{id}; use that route instead.
+// @Tags User
+// @Accept  json
+// @Produce  json
+// @Param user body model.LegacyUserUpdate true "User ID and fields to change"
+// @Success 200 {object} model.UserView
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Deprecated
+// @Router /user/ [put]
+func updateUserLegacy(c *gin.Context) {
+	var input model.LegacyUserUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := userSvc.UpdateUser(input.ID, input.UserUpdate)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		handleUserError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, usr)
+	c.JSON(http.StatusOK, user)
+}
+
+func handleUserError(c *gin.Context, err error) {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error(), "details": validationErr.Fields})
+	case errors.Is(err, repository.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrUserExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
 
 // deleteUserByID godoc
@@ -152,16 +202,13 @@ func updateUser(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "User ID" Format(int64)
-// @Success 200 {object} model.MUser
-// @Failure 400 {string} string
-// @Failure 404 {object} model.MUser
-// @Failure 500 {string} string
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
 // @Security bearerAuth
 // @Router /user/{id} [delete]
 func deleteUserByID(c *gin.Context) {
 
-	var user model.MUser
-
 	paramID := c.Param("id")
 	varID, err := strconv.ParseInt(paramID, 10, 64)
 	if err != nil {
@@ -175,5 +222,39 @@ func deleteUserByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusNoContent, user)
+	c.Status(http.StatusNoContent)
+}
+
+// unlockUser godoc
+// @Summary unlock a master user
+// @Description Clear the locked flag of a user and lift the lockout left by failed logins for their username
+// @Tags User
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID" Format(int64)
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} util.PermissionDenied
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security bearerAuth
+// @Router /user/{id}/unlock [post]
+func unlockUser(c *gin.Context) {
+	varID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	err = loginSvc.Unlock(varID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unlocked successfully"})
 }
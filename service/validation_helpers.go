@@ -8,6 +8,8 @@ import (
 
 const isoDateLayout = "2006-01-02"
 
+const clockTimeLayout = "15:04"
+
 // validateISODate ensures a date string follows the YYYY-MM-DD layout and
 // represents a real calendar date.
 func validateISODate(dateStr string) error {
@@ -22,3 +24,43 @@ func validateISODate(dateStr string) error {
 
 	return nil
 }
+
+// validateTimeRange ensures start and end are HH:MM clock times and that the
+// range ends after it starts.
+func validateTimeRange(start, end string) error {
+	startTime, err := time.Parse(clockTimeLayout, strings.TrimSpace(start))
+	if err != nil {
+		return fmt.Errorf("start_time must be in HH:MM format")
+	}
+	endTime, err := time.Parse(clockTimeLayout, strings.TrimSpace(end))
+	if err != nil {
+		return fmt.Errorf("end_time must be in HH:MM format")
+	}
+	if !endTime.After(startTime) {
+		return fmt.Errorf("end_time must be after start_time")
+	}
+
+	return nil
+}
+
+// validateDateRange ensures from and to are both YYYY-MM-DD dates and that
+// the range does not end before it starts.
+func validateDateRange(from, to string) error {
+	issues := make(map[string]string)
+
+	if err := validateISODate(from); err != nil {
+		issues["from"] = err.Error()
+	}
+	if err := validateISODate(to); err != nil {
+		issues["to"] = err.Error()
+	}
+	if len(issues) == 0 && from > to {
+		issues["to"] = "to must not be before from"
+	}
+
+	if len(issues) > 0 {
+		return &ValidationError{Fields: issues}
+	}
+
+	return nil
+}
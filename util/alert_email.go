@@ -0,0 +1,86 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+
+	"github.com/shravanasati/scopex-go-assignment/model"
+)
+
+const alertEmailTemplateHTML = `
+<!DOCTYPE html>
+<html>
+<head>
+    <style>
+        body { font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; line-height: 1.6; color: #333; background-color: #f4f4f4; margin: 0; padding: 0; }
+        .container { max-width: 600px; margin: 20px auto; padding: 20px; background-color: #ffffff; border-radius: 8px; box-shadow: 0 4px 8px rgba(0,0,0,0.1); }
+        .header { text-align: center; padding-bottom: 20px; border-bottom: 2px solid #eee; margin-bottom: 20px; }
+        .header h2 { color: #c0392b; margin: 0; }
+        .content { padding: 0 10px; }
+        .info-group { margin-bottom: 15px; }
+        .label { font-weight: 600; color: #555; display: inline-block; width: 120px; }
+        .value { color: #333; }
+        .stats { display: flex; flex-wrap: wrap; justify-content: space-around; margin-top: 30px; background-color: #f9f9f9; padding: 15px; border-radius: 6px; }
+        .stat-box { text-align: center; }
+        .stat-number { display: block; font-size: 24px; font-weight: bold; }
+        .stat-number.absent { color: #c0392b; }
+        .stat-label { font-size: 14px; color: #777; }
+        .footer { margin-top: 30px; text-align: center; font-size: 12px; color: #aaa; border-top: 1px solid #eee; padding-top: 10px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h2>Low Attendance Alert</h2>
+        </div>
+        <div class="content">
+            <div class="info-group">
+                <span class="label">Student Name:</span>
+                <span class="value">{{.Student.Name}}</span>
+            </div>
+            <div class="info-group">
+                <span class="label">Period:</span>
+                <span class="value">{{.Alert.WindowStart}} to {{.Alert.WindowEnd}}</span>
+            </div>
+            <div class="stats">
+                <div class="stat-box">
+                    <span class="stat-number absent">{{printf "%.2f" .Alert.Percentage}}%</span>
+                    <span class="stat-label">Attendance</span>
+                </div>
+                <div class="stat-box">
+                    <span class="stat-number">{{printf "%.0f" .Alert.Threshold}}%</span>
+                    <span class="stat-label">Required</span>
+                </div>
+            </div>
+        </div>
+        <div class="footer">
+            <p>Generated by ScopeX Attendance System</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+// SendAlertEmail notifies that a student's attendance fell below the
+// configured threshold
+func SendAlertEmail(alert model.AttendanceAlert, student model.Student) {
+	tmpl, err := template.New("alert").Parse(alertEmailTemplateHTML)
+	if err != nil {
+		fmt.Printf("failed to parse alert email template: %v\n", err)
+		return
+	}
+
+	var body bytes.Buffer
+	data := struct {
+		Alert   model.AttendanceAlert
+		Student model.Student
+	}{alert, student}
+	if err := tmpl.Execute(&body, data); err != nil {
+		fmt.Printf("failed to execute alert email template: %v\n", err)
+		return
+	}
+
+	subject := fmt.Sprintf("Low attendance alert for %s", student.Name)
+	deliverEmail(subject, body.String(), student.Email)
+}
@@ -0,0 +1,121 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gomodule/redigo/redis"
+	"github.com/segmentio/ksuid"
+)
+
+// checkinPurpose marks a JWT as a check-in token so it can never be used as
+// an access token and access tokens are never accepted for check-in.
+const checkinPurpose = "checkin"
+
+// ErrInvalidCheckinToken is returned for a check-in token that is malformed,
+// expired or not signed by this server.
+var ErrInvalidCheckinToken = errors.New("check-in token is invalid or expired")
+
+// CheckinToken describes a signed, time-boxed check-in token for a session
+type CheckinToken struct {
+	Token     string `json:"token"`
+	TokenID   string `json:"-"`
+	SessionID int64  `json:"session_id" example:"1"`
+	ExpiresAt int64  `json:"expires_at" example:"1698400000"`
+}
+
+// CreateCheckinToken signs a check-in token for the session that expires
+// after ttl, using the same HS256 signing as access tokens.
+func CreateCheckinToken(sessionID int64, ttl time.Duration) (CheckinToken, error) {
+	ct := CheckinToken{
+		TokenID:   ksuid.New().String(),
+		SessionID: sessionID,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"exp":        ct.ExpiresAt,
+		"jti":        ct.TokenID,
+		"session_id": sessionID,
+		"purpose":    checkinPurpose,
+	})
+
+	var err error
+	ct.Token, err = token.SignedString([]byte(accessSecret))
+	if err != nil {
+		return CheckinToken{}, err
+	}
+
+	return ct, nil
+}
+
+// VerifyCheckinToken checks the signature, expiry and purpose of a check-in
+// token and returns its claims.
+func VerifyCheckinToken(tokenStr string) (CheckinToken, error) {
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		return []byte(accessSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return CheckinToken{}, ErrInvalidCheckinToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != checkinPurpose {
+		return CheckinToken{}, ErrInvalidCheckinToken
+	}
+	tokenID, ok := claims["jti"].(string)
+	sessionID, okSession := claims["session_id"].(float64)
+	expiresAt, okExpiry := claims["exp"].(float64)
+	if !ok || !okSession || !okExpiry {
+		return CheckinToken{}, ErrInvalidCheckinToken
+	}
+
+	return CheckinToken{
+		Token:     tokenStr,
+		TokenID:   tokenID,
+		SessionID: int64(sessionID),
+		ExpiresAt: int64(expiresAt),
+	}, nil
+}
+
+func checkinClaimKey(ct CheckinToken, studentID int64) string {
+	return fmt.Sprintf("checkin:%s:%d", ct.TokenID, studentID)
+}
+
+// ClaimCheckin records that the student used the check-in token. It returns
+// false when the student already used it. The key expires with the token.
+func ClaimCheckin(ct CheckinToken, studentID int64) (bool, error) {
+	conn := Pool.Get()
+	defer conn.Close()
+
+	ttl := ct.ExpiresAt - time.Now().Unix()
+	if ttl < 1 {
+		ttl = 1
+	}
+
+	_, err := redis.String(conn.Do("SET", checkinClaimKey(ct, studentID), 1, "NX", "EX", ttl))
+	if errors.Is(err, redis.ErrNil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ReleaseCheckin forgets a claim made by ClaimCheckin, so a check-in that
+// failed after claiming can be retried with the same token
+func ReleaseCheckin(ct CheckinToken, studentID int64) error {
+	conn := Pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("DEL", checkinClaimKey(ct, studentID))
+	return err
+}
@@ -0,0 +1,45 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckinTokenRoundTrip(t *testing.T) {
+	ct, err := CreateCheckinToken(42, time.Minute)
+	if err != nil {
+		t.Fatalf("CreateCheckinToken failed: %v", err)
+	}
+
+	verified, err := VerifyCheckinToken(ct.Token)
+	if err != nil {
+		t.Fatalf("VerifyCheckinToken failed: %v", err)
+	}
+	if verified.SessionID != 42 || verified.TokenID != ct.TokenID || verified.ExpiresAt != ct.ExpiresAt {
+		t.Errorf("VerifyCheckinToken returned %+v, want claims of %+v", verified, ct)
+	}
+}
+
+func TestCheckinTokenRejectsExpiredAndTampered(t *testing.T) {
+	expired, _ := CreateCheckinToken(42, -time.Minute)
+	if _, err := VerifyCheckinToken(expired.Token); err != ErrInvalidCheckinToken {
+		t.Errorf("expired token: got %v, want ErrInvalidCheckinToken", err)
+	}
+
+	ct, _ := CreateCheckinToken(42, time.Minute)
+	tampered := ct.Token[:strings.LastIndex(ct.Token, ".")] + ".c2lnbmF0dXJl"
+	if _, err := VerifyCheckinToken(tampered); err != ErrInvalidCheckinToken {
+		t.Errorf("tampered token: got %v, want ErrInvalidCheckinToken", err)
+	}
+}
+
+func TestRenderQRSVG(t *testing.T) {
+	svg, err := RenderQRSVG("checkin-token")
+	if err != nil {
+		t.Fatalf("RenderQRSVG failed: %v", err)
+	}
+	if !strings.HasPrefix(string(svg), "<svg") || !strings.Contains(string(svg), "<path d=\"M") {
+		t.Errorf("RenderQRSVG returned unexpected markup: %.80s", svg)
+	}
+}
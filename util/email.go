@@ -23,7 +23,7 @@ const emailTemplateHTML = `
         .info-group { margin-bottom: 15px; }
         .label { font-weight: 600; color: #555; display: inline-block; width: 120px; }
         .value { color: #333; }
-        .stats { display: flex; justify-content: space-around; margin-top: 30px; background-color: #f9f9f9; padding: 15px; border-radius: 6px; }
+        .stats { display: flex; flex-wrap: wrap; justify-content: space-around; margin-top: 30px; background-color: #f9f9f9; padding: 15px; border-radius: 6px; }
         .stat-box { text-align: center; }
         .stat-number { display: block; font-size: 24px; font-weight: bold; }
         .stat-number.present { color: #27ae60; }
@@ -60,7 +60,21 @@ const emailTemplateHTML = `
                     <span class="stat-number absent">{{.AbsentCount}}</span>
                     <span class="stat-label">Days Absent</span>
                 </div>
+                <div class="stat-box">
+                    <span class="stat-number">{{.UnmarkedWorkingDays}}</span>
+                    <span class="stat-label">Unmarked Working Days</span>
+                </div>
+            </div>
+            {{if .StatusCounts}}
+            <div class="stats">
+                {{range .StatusCounts}}
+                <div class="stat-box">
+                    <span class="stat-number {{if .CountsAsPresent}}present{{else if not .Excused}}absent{{end}}">{{.Count}}</span>
+                    <span class="stat-label">{{.Label}}</span>
+                </div>
+                {{end}}
             </div>
+            {{end}}
         </div>
         <div class="footer">
             <p>Generated by ScopeX Attendance System</p>
@@ -71,12 +85,6 @@ const emailTemplateHTML = `
 `
 
 func SendEmail(report model.AttendanceReport) {
-	apiKey := os.Getenv("RESEND_API_KEY")
-	if apiKey == "" {
-		fmt.Println("skipping email notification due to absence of env RESEND_API_KEY")
-		return
-	}
-
 	tmpl, err := template.New("email").Parse(emailTemplateHTML)
 	if err != nil {
 		fmt.Printf("failed to parse email template: %v\n", err)
@@ -90,13 +98,24 @@ func SendEmail(report model.AttendanceReport) {
 	}
 
 	subject := fmt.Sprintf("Attendance Report for %s", report.StudentName)
+	deliverEmail(subject, body.String(), report.StudentEmail)
+}
+
+// deliverEmail sends an HTML email through resend when RESEND_API_KEY is set.
+// recipient is only logged; mail goes to the resend test inbox.
+func deliverEmail(subject, html, recipient string) {
+	apiKey := os.Getenv("RESEND_API_KEY")
+	if apiKey == "" {
+		fmt.Println("skipping email notification due to absence of env RESEND_API_KEY")
+		return
+	}
 
 	client := resend.NewClient(apiKey)
 
 	params := &resend.SendEmailRequest{
 		From:    "Acme <onboarding@resend.dev>",
 		To:      []string{"delivered@resend.dev"},
-		Html:    body.String(),
+		Html:    html,
 		Subject: subject,
 	}
 
@@ -105,5 +124,5 @@ func SendEmail(report model.AttendanceReport) {
 		fmt.Println("error in email sending:", err.Error())
 		return
 	}
-	fmt.Println("email notification sent to", report.StudentEmail, "id:", sent.Id)
+	fmt.Println("email notification sent to", recipient, "id:", sent.Id)
 }
@@ -35,14 +35,18 @@ type TokenDetails struct {
 	RefreshToken string `json:"refreshToken"`
 	AccessUUID   string `json:"-"`
 	RefreshUUID  string `json:"-"`
+	FamilyID     string `json:"-"`
 	AtExpires    int64  `json:"atExpires"`
 	RtExpires    int64  `json:"rtExpires"`
 }
 
 // AccessDetails ...
 type AccessDetails struct {
-	AccessUUID string
-	UserID     int64
+	AccessUUID  string
+	FamilyID    string
+	UserID      int64
+	Roles       []string
+	Permissions []string
 }
 
 // SetupRedisJWT ...
@@ -66,10 +70,17 @@ func SetupRedisJWT() *redis.Pool {
 
 }
 
-// CreateToken ...
-func CreateToken(u model.MUser) (*TokenDetails, error) {
+// CreateToken signs the token pair of a user and starts a new token family,
+// which every pair rotated from it by a refresh shares. The access token
+// carries the roles and permissions of grants, so role changes apply from
+// the next login or refresh.
+func CreateToken(u model.MUser, grants model.Grants) (*TokenDetails, error) {
+	return createToken(u, grants, ksuid.New().String())
+}
+
+func createToken(u model.MUser, grants model.Grants, familyID string) (*TokenDetails, error) {
 
-	td := &TokenDetails{}
+	td := &TokenDetails{FamilyID: familyID}
 	td.AtExpires = time.Now().Add(time.Minute * time.Duration(timeToken)).Unix()
 	td.AccessUUID = ksuid.New().String()
 
@@ -81,9 +92,12 @@ func CreateToken(u model.MUser) (*TokenDetails, error) {
 	at := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"exp":         td.AtExpires,
 		"access_uuid": td.AccessUUID,
+		"family_id":   td.FamilyID,
 		"user_id":     u.ID,
 		"name":        u.UserName,
 		"authorized":  true,
+		"roles":       grants.Roles,
+		"permissions": grants.Permissions,
 	})
 	td.AccessToken, err = at.SignedString([]byte(accessSecret))
 	if err != nil {
@@ -93,6 +107,7 @@ func CreateToken(u model.MUser) (*TokenDetails, error) {
 	rt := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"exp":          td.RtExpires,
 		"refresh_uuid": td.RefreshUUID,
+		"family_id":    td.FamilyID,
 		"user_id":      u.ID,
 		"name":         u.UserName,
 	})
@@ -104,8 +119,18 @@ func CreateToken(u model.MUser) (*TokenDetails, error) {
 	return td, nil
 }
 
-// SaveToRedis ...
-func SaveToRedis(userID int64, td *TokenDetails) error {
+// ClientInfo describes the client a login came from
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// SaveToRedis stores the token pair until it expires and records it as the
+// live pair of its token family, which is listed in the user's session index.
+// The family keeps the client and creation time of the login that started it.
+// A family revoked meanwhile is not brought back: the pair is removed again
+// and ErrTokenFamilyRevoked is returned.
+func SaveToRedis(userID int64, td *TokenDetails, client ClientInfo) error {
 
 	// Use the connection pool's Get() method to fetch a single Redis
 	// connection from the pool.
@@ -115,19 +140,70 @@ func SaveToRedis(userID int64, td *TokenDetails) error {
 	// ensure that the connection is always returned to the pool.
 	defer conn.Close()
 
-	_, errAccess := conn.Do("SET", td.AccessUUID, userID)
+	now := time.Now().Unix()
+	atTTL := ttlUntil(td.AtExpires, now)
+	rtTTL := ttlUntil(td.RtExpires, now)
+
+	_, errAccess := conn.Do("SET", td.AccessUUID, userID, "EX", atTTL)
 	if errAccess != nil {
 		return errAccess
 	}
 
-	_, errReferesh := conn.Do("SET", td.RefreshUUID, userID)
+	_, errReferesh := conn.Do("SET", td.RefreshUUID, userID, "EX", rtTTL)
 	if errReferesh != nil {
 		return errReferesh
 	}
 
+	// the family points at its live refresh token so reuse can revoke it
+	family := tokenFamilyKey(td.FamilyID)
+	for _, field := range [][2]interface{}{{"created_at", now}, {"ip", client.IP}, {"user_agent", client.UserAgent}} {
+		if _, err := conn.Do("HSETNX", family, field[0], field[1]); err != nil {
+			return err
+		}
+	}
+	if _, err := conn.Do("HSET", family, "refresh_uuid", td.RefreshUUID, "user_id", userID, "refreshed_at", now); err != nil {
+		return err
+	}
+	if _, err := conn.Do("EXPIRE", family, rtTTL); err != nil {
+		return err
+	}
+
+	// every family of the user lives at most as long as the newest one
+	index := userSessionsKey(userID)
+	if _, err := conn.Do("SADD", index, td.FamilyID); err != nil {
+		return err
+	}
+	if _, err := conn.Do("EXPIRE", index, rtTTL); err != nil {
+		return err
+	}
+
+	// checked after writing: a revocation that has not set its marker by now
+	// reads the family afterwards and deletes the pair written above itself
+	revoked, err := isFamilyRevoked(conn, td.FamilyID)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		if _, err := conn.Do("DEL", td.AccessUUID, td.RefreshUUID, family); err != nil {
+			return err
+		}
+		if _, err := conn.Do("SREM", index, td.FamilyID); err != nil {
+			return err
+		}
+		return ErrTokenFamilyRevoked
+	}
+
 	return nil
 }
 
+// ttlUntil returns the seconds left until the unix time exp, at least 1
+func ttlUntil(exp, now int64) int64 {
+	if exp-now < 1 {
+		return 1
+	}
+	return exp - now
+}
+
 // ExtractFromRedis ...
 func ExtractFromRedis(r *http.Request) (*AccessDetails, error) {
 	tokenStr := ExtractToken(r)
@@ -141,7 +217,7 @@ func ExtractFromRedis(r *http.Request) (*AccessDetails, error) {
 	if ok && token.Valid {
 		accessUUID, ok := claims["access_uuid"].(string)
 		if !ok {
-			return nil, err
+			return nil, errors.New("Authentification failure")
 		}
 
 		userID := claims["user_id"].(float64)
@@ -159,14 +235,32 @@ func ExtractFromRedis(r *http.Request) (*AccessDetails, error) {
 			return nil, errors.New("Authentification failure")
 		}
 
+		familyID, _ := claims["family_id"].(string)
+
 		return &AccessDetails{
-			AccessUUID: accessUUID,
-			UserID:     redisIDUser,
+			AccessUUID:  accessUUID,
+			FamilyID:    familyID,
+			UserID:      redisIDUser,
+			Roles:       claimStrings(claims, "roles"),
+			Permissions: claimStrings(claims, "permissions"),
 		}, nil
 	}
 	return nil, err
 }
 
+// claimStrings reads a list of strings from the claims, which decode JSON
+// arrays as []interface{}. A missing claim yields an empty list.
+func claimStrings(claims jwt.MapClaims, key string) []string {
+	values, _ := claims[key].([]interface{})
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
 // ExtractToken ...
 func ExtractToken(r *http.Request) string {
 
@@ -231,5 +325,13 @@ func DeleteToken(authD *AccessDetails) error {
 		return err
 	}
 
+	// end the token family
+	if authD.FamilyID != "" {
+		err = RevokeTokenFamily(authD.FamilyID)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,88 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+
+	"github.com/shravanasati/scopex-go-assignment/model"
+)
+
+const leaveEmailTemplateHTML = `
+<!DOCTYPE html>
+<html>
+<head>
+    <style>
+        body { font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; line-height: 1.6; color: #333; background-color: #f4f4f4; margin: 0; padding: 0; }
+        .container { max-width: 600px; margin: 20px auto; padding: 20px; background-color: #ffffff; border-radius: 8px; box-shadow: 0 4px 8px rgba(0,0,0,0.1); }
+        .header { text-align: center; padding-bottom: 20px; border-bottom: 2px solid #eee; margin-bottom: 20px; }
+        .header h2 { color: #2c3e50; margin: 0; }
+        .content { padding: 0 10px; }
+        .info-group { margin-bottom: 15px; }
+        .label { font-weight: 600; color: #555; display: inline-block; width: 120px; }
+        .value { color: #333; }
+        .state { font-weight: bold; text-transform: capitalize; }
+        .state.approved { color: #27ae60; }
+        .state.rejected, .state.cancelled { color: #c0392b; }
+        .footer { margin-top: 30px; text-align: center; font-size: 12px; color: #aaa; border-top: 1px solid #eee; padding-top: 10px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h2>Leave Request #{{.Leave.ID}}</h2>
+        </div>
+        <div class="content">
+            <div class="info-group">
+                <span class="label">Student Name:</span>
+                <span class="value">{{.Student.Name}}</span>
+            </div>
+            <div class="info-group">
+                <span class="label">Dates:</span>
+                <span class="value">{{.Leave.FromDate}} to {{.Leave.ToDate}}</span>
+            </div>
+            <div class="info-group">
+                <span class="label">Reason:</span>
+                <span class="value">{{.Leave.Reason}}</span>
+            </div>
+            <div class="info-group">
+                <span class="label">State:</span>
+                <span class="value state {{.Leave.State}}">{{.Leave.State}}</span>
+            </div>
+            {{if .Leave.DecisionNote}}
+            <div class="info-group">
+                <span class="label">Note:</span>
+                <span class="value">{{.Leave.DecisionNote}}</span>
+            </div>
+            {{end}}
+        </div>
+        <div class="footer">
+            <p>Generated by ScopeX Attendance System</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+// SendLeaveRequestEmail notifies about a leave request being submitted or
+// changing state
+func SendLeaveRequestEmail(leave model.LeaveRequest, student model.Student) {
+	tmpl, err := template.New("leave").Parse(leaveEmailTemplateHTML)
+	if err != nil {
+		fmt.Printf("failed to parse leave email template: %v\n", err)
+		return
+	}
+
+	var body bytes.Buffer
+	data := struct {
+		Leave   model.LeaveRequest
+		Student model.Student
+	}{leave, student}
+	if err := tmpl.Execute(&body, data); err != nil {
+		fmt.Printf("failed to execute leave email template: %v\n", err)
+		return
+	}
+
+	subject := fmt.Sprintf("Leave request for %s is %s", student.Name, leave.State)
+	deliverEmail(subject, body.String(), student.Email)
+}
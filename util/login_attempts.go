@@ -0,0 +1,109 @@
+package util
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// LoginThrottle limits failed logins per username and per client IP. Once
+// MaxFailures (or IPMaxFailures) failures happen within Window, logins for
+// the username (or from the IP) are refused for LockFor. A limit of 0
+// disables that check.
+type LoginThrottle struct {
+	MaxFailures   int
+	IPMaxFailures int
+	Window        time.Duration
+	LockFor       time.Duration
+}
+
+// usernames are matched case-insensitively by the database, so the counters
+// are too
+func loginUserID(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+func loginFailuresKey(kind, id string) string {
+	return "login-failures:" + kind + ":" + id
+}
+
+func loginLockKey(kind, id string) string {
+	return "login-lock:" + kind + ":" + id
+}
+
+// LoginLockedFor returns how long logins for username or from ip remain
+// refused, or 0 when neither is locked
+func LoginLockedFor(username, ip string) (time.Duration, error) {
+	conn := Pool.Get()
+	defer conn.Close()
+
+	var longest int64
+	for _, key := range []string{loginLockKey("user", loginUserID(username)), loginLockKey("ip", ip)} {
+		ttl, err := redis.Int64(conn.Do("TTL", key))
+		if err != nil {
+			return 0, err
+		}
+		if ttl > longest {
+			longest = ttl
+		}
+	}
+	return time.Duration(longest) * time.Second, nil
+}
+
+// RecordLoginFailure counts a failed login for username and ip. It returns
+// the lock duration when this failure reached a limit, or 0.
+func RecordLoginFailure(username, ip string, t LoginThrottle) (time.Duration, error) {
+	conn := Pool.Get()
+	defer conn.Close()
+
+	var locked time.Duration
+	for _, c := range []struct {
+		kind, id string
+		limit    int
+	}{{"user", loginUserID(username), t.MaxFailures}, {"ip", ip, t.IPMaxFailures}} {
+		if c.limit <= 0 || c.id == "" {
+			continue
+		}
+
+		key := loginFailuresKey(c.kind, c.id)
+		failures, err := redis.Int(conn.Do("INCR", key))
+		if err != nil {
+			return 0, err
+		}
+		if failures == 1 {
+			if _, err := conn.Do("EXPIRE", key, ttlSeconds(t.Window)); err != nil {
+				return 0, err
+			}
+		}
+		if failures < c.limit {
+			continue
+		}
+
+		if _, err := conn.Do("SET", loginLockKey(c.kind, c.id), failures, "EX", ttlSeconds(t.LockFor)); err != nil {
+			return 0, err
+		}
+		if _, err := conn.Do("DEL", key); err != nil {
+			return 0, err
+		}
+		locked = t.LockFor
+	}
+	return locked, nil
+}
+
+// ClearLoginFailures forgets the failed logins and the lock of a username
+func ClearLoginFailures(username string) error {
+	conn := Pool.Get()
+	defer conn.Close()
+
+	id := loginUserID(username)
+	_, err := conn.Do("DEL", loginFailuresKey("user", id), loginLockKey("user", id))
+	return err
+}
+
+func ttlSeconds(d time.Duration) int64 {
+	if d < time.Second {
+		return 1
+	}
+	return int64(d / time.Second)
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordLoginFailureLocksUsername(t *testing.T) {
+	store := withFakeRedis(t)
+	throttle := LoginThrottle{MaxFailures: 3, IPMaxFailures: 10, Window: 15 * time.Minute, LockFor: 5 * time.Minute}
+
+	for i := 0; i < 2; i++ {
+		locked, err := RecordLoginFailure("Budi", "203.0.113.7", throttle)
+		if err != nil || locked != 0 {
+			t.Fatalf("failure %d: locked %v, err %v", i+1, locked, err)
+		}
+	}
+	if ttl := store.expiry(loginFailuresKey("user", "budi")); ttl != 900 {
+		t.Errorf("failure window TTL = %d, want 900", ttl)
+	}
+
+	locked, err := RecordLoginFailure("budi ", "203.0.113.7", throttle)
+	if err != nil || locked != 5*time.Minute {
+		t.Fatalf("third failure: locked %v, err %v; want 5m", locked, err)
+	}
+
+	remaining, err := LoginLockedFor("BUDI", "198.51.100.1")
+	if err != nil || remaining != 5*time.Minute {
+		t.Errorf("LoginLockedFor = %v, %v; want 5m", remaining, err)
+	}
+	if remaining, _ := LoginLockedFor("anduk", "198.51.100.1"); remaining != 0 {
+		t.Errorf("another username is locked for %v", remaining)
+	}
+
+	if err := ClearLoginFailures("budi"); err != nil {
+		t.Fatalf("ClearLoginFailures failed: %v", err)
+	}
+	if remaining, _ := LoginLockedFor("budi", "198.51.100.1"); remaining != 0 {
+		t.Errorf("unlocked username is still locked for %v", remaining)
+	}
+}
+
+func TestRecordLoginFailureLocksIP(t *testing.T) {
+	withFakeRedis(t)
+	throttle := LoginThrottle{MaxFailures: 5, IPMaxFailures: 2, Window: time.Minute, LockFor: time.Minute}
+
+	RecordLoginFailure("budi", "203.0.113.7", throttle)
+	locked, _ := RecordLoginFailure("anduk", "203.0.113.7", throttle)
+	if locked != time.Minute {
+		t.Fatalf("second failure from the IP: locked %v, want 1m", locked)
+	}
+
+	if remaining, _ := LoginLockedFor("haya", "203.0.113.7"); remaining != time.Minute {
+		t.Errorf("IP lock = %v, want 1m", remaining)
+	}
+	if remaining, _ := LoginLockedFor("haya", "198.51.100.1"); remaining != 0 {
+		t.Errorf("another IP is locked for %v", remaining)
+	}
+}
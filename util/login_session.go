@@ -0,0 +1,155 @@
+package util
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrLoginSessionNotFound is returned when a session does not exist or
+// belongs to another user.
+var ErrLoginSessionNotFound = errors.New("session not found")
+
+// ErrTokenFamilyRevoked is returned when a token pair would be stored in a
+// token family that was revoked, e.g. by a concurrent reuse of its refresh
+// token.
+var ErrTokenFamilyRevoked = errors.New("the session has been revoked")
+
+// A login session is a token family: the hash at tokenFamilyKey holds the
+// live refresh UUID, the owner and the client of the login, and the set at
+// userSessionsKey indexes the families of a user. Revoking a family leaves
+// the marker at revokedFamilyKey behind, so that no pair is written into it
+// again while any of its refresh tokens is still valid.
+func tokenFamilyKey(familyID string) string {
+	return "token-family:" + familyID
+}
+
+func revokedFamilyKey(familyID string) string {
+	return "token-family-revoked:" + familyID
+}
+
+func userSessionsKey(userID int64) string {
+	return "user-sessions:" + strconv.FormatInt(userID, 10)
+}
+
+// ListSessions returns the active sessions of a user, newest first.
+// currentID marks the session of the caller's token.
+func ListSessions(userID int64, currentID string) (model.LoginSessions, error) {
+	conn := Pool.Get()
+	defer conn.Close()
+
+	familyIDs, err := redis.Strings(conn.Do("SMEMBERS", userSessionsKey(userID)))
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := model.LoginSessions{}
+	for _, familyID := range familyIDs {
+		fields, err := redis.StringMap(conn.Do("HGETALL", tokenFamilyKey(familyID)))
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) == 0 {
+			// the family expired or was revoked
+			if _, err := conn.Do("SREM", userSessionsKey(userID), familyID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		sessions = append(sessions, model.LoginSession{
+			ID:          familyID,
+			CreatedAt:   unixField(fields["created_at"]),
+			RefreshedAt: unixField(fields["refreshed_at"]),
+			IP:          fields["ip"],
+			UserAgent:   fields["user_agent"],
+			Current:     familyID == currentID,
+		})
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+func unixField(value string) time.Time {
+	sec, _ := strconv.ParseInt(value, 10, 64)
+	return time.Unix(sec, 0).UTC()
+}
+
+// RevokeUserSession logs out one session of a user
+func RevokeUserSession(userID int64, familyID string) error {
+	conn := Pool.Get()
+	owner, err := redis.Int64(conn.Do("HGET", tokenFamilyKey(familyID), "user_id"))
+	conn.Close()
+	if errors.Is(err, redis.ErrNil) || (err == nil && owner != userID) {
+		return ErrLoginSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return RevokeTokenFamily(familyID)
+}
+
+// RevokeUserSessions logs out every session of a user
+func RevokeUserSessions(userID int64) error {
+	conn := Pool.Get()
+	familyIDs, err := redis.Strings(conn.Do("SMEMBERS", userSessionsKey(userID)))
+	conn.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, familyID := range familyIDs {
+		if err := RevokeTokenFamily(familyID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RevokeTokenFamily deletes the live token pair of a token family, logging
+// out the session it was issued to. The family is marked revoked first, so
+// a pair rotated concurrently is refused by SaveToRedis.
+func RevokeTokenFamily(familyID string) error {
+	conn := Pool.Get()
+	defer conn.Close()
+
+	// no refresh token of the family outlives a freshly rotated one
+	ttl := int64(timeRefreshToken) * 3600
+	if _, err := conn.Do("SET", revokedFamilyKey(familyID), 1, "EX", ttl); err != nil {
+		return err
+	}
+
+	fields, err := redis.StringMap(conn.Do("HGETALL", tokenFamilyKey(familyID)))
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	refreshUUID := fields["refresh_uuid"]
+	if _, err := conn.Do("DEL", tokenFamilyKey(familyID), refreshUUID, accessUUIDOf(refreshUUID)); err != nil {
+		return err
+	}
+
+	if userID, err := strconv.ParseInt(fields["user_id"], 10, 64); err == nil {
+		if _, err := conn.Do("SREM", userSessionsKey(userID), familyID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isFamilyRevoked reports whether RevokeTokenFamily ran for the family
+func isFamilyRevoked(conn redis.Conn, familyID string) (bool, error) {
+	return redis.Bool(conn.Do("EXISTS", revokedFamilyKey(familyID)))
+}
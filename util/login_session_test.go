@@ -0,0 +1,99 @@
+package util
+
+import (
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+)
+
+func TestSaveToRedisSetsExpirations(t *testing.T) {
+	withTokenSecrets(t)
+	store := withFakeRedis(t)
+	td := login(t, model.MUser{ID: 2, UserName: "budi"})
+
+	if ttl := store.expiry(td.AccessUUID); ttl < 3590 || ttl > 3600 {
+		t.Errorf("access token TTL = %d, want about 3600", ttl)
+	}
+	if ttl := store.expiry(td.RefreshUUID); ttl < 10790 || ttl > 10800 {
+		t.Errorf("refresh token TTL = %d, want about 10800", ttl)
+	}
+	if store.expiry(tokenFamilyKey(td.FamilyID)) != store.expiry(td.RefreshUUID) {
+		t.Error("the family does not expire with its refresh token")
+	}
+	if store.expiry(userSessionsKey(2)) == 0 {
+		t.Error("the session index has no expiration")
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	withTokenSecrets(t)
+	withFakeRedis(t)
+	u := model.MUser{ID: 2, UserName: "budi"}
+	first := login(t, u)
+	second := login(t, u)
+	if _, err := refresh(u, first.RefreshToken); err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	login(t, model.MUser{ID: 3, UserName: "anduk"})
+
+	sessions, err := ListSessions(2, second.FamilyID)
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(sessions))
+	}
+	for _, s := range sessions {
+		if s.IP != "203.0.113.7" || s.UserAgent != "test" || s.CreatedAt.IsZero() {
+			t.Errorf("session %+v does not keep the client of its login", s)
+		}
+		if s.Current != (s.ID == second.FamilyID) {
+			t.Errorf("session %s: current = %v", s.ID, s.Current)
+		}
+	}
+}
+
+func TestRevokeUserSession(t *testing.T) {
+	withTokenSecrets(t)
+	store := withFakeRedis(t)
+	budi := login(t, model.MUser{ID: 2, UserName: "budi"})
+	anduk := login(t, model.MUser{ID: 3, UserName: "anduk"})
+
+	if err := RevokeUserSession(2, anduk.FamilyID); err != ErrLoginSessionNotFound {
+		t.Errorf("another user's session: got %v, want ErrLoginSessionNotFound", err)
+	}
+	if err := RevokeUserSession(2, budi.FamilyID); err != nil {
+		t.Fatalf("RevokeUserSession failed: %v", err)
+	}
+
+	if store.has(budi.AccessUUID) || store.has(budi.RefreshUUID) {
+		t.Error("the revoked session's tokens are still stored")
+	}
+	if sessions, _ := ListSessions(2, ""); len(sessions) != 0 {
+		t.Errorf("got %d sessions after revoking, want 0", len(sessions))
+	}
+	if !store.has(anduk.AccessUUID) {
+		t.Error("another user's session was revoked")
+	}
+}
+
+func TestRevokeUserSessions(t *testing.T) {
+	withTokenSecrets(t)
+	store := withFakeRedis(t)
+	u := model.MUser{ID: 2, UserName: "budi"}
+	first := login(t, u)
+	second := login(t, u)
+
+	if err := RevokeUserSessions(2); err != nil {
+		t.Fatalf("RevokeUserSessions failed: %v", err)
+	}
+
+	for _, td := range []*TokenDetails{first, second} {
+		if store.has(td.AccessUUID) || store.has(td.RefreshUUID) || store.has(tokenFamilyKey(td.FamilyID)) {
+			t.Errorf("session %s survived", td.FamilyID)
+		}
+	}
+	if store.has(userSessionsKey(2)) {
+		t.Error("the session index survived")
+	}
+}
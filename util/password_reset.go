@@ -0,0 +1,106 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrInvalidResetToken is returned for a password reset token that is
+// unknown, expired or already used.
+var ErrInvalidResetToken = errors.New("password reset token is invalid or expired")
+
+// PasswordResetToken is a freshly issued password reset token. Only its hash
+// is stored, so the token itself exists only in the email sent to the user.
+type PasswordResetToken struct {
+	Token     string
+	UserID    int64
+	ExpiresAt time.Time
+}
+
+func passwordResetKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "password-reset:" + hex.EncodeToString(sum[:])
+}
+
+// userPasswordResetKey points at the latest reset token of a user, so that
+// issuing a new one voids the previous link
+func userPasswordResetKey(userID int64) string {
+	return "password-reset-user:" + strconv.FormatInt(userID, 10)
+}
+
+// IssuePasswordResetToken creates a single-use reset token for a user that
+// expires after ttl. Any earlier token of the user stops working.
+func IssuePasswordResetToken(userID int64, ttl time.Duration) (PasswordResetToken, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return PasswordResetToken{}, err
+	}
+	rt := PasswordResetToken{
+		Token:     base64.RawURLEncoding.EncodeToString(raw),
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	conn := Pool.Get()
+	defer conn.Close()
+
+	previous, err := redis.String(conn.Do("GET", userPasswordResetKey(userID)))
+	if err != nil && !errors.Is(err, redis.ErrNil) {
+		return PasswordResetToken{}, err
+	}
+	if previous != "" {
+		if _, err := conn.Do("DEL", previous); err != nil {
+			return PasswordResetToken{}, err
+		}
+	}
+
+	key := passwordResetKey(rt.Token)
+	if _, err := conn.Do("SET", key, userID, "EX", ttlSeconds(ttl)); err != nil {
+		return PasswordResetToken{}, err
+	}
+	if _, err := conn.Do("SET", userPasswordResetKey(userID), key, "EX", ttlSeconds(ttl)); err != nil {
+		return PasswordResetToken{}, err
+	}
+	return rt, nil
+}
+
+// ConsumePasswordResetToken returns the user a reset token was issued to and
+// deletes the token, so it works exactly once
+func ConsumePasswordResetToken(token string) (int64, error) {
+	if token == "" {
+		return 0, ErrInvalidResetToken
+	}
+
+	conn := Pool.Get()
+	defer conn.Close()
+
+	key := passwordResetKey(token)
+	userID, err := redis.Int64(conn.Do("GET", key))
+	if errors.Is(err, redis.ErrNil) {
+		return 0, ErrInvalidResetToken
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// a concurrent reset with the same token loses the DEL
+	deleted, err := redis.Int(conn.Do("DEL", key))
+	if err != nil {
+		return 0, err
+	}
+	if deleted == 0 {
+		return 0, ErrInvalidResetToken
+	}
+
+	if _, err := conn.Do("DEL", userPasswordResetKey(userID)); err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
@@ -0,0 +1,76 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/shravanasati/scopex-go-assignment/model"
+)
+
+const passwordResetEmailTemplateHTML = `
+<!DOCTYPE html>
+<html>
+<head>
+    <style>
+        body { font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; line-height: 1.6; color: #333; background-color: #f4f4f4; margin: 0; padding: 0; }
+        .container { max-width: 600px; margin: 20px auto; padding: 20px; background-color: #ffffff; border-radius: 8px; box-shadow: 0 4px 8px rgba(0,0,0,0.1); }
+        .header { text-align: center; padding-bottom: 20px; border-bottom: 2px solid #eee; margin-bottom: 20px; }
+        .header h2 { color: #2c3e50; margin: 0; }
+        .content { padding: 0 10px; }
+        .action { text-align: center; margin: 30px 0; }
+        .button { display: inline-block; padding: 10px 24px; background-color: #2c3e50; color: #ffffff; border-radius: 6px; text-decoration: none; }
+        .token { font-family: monospace; word-break: break-all; background-color: #f9f9f9; padding: 10px; border-radius: 6px; }
+        .footer { margin-top: 30px; text-align: center; font-size: 12px; color: #aaa; border-top: 1px solid #eee; padding-top: 10px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h2>Password Reset</h2>
+        </div>
+        <div class="content">
+            <p>Hello {{.UserName}},</p>
+            <p>A password reset was requested for your account. It can be used once, until {{.ExpiresAt}}.</p>
+            {{if .Link}}
+            <div class="action">
+                <a class="button" href="{{.Link}}">Reset password</a>
+            </div>
+            {{else}}
+            <p>Your reset token:</p>
+            <p class="token">{{.Token}}</p>
+            {{end}}
+            <p>If you did not ask for this, you can ignore this email; your password stays unchanged.</p>
+        </div>
+        <div class="footer">
+            <p>Generated by ScopeX Attendance System</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+// SendPasswordResetEmail sends a reset token to the email of a user. With an
+// empty link the bare token is included instead.
+func SendPasswordResetEmail(user model.MUser, reset PasswordResetToken, link string) {
+	tmpl, err := template.New("password-reset").Parse(passwordResetEmailTemplateHTML)
+	if err != nil {
+		fmt.Printf("failed to parse password reset email template: %v\n", err)
+		return
+	}
+
+	var body bytes.Buffer
+	data := struct {
+		UserName  string
+		Link      string
+		Token     string
+		ExpiresAt string
+	}{user.UserName, link, reset.Token, reset.ExpiresAt.UTC().Format(time.RFC1123)}
+	if err := tmpl.Execute(&body, data); err != nil {
+		fmt.Printf("failed to execute password reset email template: %v\n", err)
+		return
+	}
+
+	deliverEmail("Reset your password", body.String(), user.Email)
+}
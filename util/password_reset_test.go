@@ -0,0 +1,54 @@
+package util
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPasswordResetTokenIsSingleUse(t *testing.T) {
+	store := withFakeRedis(t)
+
+	rt, err := IssuePasswordResetToken(2, 30*time.Minute)
+	if err != nil {
+		t.Fatalf("IssuePasswordResetToken failed: %v", err)
+	}
+	if store.has(rt.Token) || store.has("password-reset:"+rt.Token) {
+		t.Error("the raw token is stored in Redis")
+	}
+	if ttl := store.expiry(passwordResetKey(rt.Token)); ttl != 1800 {
+		t.Errorf("token TTL = %d, want 1800", ttl)
+	}
+
+	userID, err := ConsumePasswordResetToken(rt.Token)
+	if err != nil || userID != 2 {
+		t.Fatalf("ConsumePasswordResetToken = %d, %v; want 2", userID, err)
+	}
+	if _, err := ConsumePasswordResetToken(rt.Token); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("second use: err = %v, want ErrInvalidResetToken", err)
+	}
+	if store.has(userPasswordResetKey(2)) {
+		t.Error("the user's reset pointer was left behind")
+	}
+}
+
+func TestNewPasswordResetTokenVoidsPrevious(t *testing.T) {
+	withFakeRedis(t)
+
+	first, _ := IssuePasswordResetToken(2, time.Minute)
+	second, _ := IssuePasswordResetToken(2, time.Minute)
+	other, _ := IssuePasswordResetToken(3, time.Minute)
+
+	if _, err := ConsumePasswordResetToken(first.Token); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("superseded token: err = %v, want ErrInvalidResetToken", err)
+	}
+	if userID, err := ConsumePasswordResetToken(second.Token); err != nil || userID != 2 {
+		t.Errorf("latest token = %d, %v; want 2", userID, err)
+	}
+	if userID, err := ConsumePasswordResetToken(other.Token); err != nil || userID != 3 {
+		t.Errorf("another user's token = %d, %v; want 3", userID, err)
+	}
+	if _, err := ConsumePasswordResetToken("made-up"); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("unknown token: err = %v, want ErrInvalidResetToken", err)
+	}
+}
@@ -0,0 +1,199 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// fakeRedis is an in-memory stand-in for the Redis commands the token and
+// session helpers use. Expirations are recorded in ttl but never fire.
+type fakeRedis struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	hashes map[string]map[string][]byte
+	sets   map[string]map[string]bool
+	ttl    map[string]int64
+}
+
+type fakeRedisConn struct {
+	store *fakeRedis
+}
+
+// withFakeRedis points Pool at a fresh fakeRedis for the test
+func withFakeRedis(t *testing.T) *fakeRedis {
+	store := &fakeRedis{
+		values: map[string][]byte{},
+		hashes: map[string]map[string][]byte{},
+		sets:   map[string]map[string]bool{},
+		ttl:    map[string]int64{},
+	}
+	original := Pool
+	Pool = &redis.Pool{Dial: func() (redis.Conn, error) { return &fakeRedisConn{store: store}, nil }}
+	t.Cleanup(func() { Pool = original })
+	return store
+}
+
+func (f *fakeRedis) has(key string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.exists(key)
+}
+
+func (f *fakeRedis) expiry(key string) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ttl[key]
+}
+
+func (f *fakeRedis) exists(key string) bool {
+	_, isValue := f.values[key]
+	_, isHash := f.hashes[key]
+	_, isSet := f.sets[key]
+	return isValue || isHash || isSet
+}
+
+func (f *fakeRedis) del(key string) bool {
+	existed := f.exists(key)
+	delete(f.values, key)
+	delete(f.hashes, key)
+	delete(f.sets, key)
+	delete(f.ttl, key)
+	return existed
+}
+
+func (c *fakeRedisConn) Close() error                      { return nil }
+func (c *fakeRedisConn) Err() error                        { return nil }
+func (c *fakeRedisConn) Send(string, ...interface{}) error { return nil }
+func (c *fakeRedisConn) Flush() error                      { return nil }
+func (c *fakeRedisConn) Receive() (interface{}, error)     { return nil, nil }
+
+func (c *fakeRedisConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	s := c.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := make([]string, len(args))
+	for i, arg := range args {
+		a[i] = fmt.Sprint(arg)
+	}
+
+	switch strings.ToUpper(cmd) {
+	case "GET":
+		if v, ok := s.values[a[0]]; ok {
+			return v, nil
+		}
+		return nil, nil
+	case "SET":
+		delete(s.ttl, a[0])
+		for i := 2; i < len(a); i++ {
+			switch strings.ToUpper(a[i]) {
+			case "NX":
+				if s.exists(a[0]) {
+					return nil, nil
+				}
+			case "EX":
+				i++
+				s.ttl[a[0]], _ = strconv.ParseInt(a[i], 10, 64)
+			}
+		}
+		s.values[a[0]] = []byte(a[1])
+		return "OK", nil
+	case "DEL":
+		var n int64
+		for _, key := range a {
+			if s.del(key) {
+				n++
+			}
+		}
+		return n, nil
+	case "EXISTS":
+		var n int64
+		for _, key := range a {
+			if s.exists(key) {
+				n++
+			}
+		}
+		return n, nil
+	case "INCR":
+		n, _ := strconv.ParseInt(string(s.values[a[0]]), 10, 64)
+		n++
+		s.values[a[0]] = []byte(strconv.FormatInt(n, 10))
+		return n, nil
+	case "TTL":
+		if !s.exists(a[0]) {
+			return int64(-2), nil
+		}
+		if ttl, ok := s.ttl[a[0]]; ok {
+			return ttl, nil
+		}
+		return int64(-1), nil
+	case "EXPIRE":
+		if !s.exists(a[0]) {
+			return int64(0), nil
+		}
+		s.ttl[a[0]], _ = strconv.ParseInt(a[1], 10, 64)
+		return int64(1), nil
+	case "HSET", "HSETNX":
+		h := s.hashes[a[0]]
+		if h == nil {
+			h = map[string][]byte{}
+			s.hashes[a[0]] = h
+		}
+		var n int64
+		for i := 1; i+1 < len(a); i += 2 {
+			if _, ok := h[a[i]]; ok && strings.ToUpper(cmd) == "HSETNX" {
+				continue
+			}
+			h[a[i]] = []byte(a[i+1])
+			n++
+		}
+		return n, nil
+	case "HGET":
+		if v, ok := s.hashes[a[0]][a[1]]; ok {
+			return v, nil
+		}
+		return nil, nil
+	case "HGETALL":
+		reply := []interface{}{}
+		for field, v := range s.hashes[a[0]] {
+			reply = append(reply, []byte(field), v)
+		}
+		return reply, nil
+	case "SADD":
+		set := s.sets[a[0]]
+		if set == nil {
+			set = map[string]bool{}
+			s.sets[a[0]] = set
+		}
+		for _, m := range a[1:] {
+			set[m] = true
+		}
+		return int64(len(a) - 1), nil
+	case "SREM":
+		for _, m := range a[1:] {
+			delete(s.sets[a[0]], m)
+		}
+		if len(s.sets[a[0]]) == 0 {
+			s.del(a[0])
+		}
+		return int64(len(a) - 1), nil
+	case "SMEMBERS":
+		members := []string{}
+		for m := range s.sets[a[0]] {
+			members = append(members, m)
+		}
+		sort.Strings(members)
+		reply := make([]interface{}, len(members))
+		for i, m := range members {
+			reply[i] = []byte(m)
+		}
+		return reply, nil
+	}
+	return nil, fmt.Errorf("fakeRedis: unsupported command %s", cmd)
+}
@@ -0,0 +1,143 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gomodule/redigo/redis"
+)
+
+// Errors returned when a refresh token cannot be exchanged
+var (
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used, the session has been revoked")
+)
+
+// RefreshDetails are the claims of a verified refresh token
+type RefreshDetails struct {
+	RefreshUUID string
+	FamilyID    string
+	UserID      int64
+	ExpiresAt   int64
+}
+
+func usedRefreshKey(refreshUUID string) string {
+	return "refresh-used:" + refreshUUID
+}
+
+// accessUUIDOf returns the access UUID paired with a refresh UUID, which is
+// built as "<access uuid>++<user id>" by CreateToken
+func accessUUIDOf(refreshUUID string) string {
+	accessUUID, _, _ := strings.Cut(refreshUUID, "++")
+	return accessUUID
+}
+
+// VerifyRefreshToken checks the signature and expiry of a refresh token
+// against JWT.REFRESH_SECRET and reads its claims
+func VerifyRefreshToken(tokenStr string) (*RefreshDetails, error) {
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(refreshSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidRefreshToken
+	}
+	refreshUUID, _ := claims["refresh_uuid"].(string)
+	familyID, _ := claims["family_id"].(string)
+	userID, _ := claims["user_id"].(float64)
+	exp, _ := claims["exp"].(float64)
+	if refreshUUID == "" || familyID == "" || userID == 0 {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return &RefreshDetails{
+		RefreshUUID: refreshUUID,
+		FamilyID:    familyID,
+		UserID:      int64(userID),
+		ExpiresAt:   int64(exp),
+	}, nil
+}
+
+// ConsumeRefreshToken revokes the token pair of a verified refresh token so
+// that it is exchanged exactly once. A token that was already exchanged is a
+// sign of theft: its whole family is revoked and ErrRefreshTokenReused is
+// returned. Unknown (logged out or revoked) tokens give ErrInvalidRefreshToken.
+func ConsumeRefreshToken(rd *RefreshDetails) error {
+	conn := Pool.Get()
+	defer conn.Close()
+
+	storedUserID, err := redis.Int64(conn.Do("GET", rd.RefreshUUID))
+	if errors.Is(err, redis.ErrNil) {
+		used, err := redis.Bool(conn.Do("EXISTS", usedRefreshKey(rd.RefreshUUID)))
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidRefreshToken
+		}
+		if err := RevokeTokenFamily(rd.FamilyID); err != nil {
+			return err
+		}
+		return ErrRefreshTokenReused
+	}
+	if err != nil {
+		return err
+	}
+	if storedUserID != rd.UserID {
+		return ErrInvalidRefreshToken
+	}
+
+	// mark the token used before revoking it, so a concurrent exchange that
+	// loses the DEL below is seen as reuse
+	ttl := ttlUntil(rd.ExpiresAt, time.Now().Unix())
+	if _, err := conn.Do("SET", usedRefreshKey(rd.RefreshUUID), rd.FamilyID, "EX", ttl); err != nil {
+		return err
+	}
+
+	deleted, err := redis.Int(conn.Do("DEL", rd.RefreshUUID))
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		if err := RevokeTokenFamily(rd.FamilyID); err != nil {
+			return err
+		}
+		return ErrRefreshTokenReused
+	}
+
+	_, err = conn.Do("DEL", accessUUIDOf(rd.RefreshUUID))
+	return err
+}
+
+// RotateToken signs the pair that replaces a consumed refresh token. It
+// stays in the token family of the consumed one, and a family revoked since
+// the token was consumed gives ErrTokenFamilyRevoked.
+func RotateToken(rd *RefreshDetails, u model.MUser, grants model.Grants) (*TokenDetails, error) {
+	if u.ID != rd.UserID {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	conn := Pool.Get()
+	revoked, err := isFamilyRevoked(conn, rd.FamilyID)
+	conn.Close()
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrTokenFamilyRevoked
+	}
+
+	return createToken(u, grants, rd.FamilyID)
+}
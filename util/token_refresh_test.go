@@ -0,0 +1,165 @@
+package util
+
+import (
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+)
+
+func withTokenSecrets(t *testing.T) {
+	originalAccess, originalRefresh := accessSecret, refreshSecret
+	accessSecret, refreshSecret = "access-test", "refresh-test"
+	t.Cleanup(func() { accessSecret, refreshSecret = originalAccess, originalRefresh })
+}
+
+// login issues and stores a token pair like the login handler does
+func login(t *testing.T, u model.MUser) *TokenDetails {
+	td, err := CreateToken(u, model.Grants{})
+	if err != nil {
+		t.Fatalf("CreateToken failed: %v", err)
+	}
+	if err := SaveToRedis(u.ID, td, ClientInfo{IP: "203.0.113.7", UserAgent: "test"}); err != nil {
+		t.Fatalf("SaveToRedis failed: %v", err)
+	}
+	return td
+}
+
+// refresh exchanges a refresh token like the refresh handler does
+func refresh(u model.MUser, refreshToken string) (*TokenDetails, error) {
+	rd, err := VerifyRefreshToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := ConsumeRefreshToken(rd); err != nil {
+		return nil, err
+	}
+	td, err := RotateToken(rd, u, model.Grants{})
+	if err != nil {
+		return nil, err
+	}
+	return td, SaveToRedis(u.ID, td, ClientInfo{IP: "198.51.100.1", UserAgent: "other"})
+}
+
+func TestVerifyRefreshTokenRejectsAccessToken(t *testing.T) {
+	withTokenSecrets(t)
+	td, _ := CreateToken(model.MUser{ID: 2, UserName: "budi"}, model.Grants{})
+
+	if _, err := VerifyRefreshToken(td.AccessToken); err != ErrInvalidRefreshToken {
+		t.Errorf("access token: got %v, want ErrInvalidRefreshToken", err)
+	}
+
+	rd, err := VerifyRefreshToken(td.RefreshToken)
+	if err != nil {
+		t.Fatalf("VerifyRefreshToken failed: %v", err)
+	}
+	if rd.RefreshUUID != td.RefreshUUID || rd.FamilyID != td.FamilyID || rd.UserID != 2 {
+		t.Errorf("VerifyRefreshToken returned %+v, want the claims of %+v", rd, td)
+	}
+}
+
+func TestRefreshRotatesPair(t *testing.T) {
+	withTokenSecrets(t)
+	store := withFakeRedis(t)
+	u := model.MUser{ID: 2, UserName: "budi"}
+	first := login(t, u)
+
+	second, err := refresh(u, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+
+	if store.has(first.AccessUUID) || store.has(first.RefreshUUID) {
+		t.Error("the old pair is still stored")
+	}
+	if !store.has(second.AccessUUID) || !store.has(second.RefreshUUID) {
+		t.Error("the new pair is not stored")
+	}
+	if second.FamilyID != first.FamilyID {
+		t.Errorf("family changed from %s to %s", first.FamilyID, second.FamilyID)
+	}
+	if got := string(store.hashes[tokenFamilyKey(first.FamilyID)]["refresh_uuid"]); got != second.RefreshUUID {
+		t.Errorf("family points at %s, want %s", got, second.RefreshUUID)
+	}
+}
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	withTokenSecrets(t)
+	store := withFakeRedis(t)
+	u := model.MUser{ID: 2, UserName: "budi"}
+	first := login(t, u)
+	other := login(t, u)
+	second, err := refresh(u, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+
+	if _, err := refresh(u, first.RefreshToken); err != ErrRefreshTokenReused {
+		t.Fatalf("reused token: got %v, want ErrRefreshTokenReused", err)
+	}
+	if store.has(second.AccessUUID) || store.has(second.RefreshUUID) {
+		t.Error("the family's live pair survived the reuse")
+	}
+	if !store.has(other.AccessUUID) {
+		t.Error("another login of the user was revoked")
+	}
+
+	if _, err := refresh(u, second.RefreshToken); err != ErrInvalidRefreshToken {
+		t.Errorf("revoked family: got %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestRefreshAfterLogout(t *testing.T) {
+	withTokenSecrets(t)
+	withFakeRedis(t)
+	u := model.MUser{ID: 2, UserName: "budi"}
+	td := login(t, u)
+
+	if err := DeleteToken(&AccessDetails{AccessUUID: td.AccessUUID, FamilyID: td.FamilyID, UserID: u.ID}); err != nil {
+		t.Fatalf("DeleteToken failed: %v", err)
+	}
+
+	if _, err := refresh(u, td.RefreshToken); err != ErrInvalidRefreshToken {
+		t.Errorf("logged out token: got %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestRefreshRevokedBetweenConsumeAndSave(t *testing.T) {
+	withTokenSecrets(t)
+	store := withFakeRedis(t)
+	u := model.MUser{ID: 2, UserName: "budi"}
+	first := login(t, u)
+
+	// the legitimate exchange consumes the token and signs the new pair ...
+	rd, err := VerifyRefreshToken(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("VerifyRefreshToken failed: %v", err)
+	}
+	if err := ConsumeRefreshToken(rd); err != nil {
+		t.Fatalf("ConsumeRefreshToken failed: %v", err)
+	}
+	td, err := RotateToken(rd, u, model.Grants{})
+	if err != nil {
+		t.Fatalf("RotateToken failed: %v", err)
+	}
+
+	// ... when a concurrent exchange of the same token revokes the family
+	if err := ConsumeRefreshToken(rd); err != ErrRefreshTokenReused {
+		t.Fatalf("concurrent exchange: got %v, want ErrRefreshTokenReused", err)
+	}
+
+	if err := SaveToRedis(u.ID, td, ClientInfo{IP: "198.51.100.1", UserAgent: "other"}); err != ErrTokenFamilyRevoked {
+		t.Fatalf("SaveToRedis: got %v, want ErrTokenFamilyRevoked", err)
+	}
+	if store.has(td.AccessUUID) || store.has(td.RefreshUUID) || store.has(tokenFamilyKey(td.FamilyID)) {
+		t.Error("the pair rotated from the reused token survived the revocation")
+	}
+	if store.sets[userSessionsKey(u.ID)][td.FamilyID] {
+		t.Error("the revoked family is still listed in the session index")
+	}
+	if _, err := refresh(u, td.RefreshToken); err != ErrInvalidRefreshToken {
+		t.Errorf("rotated token: got %v, want ErrInvalidRefreshToken", err)
+	}
+	if _, err := RotateToken(rd, u, model.Grants{}); err != ErrTokenFamilyRevoked {
+		t.Errorf("RotateToken after revocation: got %v, want ErrTokenFamilyRevoked", err)
+	}
+}
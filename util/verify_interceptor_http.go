@@ -2,11 +2,26 @@ package util
 
 import (
 	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Context keys under which TokenAuthMiddleware stores the caller's grants
+// and the login session of their token
+const (
+	ContextRoles       = "roles"
+	ContextPermissions = "permissions"
+	ContextSessionID   = "sessionId"
+)
+
+// PermissionDenied is the body of the 403 answered by RequirePermission
+type PermissionDenied struct {
+	Message    string `json:"message" example:"Permission denied"`
+	Permission string `json:"permission" example:"students:delete"`
+}
+
 // TokenAuthMiddleware ...
 func TokenAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -24,6 +39,9 @@ func TokenAuthMiddleware() gin.HandlerFunc {
 
 		s := strconv.FormatInt(accessDetails.UserID, 10)
 		c.Request.Header.Set("userId", s)
+		c.Set(ContextRoles, accessDetails.Roles)
+		c.Set(ContextPermissions, accessDetails.Permissions)
+		c.Set(ContextSessionID, accessDetails.FamilyID)
 		//c.Request.Header.Set("hellowWrold", s)
 		//How to access this userId var from request in services
 		//c *gin.Context
@@ -31,3 +49,26 @@ func TokenAuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequirePermission answers 403 unless the token checked by
+// TokenAuthMiddleware grants permission. Register it after that middleware.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasPermission(c, permission) {
+			c.JSON(http.StatusForbidden, PermissionDenied{Message: "Permission denied", Permission: permission})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// HasPermission reports whether the caller's token grants permission
+func HasPermission(c *gin.Context, permission string) bool {
+	return slices.Contains(c.GetStringSlice(ContextPermissions), permission)
+}
+
+// HasRole reports whether the caller's token carries role
+func HasRole(c *gin.Context, role string) bool {
+	return slices.Contains(c.GetStringSlice(ContextRoles), role)
+}
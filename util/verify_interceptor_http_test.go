@@ -0,0 +1,72 @@
+package util
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	model "github.com/shravanasati/scopex-go-assignment/model"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/students", func(c *gin.Context) {
+		c.Set(ContextPermissions, []string{model.PermStudentsRead})
+		c.Next()
+	}, RequirePermission(model.PermStudentsRead), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.DELETE("/students", func(c *gin.Context) {
+		c.Set(ContextPermissions, []string{model.PermStudentsRead})
+		c.Next()
+	}, RequirePermission(model.PermStudentsDelete), func(c *gin.Context) {
+		t.Error("handler ran without the permission")
+	})
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/students", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("granted permission: got %d, want 200", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/students", nil))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("missing permission: got %d, want 403", rr.Code)
+	}
+	var body PermissionDenied
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil || body.Permission != model.PermStudentsDelete {
+		t.Errorf("403 body = %s, want the missing permission", rr.Body.String())
+	}
+}
+
+func TestCreateTokenCarriesGrants(t *testing.T) {
+	grants := model.Grants{Roles: []string{model.RoleTeacher}, Permissions: []string{model.PermAttendanceMark, model.PermStudentsRead}}
+	td, err := CreateToken(model.MUser{ID: 2, UserName: "budi"}, grants)
+	if err != nil {
+		t.Fatalf("CreateToken failed: %v", err)
+	}
+
+	token, err := VerifyToken(nil, td.AccessToken)
+	if err != nil {
+		t.Fatalf("VerifyToken failed: %v", err)
+	}
+	claims := token.Claims.(jwt.MapClaims)
+
+	roles := claimStrings(claims, "roles")
+	if len(roles) != 1 || roles[0] != model.RoleTeacher {
+		t.Errorf("roles claim = %v, want %v", roles, grants.Roles)
+	}
+	permissions := claimStrings(claims, "permissions")
+	if len(permissions) != 2 || permissions[1] != model.PermStudentsRead {
+		t.Errorf("permissions claim = %v, want %v", permissions, grants.Permissions)
+	}
+	if missing := claimStrings(claims, "scopes"); len(missing) != 0 {
+		t.Errorf("missing claim = %v, want empty", missing)
+	}
+}